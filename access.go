@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// AccessFormat selects the line format of an AccessLogger.
+type AccessFormat int
+
+// Access log formats.
+const (
+	// AccessJSON writes one JSON object per request with standardized
+	// fields: time, remote_ip, method, path, query, proto, status, bytes,
+	// duration_ms, referer, user_agent and, if present, request_id.
+	AccessJSON AccessFormat = iota
+	// AccessCommon writes the NCSA Common Log Format.
+	AccessCommon
+	// AccessCombined writes the Combined Log Format: Common plus the
+	// referer and user agent.
+	AccessCombined
+)
+
+// AccessLogger writes one line per HTTP request to a stream of its own,
+// separate from application logs, so access logs can be shipped and parsed
+// on their own. Use its Middleware to record requests.
+type AccessLogger struct {
+	// Writer receives the lines.
+	Writer io.Writer
+
+	// Format selects the line format. Defaults to AccessJSON.
+	Format AccessFormat
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// NewAccessLogger returns an AccessLogger writing lines in format to w.
+func NewAccessLogger(w io.Writer, format AccessFormat) *AccessLogger {
+	return &AccessLogger{Writer: w, Format: format}
+}
+
+// NewAccessLogFile returns an AccessLogger writing lines in format to the
+// file at path, rotated as described by rotation. Missing directories are
+// created.
+func NewAccessLogFile(path string, rotation RotationConfig, format AccessFormat) *AccessLogger {
+	return NewAccessLogger(&log.FileWriter{
+		Filename:     path,
+		MaxSize:      rotation.MaxSize,
+		MaxBackups:   rotation.MaxBackups,
+		LocalTime:    rotation.LocalTime,
+		EnsureFolder: true,
+	}, format)
+}
+
+// Middleware returns net/http middleware writing an access line for each
+// request once it has been served.
+func (a *AccessLogger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		_ = a.Log(r, status, rec.bytes, start, time.Since(start))
+	})
+}
+
+// accessRecord is the JSON form of an access line.
+type accessRecord struct {
+	Time       string  `json:"time"`
+	RemoteIP   string  `json:"remote_ip"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Query      string  `json:"query,omitempty"`
+	Proto      string  `json:"proto"`
+	Status     int     `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	DurationMS float64 `json:"duration_ms"`
+	Referer    string  `json:"referer,omitempty"`
+	UserAgent  string  `json:"user_agent,omitempty"`
+	RequestID  string  `json:"request_id,omitempty"`
+}
+
+// Log writes the access line of a request that started at start, took d
+// and was answered with status and size bytes, for servers that do not
+// use Middleware.
+func (a *AccessLogger) Log(r *http.Request, status int, size int64, start time.Time, d time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := a.buf[:0]
+	switch a.Format {
+	case AccessCommon, AccessCombined:
+		b = appendCLF(b, r, status, size, start)
+		if a.Format == AccessCombined {
+			b = append(b, ' ')
+			b = strconv.AppendQuote(b, r.Referer())
+			b = append(b, ' ')
+			b = strconv.AppendQuote(b, r.UserAgent())
+		}
+	default:
+		data, err := json.Marshal(accessRecord{
+			Time:       start.Format(time.RFC3339Nano),
+			RemoteIP:   remoteIP(r),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Query:      r.URL.RawQuery,
+			Proto:      r.Proto,
+			Status:     status,
+			Bytes:      size,
+			DurationMS: float64(d) / float64(time.Millisecond),
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+			RequestID:  r.Header.Get("X-Request-ID"),
+		})
+		if err != nil {
+			return err
+		}
+		b = append(b, data...)
+	}
+	b = append(b, '\n')
+	a.buf = b
+	_, err := a.Writer.Write(b)
+	return err
+}
+
+// appendCLF appends a Common Log Format line without the trailing newline.
+func appendCLF(b []byte, r *http.Request, status int, size int64, start time.Time) []byte {
+	user := "-"
+	if u := r.URL.User; u != nil && u.Username() != "" {
+		user = u.Username()
+	} else if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	}
+	b = append(b, remoteIP(r)...)
+	b = append(b, " - "...)
+	b = append(b, user...)
+	b = append(b, " ["...)
+	b = start.AppendFormat(b, "02/Jan/2006:15:04:05 -0700")
+	b = append(b, "] "...)
+	b = strconv.AppendQuote(b, fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), r.Proto))
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(status), 10)
+	b = append(b, ' ')
+	if size == 0 {
+		return append(b, '-')
+	}
+	return strconv.AppendInt(b, size, 10)
+}
+
+// Close closes Writer if it is closable.
+func (a *AccessLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return closeWriter(a.Writer)
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func serveAccess(t *testing.T, a *AccessLogger) {
+	t.Helper()
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/index.html?lang=en", nil)
+	req.RemoteAddr = "192.0.2.1:4711"
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	req.Header.Set("Referer", "https://example.com/")
+	req.SetBasicAuth("frank", "secret")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestAccessLoggerCombined(t *testing.T) {
+	var buf bytes.Buffer
+	serveAccess(t, NewAccessLogger(&buf, AccessCombined))
+	re := regexp.MustCompile(`^192\.0\.2\.1 - frank \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /index.html\?lang=en HTTP/1.1" 200 5 "https://example.com/" "Mozilla/5.0"\n$`)
+	if !re.Match(buf.Bytes()) {
+		t.Errorf("Unexpected combined line: %q", buf.Bytes())
+	}
+}
+
+func TestAccessLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	serveAccess(t, NewAccessLogger(&buf, AccessJSON))
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("Failed to parse line: %v", err)
+	}
+	want := map[string]any{"remote_ip": "192.0.2.1", "method": "GET", "path": "/index.html", "query": "lang=en", "status": float64(200), "bytes": float64(5)}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, rec[k])
+		}
+	}
+	if _, ok := rec["level"]; ok {
+		t.Error("Access lines should not carry application log fields")
+	}
+}
+
+func TestAccessLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "access.log")
+	a := NewAccessLogFile(path, RotationConfig{MaxSize: 1 << 20}, AccessCommon)
+	serveAccess(t, a)
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read access log: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"GET /index.html?lang=en HTTP/1.1" 200 5`)) {
+		t.Errorf("Unexpected access log: %q", data)
+	}
+}
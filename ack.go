@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrAckTimeout is returned when a collector does not acknowledge a batch
+// in time. The batch is treated as undelivered and sent again.
+var ErrAckTimeout = errors.New("logging: acknowledgment timed out")
+
+// AckSender is implemented by sinks whose collector acknowledges batches
+// asynchronously, such as Fluentd's forward protocol with
+// require_ack_response or a Kafka producer with acks=all.
+type AckSender interface {
+	// SendBatch sends entries and returns a channel receiving nil once the
+	// collector acknowledged them, or the reason it refused them. The
+	// entries are only valid until SendBatch returns.
+	SendBatch(entries [][]byte) (<-chan error, error)
+}
+
+// AckSink is a BatchSink that reports a batch as written only once Sender's
+// collector acknowledged it. Behind a SpoolWriter, which removes entries
+// only after a successful write and sends them again otherwise, this gives
+// at-least-once delivery end to end:
+//
+//	spool := logging.NewSpoolWriter(dir, logging.NewAckSink(producer, 10*time.Second))
+//
+// Call the SpoolWriter's Sync as well to survive machine crashes, not only
+// process restarts.
+type AckSink struct {
+	// Sender sends the batches.
+	Sender AckSender
+
+	// Timeout bounds the wait for an acknowledgment. Defaults to 10s.
+	Timeout time.Duration
+
+	timeouts atomic.Uint64
+}
+
+// NewAckSink returns an AckSink sending through sender and waiting up to
+// timeout for each acknowledgment.
+func NewAckSink(sender AckSender, timeout time.Duration) *AckSink {
+	return &AckSink{Sender: sender, Timeout: timeout}
+}
+
+// WriteBatch implements BatchSink. It returns ErrAckTimeout if the
+// acknowledgment does not arrive in time; an acknowledgment arriving later
+// is ignored, so the collector may receive the batch twice.
+func (s *AckSink) WriteBatch(entries [][]byte) error {
+	ack, err := s.Sender.SendBatch(entries)
+	if err != nil {
+		return err
+	}
+	t := time.NewTimer(s.timeout())
+	defer t.Stop()
+	select {
+	case err := <-ack:
+		return err
+	case <-t.C:
+		s.timeouts.Add(1)
+		return ErrAckTimeout
+	}
+}
+
+// Timeouts returns the number of batches whose acknowledgment timed out.
+func (s *AckSink) Timeouts() uint64 {
+	return s.timeouts.Load()
+}
+
+// Close closes Sender if it is closable.
+func (s *AckSink) Close() error {
+	return closeWriter(s.Sender)
+}
+
+func (s *AckSink) timeout() time.Duration {
+	if s.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return s.Timeout
+}
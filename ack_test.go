@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lossySender acknowledges every batch except the first lose ones.
+type lossySender struct {
+	mu    sync.Mutex
+	lose  int
+	sent  [][]string
+	reply error
+}
+
+func (s *lossySender) SendBatch(entries [][]byte) (<-chan error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := make([]string, len(entries))
+	for i, e := range entries {
+		batch[i] = string(e)
+	}
+	s.sent = append(s.sent, batch)
+	ack := make(chan error, 1)
+	if len(s.sent) > s.lose {
+		ack <- s.reply
+	}
+	return ack, nil
+}
+
+func (s *lossySender) batches() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.sent...)
+}
+
+func TestAckSinkWaitsForAck(t *testing.T) {
+	sender := &lossySender{lose: 1}
+	sink := NewAckSink(sender, 10*time.Millisecond)
+	if err := sink.WriteBatch([][]byte{[]byte("a")}); !errors.Is(err, ErrAckTimeout) {
+		t.Errorf("Expected ErrAckTimeout without an ack, got %v", err)
+	}
+	if err := sink.WriteBatch([][]byte{[]byte("a")}); err != nil {
+		t.Errorf("Expected an acknowledged batch to succeed, got %v", err)
+	}
+	sender.reply = errors.New("nack")
+	if err := sink.WriteBatch([][]byte{[]byte("b")}); err == nil || err.Error() != "nack" {
+		t.Errorf("Expected the collector's refusal, got %v", err)
+	}
+	if sink.Timeouts() != 1 {
+		t.Errorf("Expected 1 timeout, got %d", sink.Timeouts())
+	}
+}
+
+func TestSpoolRedeliversUnacknowledgedBatches(t *testing.T) {
+	dir := t.TempDir()
+	sender := &lossySender{lose: 1}
+	logger, _ := testLogger(LogLevelInfo)
+	spool := &SpoolWriter{Dir: dir, Sink: NewAckSink(sender, 10*time.Millisecond), RetryInterval: time.Hour}
+	logger.SetWriter(spool)
+	logger.Info("one")
+	logger.Info("two")
+
+	if err := spool.Flush(); err != nil && !errors.Is(err, ErrAckTimeout) {
+		t.Fatalf("Unexpected Flush error: %v", err)
+	}
+	if err := spool.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	_ = spool.Close()
+
+	var delivered int
+	for _, b := range sender.batches() {
+		delivered += len(b)
+	}
+	if delivered < 3 {
+		t.Errorf("Expected the unacknowledged entries to be sent again, got %v", sender.batches())
+	}
+
+	again := &lossySender{}
+	next := NewSpoolWriter(dir, NewAckSink(again, time.Second))
+	_ = next.Flush()
+	_ = next.Close()
+	if len(again.batches()) != 0 {
+		t.Errorf("Acknowledged entries should not be sent after a restart, got %v", again.batches())
+	}
+}
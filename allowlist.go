@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FieldAllowlist restricts the fields emitted to explicitly allowed keys,
+// for services handling regulated data where logging everything is not
+// acceptable.
+type FieldAllowlist struct {
+	// Keys are the allowed key patterns, matched case-insensitively with
+	// path.Match against top-level keys, so "http_*" covers "http_status".
+	// An allowed key keeps its whole value, nested maps included.
+	Keys []string
+
+	// Hash keeps the other fields with their values replaced by a hash
+	// instead of dropping them, so entries can still be correlated without
+	// revealing the values.
+	Hash bool
+
+	// HashKey keys the hash as HMAC-SHA256, so low-entropy values such as
+	// user IDs cannot be recovered by hashing guesses. Without it, values
+	// are hashed with plain SHA-256.
+	HashKey []byte
+}
+
+// allowlist applies a FieldAllowlist.
+type allowlist struct {
+	patterns []string
+	hash     bool
+	key      []byte
+}
+
+func newAllowlist(a FieldAllowlist) (*allowlist, error) {
+	al := &allowlist{patterns: make([]string, len(a.Keys)), hash: a.Hash, key: a.HashKey}
+	for i, p := range a.Keys {
+		p = strings.ToLower(p)
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("logging: allowlist pattern %q: %w", p, err)
+		}
+		al.patterns[i] = p
+	}
+	return al, nil
+}
+
+func (a *allowlist) allowed(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range a.patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filter drops or hashes the fields of merged, which it owns, whose key is
+// in added and not allowed.
+func (a *allowlist) filter(merged, added Fields) {
+	for k := range added {
+		switch {
+		case a.allowed(k):
+		case a.hash:
+			merged[k] = hashValue(a.key, merged[k])
+		default:
+			delete(merged, k)
+		}
+	}
+}
+
+// hashValue returns "sha256:" and the first 16 hex digits of the hash of
+// the formatted value, keyed with HMAC if key is set.
+func hashValue(key []byte, v any) string {
+	var sum []byte
+	s := fmt.Sprint(v)
+	if key != nil {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(s))
+		sum = mac.Sum(nil)
+	} else {
+		h := sha256.Sum256([]byte(s))
+		sum = h[:]
+	}
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// WithFieldAllowlist restricts fields as by Logger.SetFieldAllowlist,
+// panicking on an invalid pattern.
+func WithFieldAllowlist(a FieldAllowlist) Option {
+	al, err := newAllowlist(a)
+	if err != nil {
+		panic(err)
+	}
+	return optionFunc(func(o *options) { o.allow = al })
+}
+
+// SetFieldAllowlist emits only the fields allowed by a, dropping or hashing
+// all others before they are encoded, for l and every logger derived or
+// named from the same root. The allowlist applies to every field, including
+// those added by helpers such as WithError; it runs after redaction and PII
+// scrubbing. Like SetRedaction, it only affects fields attached after the
+// call.
+func (l *Logger) SetFieldAllowlist(a FieldAllowlist) error {
+	al, err := newAllowlist(a)
+	if err != nil {
+		return err
+	}
+	l.state.tree.allow.Store(al)
+	return nil
+}
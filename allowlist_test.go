@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldAllowlistDrops(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	if err := logger.SetFieldAllowlist(FieldAllowlist{Keys: []string{"request_id", "HTTP_*"}}); err != nil {
+		t.Fatalf("SetFieldAllowlist failed: %v", err)
+	}
+	logger.WithFields(Fields{"request_id": "r1", "http_status": 200, "diagnosis": "flu", "patient": Fields{"name": "ada"}}).Info("visit")
+
+	e := decodeEntries(t, buf.String())[0]
+	if e["request_id"] != "r1" || e["http_status"] != float64(200) {
+		t.Errorf("Expected allowed fields kept, got %v", e)
+	}
+	if _, ok := e["diagnosis"]; ok {
+		t.Errorf("Expected other fields dropped, got %v", e)
+	}
+	if _, ok := e["patient"]; ok {
+		t.Errorf("Expected nested maps of other keys dropped, got %v", e)
+	}
+}
+
+func TestFieldAllowlistHashes(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := NewLogger(WithOutput(buf), WithFormat(FormatJSON),
+		WithFieldAllowlist(FieldAllowlist{Keys: []string{"status"}, Hash: true, HashKey: []byte("pepper")}))
+	logger.WithFields(Fields{"status": "ok", "user_id": 42}).Info("first")
+	logger.WithFields(Fields{"user_id": 42}).Info("second")
+
+	entries := decodeEntries(t, string(buf.Bytes()))
+	hashed, _ := entries[0]["user_id"].(string)
+	if entries[0]["status"] != "ok" || !strings.HasPrefix(hashed, "sha256:") || len(hashed) != len("sha256:")+16 {
+		t.Errorf("Expected other fields hashed, got %v", entries[0])
+	}
+	if entries[1]["user_id"] != hashed || hashed == hashValue(nil, 42) {
+		t.Errorf("Expected stable keyed hashes, got %v and %v", hashed, entries[1]["user_id"])
+	}
+	if err := logger.SetFieldAllowlist(FieldAllowlist{Keys: []string{"[bad"}}); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
+
+func TestFieldAllowlistHashesOnce(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := NewLogger(WithOutput(buf), WithFormat(FormatJSON),
+		WithRedaction("password"),
+		WithFieldAllowlist(FieldAllowlist{Keys: []string{"ok"}, Hash: true}))
+	parent := logger.WithFields(Fields{"user_id": 42, "password": "hunter2"})
+	parent.Info("parent")
+	child := parent.WithFields(Fields{"ok": 1})
+	child.Info("child")
+	child.Named("db").WithSubject("alice").Info("grandchild")
+
+	entries := decodeEntries(t, string(buf.Bytes()))
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	want := hashValue(nil, 42)
+	for _, e := range entries {
+		if e["user_id"] != want || e["password"] != entries[0]["password"] {
+			t.Errorf("Expected the fields of the parent hashed once, got %v", e)
+		}
+	}
+	if entries[1]["ok"] != float64(1) {
+		t.Errorf("Expected allowed fields of the child kept, got %v", entries[1])
+	}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// AssertLogged fails t unless rec recorded an entry at level whose message
+// contains substr. The failure lists what was recorded instead.
+func AssertLogged(t testing.TB, rec *Recorder, level LogLevel, substr string) bool {
+	t.Helper()
+	entries := rec.Entries()
+	if entries.FilterLevel(level).Contains(substr) {
+		return true
+	}
+	t.Errorf("logging: no %s entry containing %q; recorded:\n%s", level, substr, formatEntries(entries))
+	return false
+}
+
+// AssertNotLogged fails t if rec recorded an entry at level whose message
+// contains substr, listing the offending entries.
+func AssertNotLogged(t testing.TB, rec *Recorder, level LogLevel, substr string) bool {
+	t.Helper()
+	matches := rec.FilterLevel(level).filter(func(e Entry) bool { return strings.Contains(e.Message, substr) })
+	if len(matches) == 0 {
+		return true
+	}
+	t.Errorf("logging: unexpected %s entries containing %q:\n%s", level, substr, formatEntries(matches))
+	return false
+}
+
+// AssertFieldPresent fails t unless some entry recorded by rec has the
+// field key. The failure lists the recorded entries with their fields.
+func AssertFieldPresent(t testing.TB, rec *Recorder, key string) bool {
+	t.Helper()
+	entries := rec.Entries()
+	for _, e := range entries {
+		if _, ok := e.Fields[key]; ok {
+			return true
+		}
+	}
+	t.Errorf("logging: no entry with field %q; recorded:\n%s", key, formatEntries(entries))
+	return false
+}
+
+// formatEntries lists entries one per line for failure messages, with
+// their level, message and sorted fields.
+func formatEntries(entries Entries) string {
+	if len(entries) == 0 {
+		return "\t(no entries)"
+	}
+	var b strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "\t%-7s %q", e.Level, e.Message)
+		keys := make([]string, 0, len(e.Fields))
+		for k := range e.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeTB records the failures reported through it.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertHelpers(t *testing.T) {
+	rec := NewRecorder()
+	rec.Logger().WithFields(Fields{"user": "bob", "attempt": 2}).Warning("retrying")
+	rec.Error("upstream timeout")
+
+	ok := &fakeTB{}
+	if !AssertLogged(ok, rec, LogLevelError, "timeout") || !AssertNotLogged(ok, rec, LogLevelError, "retrying") ||
+		!AssertFieldPresent(ok, rec, "user") || len(ok.errors) != 0 {
+		t.Errorf("Expected the assertions to pass, got %v", ok.errors)
+	}
+
+	failed := &fakeTB{}
+	if AssertLogged(failed, rec, LogLevelWarning, "timeout") || AssertNotLogged(failed, rec, LogLevelError, "timeout") ||
+		AssertFieldPresent(failed, rec, "request_id") {
+		t.Error("Expected the assertions to fail")
+	}
+	want := []string{
+		"logging: no warning entry containing \"timeout\"; recorded:\n\twarning \"retrying\" attempt=2 user=bob\n\terror   \"upstream timeout\"",
+		"logging: unexpected error entries containing \"timeout\":\n\terror   \"upstream timeout\"",
+		"logging: no entry with field \"request_id\"; recorded:\n\twarning \"retrying\" attempt=2 user=bob\n\terror   \"upstream timeout\"",
+	}
+	if strings.Join(failed.errors, "\n--\n") != strings.Join(want, "\n--\n") {
+		t.Errorf("Unexpected failure messages:\n%s", strings.Join(failed.errors, "\n--\n"))
+	}
+
+	empty := &fakeTB{}
+	AssertLogged(empty, NewRecorder(), LogLevelInfo, "x")
+	if len(empty.errors) != 1 || !strings.HasSuffix(empty.errors[0], "(no entries)") {
+		t.Errorf("Expected an empty recording to be reported, got %v", empty.errors)
+	}
+}
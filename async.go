@@ -0,0 +1,242 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/phuslu/log"
+)
+
+var (
+	// ErrWriterClosed is returned when writing to a writer that has been closed.
+	ErrWriterClosed = errors.New("logging: writer is closed")
+
+	// ErrQueueFull is returned when an entry is dropped because the async queue is full.
+	ErrQueueFull = errors.New("logging: async queue is full")
+)
+
+// OverflowPolicy defines what an AsyncWriter does when its queue is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// OverflowBlock makes the caller wait until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the entry being written.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued entry to make room.
+	OverflowDropOldest
+)
+
+// AsyncWriter is a log.Writer that queues entries on a bounded queue and
+// writes them to Writer from a background goroutine, so slow sinks never
+// block the caller.
+type AsyncWriter struct {
+	// Writer is the destination of queued entries.
+	Writer log.Writer
+
+	// QueueSize is the capacity of the entry queue. Defaults to 1024.
+	QueueSize int
+
+	// Policy selects the behavior when the queue is full. Defaults to OverflowBlock.
+	Policy OverflowPolicy
+
+	// KeepErrors exempts Error and above from being dropped; such entries
+	// wait for room in the queue regardless of Policy.
+	KeepErrors bool
+
+	once     sync.Once
+	mu       sync.Mutex
+	cond     *sync.Cond
+	queue    []asyncEntry
+	pending  int
+	closed   bool
+	done     chan struct{}
+	dropped  atomic.Uint64
+	inflight atomic.Int64
+}
+
+type asyncEntry struct {
+	level log.Level
+	buf   *entryBuffer
+	flush chan struct{}
+}
+
+// NewAsyncWriter returns an AsyncWriter writing to w with a queue of size entries.
+func NewAsyncWriter(w log.Writer, size int) *AsyncWriter {
+	return &AsyncWriter{Writer: w, QueueSize: size}
+}
+
+func (w *AsyncWriter) start() {
+	if w.QueueSize <= 0 {
+		w.QueueSize = 1024
+	}
+	w.cond = sync.NewCond(&w.mu)
+	w.done = make(chan struct{})
+	go w.run()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	// The queue is double-buffered so steady-state writes do not allocate.
+	var spare []asyncEntry
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 {
+			w.mu.Unlock()
+			return
+		}
+		batch := w.queue
+		w.queue = spare[:0]
+		w.inflight.Store(int64(w.pending))
+		w.pending = 0
+		w.cond.Broadcast()
+		w.mu.Unlock()
+
+		for i, ae := range batch {
+			if ae.flush != nil {
+				close(ae.flush)
+				continue
+			}
+			e := log.NewContext(*ae.buf)
+			e.Level = ae.level
+			_, _ = w.Writer.WriteEntry(e)
+			w.inflight.Add(-1)
+			putEntryBuffer(ae.buf)
+			batch[i] = asyncEntry{}
+		}
+		spare = batch
+	}
+}
+
+// WriteEntry implements log.Writer. It copies the entry and queues it,
+// applying Policy when the queue is full.
+func (w *AsyncWriter) WriteEntry(e *log.Entry) (int, error) {
+	w.once.Do(w.start)
+
+	b := getEntryBuffer()
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		putEntryBuffer(b)
+		return 0, ErrWriterClosed
+	}
+	keep := w.KeepErrors && e.Level >= log.ErrorLevel
+	for w.pending >= w.QueueSize {
+		switch {
+		case w.Policy == OverflowDropNewest && !keep:
+			w.dropped.Add(1)
+			putEntryBuffer(b)
+			return 0, ErrQueueFull
+		case w.Policy == OverflowDropOldest && w.dropOldest():
+			continue
+		}
+		w.cond.Wait()
+		if w.closed {
+			putEntryBuffer(b)
+			return 0, ErrWriterClosed
+		}
+	}
+	w.queue = append(w.queue, asyncEntry{level: e.Level, buf: b})
+	w.pending++
+	w.cond.Broadcast()
+	return len(*b), nil
+}
+
+// dropOldest removes the oldest droppable entry from the queue. It reports
+// false if every queued entry must be kept.
+func (w *AsyncWriter) dropOldest() bool {
+	for i, ae := range w.queue {
+		if ae.flush != nil || (w.KeepErrors && ae.level >= log.ErrorLevel) {
+			continue
+		}
+		w.queue = append(w.queue[:i], w.queue[i+1:]...)
+		w.pending--
+		putEntryBuffer(ae.buf)
+		w.dropped.Add(1)
+		return true
+	}
+	return false
+}
+
+func (w *AsyncWriter) buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pending + int(w.inflight.Load())
+}
+
+// Dropped returns the number of entries discarded because the queue was full.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush blocks until every entry queued before the call has been written,
+// then flushes Writer if it buffers entries itself.
+func (w *AsyncWriter) Flush() error {
+	w.once.Do(w.start)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	done := make(chan struct{})
+	w.queue = append(w.queue, asyncEntry{flush: done})
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	<-done
+	return flushWriter(w.Writer)
+}
+
+// Close drains the queue, stops the background goroutine and closes Writer
+// if it is closable.
+func (w *AsyncWriter) Close() error {
+	w.once.Do(w.start)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	<-w.done
+	return closeWriter(w.Writer)
+}
+
+// entryBuffer captures the encoded bytes of a log.Entry.
+type entryBuffer []byte
+
+func (b *entryBuffer) Write(p []byte) (int, error) {
+	*b = append(*b, p...)
+	return len(p), nil
+}
+
+// maxPooledBuffer bounds the capacity of buffers returned to the pool so a
+// single huge entry does not pin memory forever.
+const maxPooledBuffer = 64 << 10
+
+var entryBufferPool = sync.Pool{
+	New: func() any { return new(entryBuffer) },
+}
+
+func getEntryBuffer() *entryBuffer {
+	b := entryBufferPool.Get().(*entryBuffer)
+	*b = (*b)[:0]
+	return b
+}
+
+func putEntryBuffer(b *entryBuffer) {
+	if cap(*b) <= maxPooledBuffer {
+		entryBufferPool.Put(b)
+	}
+}
@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// slowWriter simulates a sink that takes a while to accept each entry.
+type slowWriter struct {
+	mu    sync.Mutex
+	delay time.Duration
+	buf   bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriterFlush(t *testing.T) {
+	sink := &slowWriter{delay: 5 * time.Millisecond}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.logger.Writer = log.IOWriter{Writer: sink}
+	logger.SetAsync(16)
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		logger.Info("entry %d", i)
+	}
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Errorf("Async logging should not block on the sink, took %v", elapsed)
+	}
+
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got := bytes.Count([]byte(sink.String()), []byte("\n")); got != 10 {
+		t.Errorf("Expected 10 entries after Flush, got %d", got)
+	}
+}
+
+func TestAsyncWriterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewAsyncWriter(log.IOWriter{Writer: &buf}, 4)
+	logger, _ := testLogger(LogLevelInfo)
+	logger.logger.Writer = w
+
+	logger.Info("before close")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("before close")) {
+		t.Error("Close should drain queued entries")
+	}
+	if _, err := w.WriteEntry(log.NewContext(nil)); err != ErrWriterClosed {
+		t.Errorf("Expected ErrWriterClosed, got %v", err)
+	}
+}
+
+// gateWriter blocks every write until release is closed.
+type gateWriter struct {
+	release chan struct{}
+	started chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	lines   []string
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{release: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func (w *gateWriter) messages(t *testing.T) []string {
+	t.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var msgs []string
+	for _, line := range w.lines {
+		entry, err := parseLogEntry(bytes.NewBufferString(line))
+		if err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		msgs = append(msgs, entry.Message)
+	}
+	return msgs
+}
+
+func TestAsyncWriterOverflowPolicies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     OverflowPolicy
+		keepErrors bool
+		expected   []string
+	}{
+		{"drop newest", OverflowDropNewest, false, []string{"0", "1", "2"}},
+		{"drop oldest", OverflowDropOldest, false, []string{"0", "3", "4"}},
+		{"drop newest keeps errors", OverflowDropNewest, true, []string{"0", "1", "2", "err"}},
+		{"drop oldest keeps errors", OverflowDropOldest, true, []string{"0", "4", "err"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := newGateWriter()
+			w := &AsyncWriter{
+				Writer:     log.IOWriter{Writer: sink},
+				QueueSize:  2,
+				Policy:     tc.policy,
+				KeepErrors: tc.keepErrors,
+			}
+			logger, _ := testLogger(LogLevelInfo)
+			logger.logger.Writer = w
+
+			// The first entry occupies the writer goroutine; the rest queue up.
+			logger.Info("0")
+			<-sink.started
+			for i := 1; i <= 4; i++ {
+				logger.Info("%d", i)
+			}
+			errLogged := make(chan struct{})
+			if tc.keepErrors {
+				go func() {
+					logger.Error("err")
+					close(errLogged)
+				}()
+				time.Sleep(10 * time.Millisecond)
+			} else {
+				close(errLogged)
+			}
+			close(sink.release)
+			<-errLogged
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			got := sink.messages(t)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("Expected %v, got %v", tc.expected, got)
+					break
+				}
+			}
+			if w.Dropped() == 0 {
+				t.Error("Expected dropped entries to be counted")
+			}
+		})
+	}
+}
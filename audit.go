@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// Audit outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+)
+
+// auditKeys are the fields every audit entry carries.
+var auditKeys = []string{"time", "level", "actor", "action", "resource", "outcome", "reason", "message", ChainPrevField, ChainHashField}
+
+// AuditEvent is one entry of an audit trail: who did what to which
+// resource, and how it ended.
+type AuditEvent struct {
+	// Actor identifies who acted, such as a user or service account.
+	Actor string
+	// Action is what was done, such as "user.delete".
+	Action string
+	// Resource is what was acted on, such as "users/42".
+	Resource string
+	// Outcome is OutcomeSuccess, OutcomeFailure or OutcomeDenied.
+	Outcome string
+	// Reason optionally explains a failure or denial.
+	Reason string
+	// Fields are extra details; they cannot replace the fields above.
+	Fields Fields
+	// Time is when the action happened. Defaults to now.
+	Time time.Time
+}
+
+// AuditOptions configures an AuditLogger.
+type AuditOptions struct {
+	// Actions, if set, are the only actions accepted.
+	Actions []string
+
+	// Required are extra field keys every event must carry, such as
+	// "request_id".
+	Required []string
+
+	// Prev, AnchorEvery and OnAnchor configure the hash chain as for
+	// ChainWriter.
+	Prev        string
+	AnchorEvery int
+	OnAnchor    func(Anchor)
+}
+
+// AuditError reports an event rejected by the audit schema.
+type AuditError struct {
+	// Problems lists what is wrong with the event.
+	Problems []string
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("logging: invalid audit event: %v", e.Problems)
+}
+
+// AuditLogger writes an audit trail separate from application logging:
+// every event must carry an actor, an action, a resource and an outcome and
+// pass the schema of its options, and is written synchronously, as JSON,
+// through its own ChainWriter, so the trail is tamper-evident. There is no
+// level, sampling, rate limiting or redaction: an accepted event is always
+// written, and a failure to write it is returned to the caller.
+type AuditLogger struct {
+	chain *ChainWriter
+	opts  AuditOptions
+	mu    sync.Mutex
+	buf   []byte
+}
+
+// NewAuditLogger returns an AuditLogger writing to w, typically a dedicated
+// file.
+func NewAuditLogger(w io.Writer, opts AuditOptions) *AuditLogger {
+	chain := &ChainWriter{
+		Writer:      &log.IOWriter{Writer: w},
+		Prev:        opts.Prev,
+		AnchorEvery: opts.AnchorEvery,
+		OnAnchor:    opts.OnAnchor,
+	}
+	return &AuditLogger{chain: chain, opts: opts}
+}
+
+// Log validates ev and writes it to the trail. It returns an *AuditError
+// for an invalid event, which is not written.
+func (a *AuditLogger) Log(ev AuditEvent) error {
+	if err := a.validate(ev); err != nil {
+		return err
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	keys := make([]string, 0, len(ev.Fields))
+	for k := range ev.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := append(a.buf[:0], `{"time":"`...)
+	b = ev.Time.UTC().AppendFormat(b, time.RFC3339Nano)
+	b = append(b, `","level":"info"`...)
+	c := log.NewContext(b).
+		Str("actor", ev.Actor).
+		Str("action", ev.Action).
+		Str("resource", ev.Resource).
+		Str("outcome", ev.Outcome)
+	if ev.Reason != "" {
+		c = c.Str("reason", ev.Reason)
+	}
+	b = c.Value()
+	for _, k := range keys {
+		b = appendField(b, k, omitTagged(ev.Fields[k]))
+	}
+	b = log.NewContext(b).Str("message", ev.Action).Value()
+	b = append(b, "}\n"...)
+	a.buf = b
+
+	e := log.NewContext(b)
+	e.Level = log.InfoLevel
+	_, err := a.chain.WriteEntry(e)
+	return err
+}
+
+// validate checks ev against the schema.
+func (a *AuditLogger) validate(ev AuditEvent) error {
+	var problems []string
+	for _, f := range []struct{ name, value string }{
+		{"actor", ev.Actor}, {"action", ev.Action}, {"resource", ev.Resource}, {"outcome", ev.Outcome},
+	} {
+		if f.value == "" {
+			problems = append(problems, f.name+" is required")
+		}
+	}
+	switch ev.Outcome {
+	case "", OutcomeSuccess, OutcomeFailure, OutcomeDenied:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown outcome %q", ev.Outcome))
+	}
+	if ev.Action != "" && len(a.opts.Actions) > 0 && !slices.Contains(a.opts.Actions, ev.Action) {
+		problems = append(problems, fmt.Sprintf("unknown action %q", ev.Action))
+	}
+	for _, k := range a.opts.Required {
+		if _, ok := ev.Fields[k]; !ok {
+			problems = append(problems, k+" is required")
+		}
+	}
+	for k := range ev.Fields {
+		if slices.Contains(auditKeys, k) {
+			problems = append(problems, fmt.Sprintf("field %q is reserved", k))
+		}
+	}
+	if problems == nil {
+		return nil
+	}
+	sort.Strings(problems)
+	return &AuditError{Problems: problems}
+}
+
+// Head returns the hash of the last entry of the trail, to persist and pass
+// as AuditOptions.Prev when the trail continues in a new process.
+func (a *AuditLogger) Head() string {
+	return a.chain.Head()
+}
+
+// Sync commits the trail written so far to stable storage, if the
+// destination is a file.
+func (a *AuditLogger) Sync() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return syncWriter(a.chain)
+}
+
+// Close writes a final anchor and closes the destination if it is
+// closable.
+func (a *AuditLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.chain.Close()
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAuditLoggerWritesChainedTrail(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf, AuditOptions{Actions: []string{"user.delete", "role.grant"}, Required: []string{"request_id"}})
+	err := audit.Log(AuditEvent{Actor: "alice", Action: "user.delete", Resource: "users/42", Outcome: OutcomeSuccess,
+		Fields: Fields{"request_id": "r1", "creds": credentials{User: "alice", Password: "pw"}}})
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	err = audit.Log(AuditEvent{Actor: "bob", Action: "role.grant", Resource: "roles/admin", Outcome: OutcomeDenied,
+		Reason: "not an owner", Fields: Fields{"request_id": "r2"}})
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if err := audit.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, n, err := VerifyChain(strings.NewReader(buf.String()), ""); err != nil || n != 3 {
+		t.Fatalf("Expected 2 events and an anchor in a valid chain, got %d, %v", n, err)
+	}
+	entries := decodeEntries(t, buf.String())
+	e := entries[0]
+	if e["actor"] != "alice" || e["action"] != "user.delete" || e["resource"] != "users/42" || e["outcome"] != "success" || e["message"] != "user.delete" {
+		t.Errorf("Expected the mandatory fields, got %v", e)
+	}
+	if _, ok := e["creds"].(map[string]any)["password"]; ok {
+		t.Errorf("Expected log tags honored, got %v", e["creds"])
+	}
+	if entries[1]["reason"] != "not an owner" || entries[1]["outcome"] != "denied" {
+		t.Errorf("Expected the reason of a denial, got %v", entries[1])
+	}
+}
+
+func TestAuditLoggerRejectsInvalidEvents(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf, AuditOptions{Actions: []string{"user.delete"}, Required: []string{"request_id"}})
+	err := audit.Log(AuditEvent{Action: "user.purge", Outcome: "maybe", Fields: Fields{"actor": "mallory"}})
+	var aerr *AuditError
+	if !errors.As(err, &aerr) {
+		t.Fatalf("Expected an AuditError, got %v", err)
+	}
+	want := []string{
+		`actor is required`,
+		`field "actor" is reserved`,
+		`request_id is required`,
+		`resource is required`,
+		`unknown action "user.purge"`,
+		`unknown outcome "maybe"`,
+	}
+	if strings.Join(aerr.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected problems:\n got %q\nwant %q", aerr.Problems, want)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written for an invalid event, got %q", buf.String())
+	}
+}
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// BatchSink is implemented by destinations that accept many entries at once,
+// such as bulk HTTP collectors. The entries are only valid until WriteBatch
+// returns; implementations must copy anything they keep.
+type BatchSink interface {
+	WriteBatch(entries [][]byte) error
+}
+
+// BatchFunc adapts an ordinary function to a BatchSink.
+type BatchFunc func(entries [][]byte) error
+
+// WriteBatch calls f(entries).
+func (f BatchFunc) WriteBatch(entries [][]byte) error {
+	return f(entries)
+}
+
+// IOBatchSink writes each batch to the wrapped io.Writer in a single call.
+type IOBatchSink struct {
+	io.Writer
+}
+
+// WriteBatch implements BatchSink.
+func (s IOBatchSink) WriteBatch(entries [][]byte) error {
+	n := 0
+	for _, e := range entries {
+		n += len(e)
+	}
+	buf := make([]byte, 0, n)
+	for _, e := range entries {
+		buf = append(buf, e...)
+	}
+	_, err := s.Writer.Write(buf)
+	return err
+}
+
+// BatchWriter is a log.Writer that groups entries and hands them to Sink
+// every MaxEntries entries or every Interval, whichever comes first. Entries
+// at Error level and above are flushed immediately, so the visibility latency
+// of important entries stays bounded.
+type BatchWriter struct {
+	// Sink receives the batches.
+	Sink BatchSink
+
+	// MaxEntries is the batch size that triggers a flush. Defaults to 100.
+	MaxEntries int
+
+	// Interval is the maximum time an entry waits in the batch. Defaults to 1s.
+	Interval time.Duration
+
+	mu      sync.Mutex
+	flushMu sync.Mutex
+	batch   []*entryBuffer
+	entries [][]byte
+	timer   *time.Timer
+}
+
+// NewBatchWriter returns a BatchWriter flushing to sink every maxEntries
+// entries or every interval.
+func NewBatchWriter(sink BatchSink, maxEntries int, interval time.Duration) *BatchWriter {
+	return &BatchWriter{Sink: sink, MaxEntries: maxEntries, Interval: interval}
+}
+
+// WriteEntry implements log.Writer.
+func (w *BatchWriter) WriteEntry(e *log.Entry) (int, error) {
+	b := getEntryBuffer()
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+	n := len(*b)
+
+	w.mu.Lock()
+	w.batch = append(w.batch, b)
+	full := len(w.batch) >= w.maxEntries()
+	if len(w.batch) == 1 && !full {
+		w.timer = time.AfterFunc(w.interval(), func() { _ = w.Flush() })
+	}
+	w.mu.Unlock()
+
+	if full || e.Level >= log.ErrorLevel {
+		if err := w.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+func (w *BatchWriter) buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.batch)
+}
+
+// Flush writes the pending batch to Sink.
+func (w *BatchWriter) Flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.batch
+	w.batch = nil
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	w.entries = w.entries[:0]
+	for _, b := range batch {
+		w.entries = append(w.entries, *b)
+	}
+	err := w.Sink.WriteBatch(w.entries)
+	for i, b := range batch {
+		putEntryBuffer(b)
+		w.entries[i] = nil
+	}
+	return err
+}
+
+// Close flushes the pending batch and closes Sink if it is closable.
+func (w *BatchWriter) Close() error {
+	err := w.Flush()
+	if cerr := closeWriter(w.Sink); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (w *BatchWriter) maxEntries() int {
+	if w.MaxEntries <= 0 {
+		return 100
+	}
+	return w.MaxEntries
+}
+
+func (w *BatchWriter) interval() time.Duration {
+	if w.Interval <= 0 {
+		return time.Second
+	}
+	return w.Interval
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecorder records the size of every batch it receives.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (r *batchRecorder) WriteBatch(entries [][]byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, len(entries))
+	return nil
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.batches...)
+}
+
+func TestBatchWriterSizeTrigger(t *testing.T) {
+	sink := &batchRecorder{}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewBatchWriter(sink, 3, time.Hour))
+
+	for i := 0; i < 7; i++ {
+		logger.Info("entry %d", i)
+	}
+	if got := sink.sizes(); len(got) != 2 || got[0] != 3 || got[1] != 3 {
+		t.Errorf("Expected two batches of 3, got %v", got)
+	}
+}
+
+func TestBatchWriterIntervalTrigger(t *testing.T) {
+	sink := &batchRecorder{}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewBatchWriter(sink, 100, 10*time.Millisecond))
+
+	logger.Info("first")
+	logger.Info("second")
+	deadline := time.Now().Add(time.Second)
+	for len(sink.sizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := sink.sizes(); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected one batch of 2 after the interval, got %v", got)
+	}
+}
+
+func TestBatchWriterErrorFlushesImmediately(t *testing.T) {
+	sink := &batchRecorder{}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewBatchWriter(sink, 100, time.Hour))
+
+	logger.Info("context")
+	logger.Error("failure")
+	if got := sink.sizes(); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected Error to flush the batch of 2, got %v", got)
+	}
+}
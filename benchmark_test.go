@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// benchLogger returns a JSON logger writing to io.Discard.
+func benchLogger(level LogLevel) *Logger {
+	l := NewLogger(level)
+	l.logger = &log.Logger{
+		Writer: &log.IOWriter{Writer: io.Discard},
+	}
+	return l
+}
+
+// heavyFields is a representative set of request-scoped fields.
+var heavyFields = Fields{
+	"request_id": "8f14e45f-ceea-467f-a0e6-2ae2c2b05b4e",
+	"method":     "GET",
+	"path":       "/api/v1/users",
+	"status":     200,
+	"bytes":      5120,
+	"duration":   12 * time.Millisecond,
+	"remote_ip":  "10.0.0.1",
+	"user_agent": "curl/8.0",
+	"retry":      false,
+	"error":      errors.New("upstream timeout"),
+}
+
+func TestDisabledLevelZeroAlloc(t *testing.T) {
+	logger := benchLogger(LogLevelError)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info("static message")
+		logger.Warning("user %s logged in", "john")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected zero allocations for disabled levels, got %v", allocs)
+	}
+}
+
+func TestFieldsZeroAlloc(t *testing.T) {
+	logger := benchLogger(LogLevelInfo).WithFields(Fields{"component": "db", "shard": 3})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info("static message")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected zero allocations per call, got %v", allocs)
+	}
+}
+
+func BenchmarkInfoDisabled(b *testing.B) {
+	logger := benchLogger(LogLevelError)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("static message")
+	}
+}
+
+func BenchmarkInfoDisabledFormatted(b *testing.B) {
+	logger := benchLogger(LogLevelError)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("user %s logged in", "john")
+	}
+}
+
+func BenchmarkInfoStatic(b *testing.B) {
+	logger := benchLogger(LogLevelInfo)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("static message")
+	}
+}
+
+func BenchmarkInfoFormatted(b *testing.B) {
+	logger := benchLogger(LogLevelInfo)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("user %s logged in", "john")
+	}
+}
+
+func BenchmarkInfoFields(b *testing.B) {
+	logger := benchLogger(LogLevelInfo).WithFields(Fields{"component": "db", "shard": 3})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("static message")
+	}
+}
+
+func BenchmarkInfoFieldHeavy(b *testing.B) {
+	logger := benchLogger(LogLevelInfo).WithFields(heavyFields)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request completed")
+	}
+}
+
+func BenchmarkInfoSampledOut(b *testing.B) {
+	logger := benchLogger(LogLevelInfo)
+	logger.SetSampler(LevelSampler{LogLevelInfo: EveryN(1 << 62)})
+	logger.Info("first entry is kept")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("user %s logged in", "john")
+	}
+}
+
+func BenchmarkInfoAsync(b *testing.B) {
+	logger := benchLogger(LogLevelInfo)
+	logger.SetAsync(1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("static message")
+	}
+	logger.Flush()
+}
+
+func BenchmarkInfoParallel(b *testing.B) {
+	logger := benchLogger(LogLevelInfo).WithFields(Fields{"component": "db"})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("user %s logged in", "john")
+		}
+	})
+}
+
+func BenchmarkInfoDisabledParallel(b *testing.B) {
+	logger := benchLogger(LogLevelError)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("static message")
+		}
+	})
+}
+
+func BenchmarkInfoShardedParallel(b *testing.B) {
+	logger := benchLogger(LogLevelInfo)
+	w := NewShardedWriter(&log.IOWriter{Writer: io.Discard}, 0)
+	logger.SetWriter(w)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("user %s logged in", "john")
+		}
+	})
+	w.Close()
+}
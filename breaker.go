@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// ErrCircuitOpen is returned for entries not attempted because the circuit
+// breaker of their sink is open.
+var ErrCircuitOpen = errors.New("logging: circuit breaker open")
+
+// BreakerPolicy configures a BreakerSink or BreakerWriter.
+type BreakerPolicy struct {
+	// Threshold is the number of consecutive failures that opens the
+	// breaker. Defaults to 5.
+	Threshold int
+
+	// Cooldown is how long an open breaker rejects entries before letting
+	// one attempt through. Defaults to 30s.
+	Cooldown time.Duration
+
+	// Report receives one line when the breaker opens and one when the sink
+	// recovers, with the number of entries skipped. Defaults to os.Stderr.
+	Report io.Writer
+}
+
+// breaker counts consecutive failures and skips attempts while open.
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	trial    bool
+	skipped  uint64
+	counters deliveryCounters
+}
+
+// do calls deliver unless the breaker is open, and counts n entries of
+// size bytes as delivered, failed or dropped.
+func (b *breaker) do(p *BreakerPolicy, n, size int, deliver func() error) error {
+	b.mu.Lock()
+	if b.open {
+		if b.trial || time.Since(b.openedAt) < p.cooldown() {
+			b.skipped += uint64(n)
+			b.mu.Unlock()
+			b.counters.dropped.Add(uint64(n))
+			return ErrCircuitOpen
+		}
+		b.trial = true
+	}
+	b.mu.Unlock()
+
+	err := deliver()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if err == nil {
+		b.counters.deliver(n, size)
+		if b.open {
+			fmt.Fprintf(p.report(), "logging: sink recovered after %v, %d entries skipped\n",
+				time.Since(b.openedAt).Round(time.Millisecond), b.skipped)
+		}
+		b.failures, b.open, b.skipped = 0, false, 0
+		return nil
+	}
+	b.counters.failed.Add(uint64(n))
+	b.failures++
+	if b.open {
+		b.openedAt = time.Now()
+	} else if b.failures >= p.threshold() {
+		b.open, b.openedAt = true, time.Now()
+		fmt.Fprintf(p.report(), "logging: sink failed %d times, pausing it for %v: %v\n",
+			b.failures, p.cooldown(), err)
+	}
+	return err
+}
+
+// isOpen reports whether the breaker rejects entries.
+func (b *breaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+func (p *BreakerPolicy) threshold() int {
+	if p.Threshold <= 0 {
+		return 5
+	}
+	return p.Threshold
+}
+
+func (p *BreakerPolicy) cooldown() time.Duration {
+	if p.Cooldown <= 0 {
+		return 30 * time.Second
+	}
+	return p.Cooldown
+}
+
+func (p *BreakerPolicy) report() io.Writer {
+	if p.Report == nil {
+		return os.Stderr
+	}
+	return p.Report
+}
+
+// BreakerSink is a BatchSink that stops attempting a failing Sink for a
+// cool-down period instead of spending time on every batch. While open it
+// returns ErrCircuitOpen at once; a SpoolWriter in front keeps the entries
+// until the sink recovers.
+type BreakerSink struct {
+	// Sink receives the batches.
+	Sink BatchSink
+
+	// Policy configures the breaker.
+	Policy BreakerPolicy
+
+	breaker
+}
+
+// NewBreakerSink returns a BreakerSink guarding sink under policy.
+func NewBreakerSink(sink BatchSink, policy BreakerPolicy) *BreakerSink {
+	return &BreakerSink{Sink: sink, Policy: policy}
+}
+
+// WriteBatch implements BatchSink.
+func (s *BreakerSink) WriteBatch(entries [][]byte) error {
+	return s.do(&s.Policy, len(entries), batchBytes(entries), func() error { return s.Sink.WriteBatch(entries) })
+}
+
+// Open reports whether the breaker is open.
+func (s *BreakerSink) Open() bool {
+	return s.isOpen()
+}
+
+// Dropped returns the number of entries rejected while the breaker was open.
+func (s *BreakerSink) Dropped() uint64 {
+	return s.counters.dropped.Load()
+}
+
+// Stats returns the delivery counters of s.
+func (s *BreakerSink) Stats() DeliveryStats {
+	return s.counters.snapshot()
+}
+
+// Close closes Sink if it is closable.
+func (s *BreakerSink) Close() error {
+	return closeWriter(s.Sink)
+}
+
+// BreakerWriter is a log.Writer that stops attempting a failing Writer for
+// a cool-down period, like BreakerSink.
+type BreakerWriter struct {
+	// Writer is the destination of entries.
+	Writer log.Writer
+
+	// Policy configures the breaker.
+	Policy BreakerPolicy
+
+	breaker
+}
+
+// NewBreakerWriter returns a BreakerWriter guarding w under policy.
+func NewBreakerWriter(w log.Writer, policy BreakerPolicy) *BreakerWriter {
+	return &BreakerWriter{Writer: w, Policy: policy}
+}
+
+// WriteEntry implements log.Writer.
+func (w *BreakerWriter) WriteEntry(e *log.Entry) (n int, err error) {
+	err = w.do(&w.Policy, 1, 0, func() error {
+		n, err = w.Writer.WriteEntry(e)
+		return err
+	})
+	if err == nil {
+		w.counters.bytes.Add(uint64(n))
+	}
+	return n, err
+}
+
+// Open reports whether the breaker is open.
+func (w *BreakerWriter) Open() bool {
+	return w.isOpen()
+}
+
+// Dropped returns the number of entries rejected while the breaker was open.
+func (w *BreakerWriter) Dropped() uint64 {
+	return w.counters.dropped.Load()
+}
+
+// Stats returns the delivery counters of w.
+func (w *BreakerWriter) Stats() DeliveryStats {
+	return w.counters.snapshot()
+}
+
+// Flush flushes Writer if it buffers entries.
+func (w *BreakerWriter) Flush() error {
+	return flushWriter(w.Writer)
+}
+
+// Close closes Writer if it is closable.
+func (w *BreakerWriter) Close() error {
+	return closeWriter(w.Writer)
+}
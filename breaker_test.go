@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreakerSinkOpensAndRecovers(t *testing.T) {
+	var report bytes.Buffer
+	calls := 0
+	down := true
+	sink := NewBreakerSink(BatchFunc(func([][]byte) error {
+		calls++
+		if down {
+			return errCollectorDown
+		}
+		return nil
+	}), BreakerPolicy{Threshold: 3, Cooldown: 20 * time.Millisecond, Report: &report})
+
+	for i := 0; i < 10; i++ {
+		_ = sink.WriteBatch(make([][]byte, 2))
+	}
+	if calls != 3 || !sink.Open() {
+		t.Fatalf("Expected the breaker to open after 3 failures, got %d attempts", calls)
+	}
+	if err := sink.WriteBatch(make([][]byte, 1)); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen while open, got %v", err)
+	}
+	if sink.Dropped() != 15 {
+		t.Errorf("Expected 15 skipped entries, got %d", sink.Dropped())
+	}
+	if n := strings.Count(report.String(), "\n"); n != 1 {
+		t.Errorf("Expected a single summary when the breaker opens, got %q", report.String())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	down = false
+	if err := sink.WriteBatch(make([][]byte, 1)); err != nil {
+		t.Fatalf("Expected the trial after the cool-down to go through, got %v", err)
+	}
+	if sink.Open() || !strings.Contains(report.String(), "recovered") || !strings.Contains(report.String(), "15 entries skipped") {
+		t.Errorf("Expected the breaker to close with a recovery summary, got %q", report.String())
+	}
+}
+
+func TestBreakerWriterReopensOnFailedTrial(t *testing.T) {
+	var report bytes.Buffer
+	logger, _ := testLogger(LogLevelInfo)
+	bw := NewBreakerWriter(failingWriter{err: errCollectorDown}, BreakerPolicy{Threshold: 1, Cooldown: 10 * time.Millisecond, Report: &report})
+	logger.SetWriter(bw)
+
+	logger.Info("fails")
+	logger.Info("skipped")
+	time.Sleep(15 * time.Millisecond)
+	logger.Info("trial")
+	logger.Info("skipped again")
+	if !bw.Open() || bw.Dropped() != 2 {
+		t.Errorf("Expected a failed trial to reopen the breaker, got open=%v dropped=%d", bw.Open(), bw.Dropped())
+	}
+	if n := strings.Count(report.String(), "\n"); n != 1 {
+		t.Errorf("Expected no new summary for a failed trial, got %q", report.String())
+	}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	versionMu sync.Mutex
+	version   string
+)
+
+// SetVersion sets the service version reported by WithBuildInfo, for
+// builds that stamp it with -ldflags rather than module versioning. It
+// applies to loggers created afterwards.
+func SetVersion(v string) {
+	versionMu.Lock()
+	version = v
+	versionMu.Unlock()
+}
+
+// WithBuildInfo attaches the service version, VCS revision and Go version
+// to every entry, so entries can be correlated with releases. The version
+// is the one given to SetVersion, or else the main module version recorded
+// by the Go toolchain.
+func WithBuildInfo() Option {
+	return optionFunc(func(o *options) { o.buildInfo = true })
+}
+
+// buildFields returns the fields added by WithBuildInfo.
+func buildFields() Fields {
+	fields := Fields{"go_version": runtime.Version()}
+	versionMu.Lock()
+	v := version
+	versionMu.Unlock()
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				fields["vcs_revision"] = s.Value
+			case "vcs.modified":
+				if s.Value == "true" {
+					fields["vcs_modified"] = true
+				}
+			}
+		}
+	}
+	if v != "" {
+		fields["version"] = v
+	}
+	return fields
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"testing"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	SetVersion("v1.4.2")
+	defer SetVersion("")
+	var buf bytes.Buffer
+	logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), WithBuildInfo())
+
+	logger.Info("started")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry["version"] != "v1.4.2" {
+		t.Errorf("Expected the version set by SetVersion, got %v", entry["version"])
+	}
+	if entry["go_version"] != runtime.Version() {
+		t.Errorf("Expected go_version %s, got %v", runtime.Version(), entry["go_version"])
+	}
+}
+
+func TestBuildInfoFromConfig(t *testing.T) {
+	logger, err := NewLoggerFromConfig(&Config{BuildInfo: true})
+	if err != nil {
+		t.Fatalf("NewLoggerFromConfig failed: %v", err)
+	}
+	if logger.fields["go_version"] != runtime.Version() {
+		t.Errorf("Expected build fields, got %v", logger.fields)
+	}
+}
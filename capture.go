@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// CaptureSink is a log.Writer and BatchSink for tests that stores the
+// entries written to it as Entry values, so tests can assert on levels and
+// fields instead of parsing bytes. Unlike Recorder, which observes a
+// logger, it sits at the end of a writer chain and sees exactly what a real
+// sink would, including entries written by wrappers:
+//
+//	sink := new(logging.CaptureSink)
+//	logger := logging.NewLogger(logging.WithWriter(sink))
+//
+// Integral numbers are decoded as int and other numbers as float64, so
+// fields compare equal to the int values tests usually log.
+type CaptureSink struct {
+	mu      sync.Mutex
+	entries Entries
+}
+
+// WriteEntry implements log.Writer.
+func (s *CaptureSink) WriteEntry(e *log.Entry) (int, error) {
+	b := getEntryBuffer()
+	defer putEntryBuffer(b)
+	n, _ := log.IOWriter{Writer: b}.WriteEntry(e)
+	return n, s.capture(*b)
+}
+
+// WriteBatch implements BatchSink.
+func (s *CaptureSink) WriteBatch(entries [][]byte) error {
+	for _, e := range entries {
+		if err := s.capture(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CaptureSink) capture(data []byte) error {
+	e, err := decodeEntry(data)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.entries = append(s.entries, e)
+	s.mu.Unlock()
+	return nil
+}
+
+// Entries returns a copy of the entries captured so far, oldest first.
+func (s *CaptureSink) Entries() Entries {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(Entries(nil), s.entries...)
+}
+
+// Reset discards the entries captured so far.
+func (s *CaptureSink) Reset() {
+	s.mu.Lock()
+	s.entries = nil
+	s.mu.Unlock()
+}
+
+// captureTimeLayouts are tried in turn to parse the time of an entry.
+var captureTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05"}
+
+// decodeEntry decodes one JSON entry. Time, level and message become the
+// fields of the same names of Entry, the logger field both Logger and a
+// field, and every other key a field.
+func decodeEntry(data []byte) (Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return Entry{}, err
+	}
+	var e Entry
+	if s, ok := m["time"].(string); ok {
+		for _, layout := range captureTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				e.Time = t
+				break
+			}
+		}
+	}
+	if s, ok := m["level"].(string); ok {
+		e.Level, _ = parseLevel(s)
+		if s == "fatal" || s == "panic" {
+			e.Level = LogLevelError
+		}
+	}
+	e.Message, _ = m["message"].(string)
+	e.Logger, _ = m["logger"].(string)
+	delete(m, "time")
+	delete(m, "level")
+	delete(m, "message")
+	if len(m) > 0 {
+		e.Fields = make(Fields, len(m))
+		for k, v := range m {
+			e.Fields[k] = convertNumbers(v)
+		}
+	}
+	return e, nil
+}
+
+// convertNumbers replaces the json.Numbers in v with int or float64.
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil && int64(int(i)) == i {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, x := range v {
+			v[k] = convertNumbers(x)
+		}
+	case []any:
+		for i, x := range v {
+			v[i] = convertNumbers(x)
+		}
+	}
+	return v
+}
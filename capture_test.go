@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptureSinkDecodesEntries(t *testing.T) {
+	sink := new(CaptureSink)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	logger := NewLogger(WithWriter(sink), LogLevelDebug, WithClock(ClockFunc(func() time.Time { return start })))
+	logger.Named("db").WithFields(Fields{"status": 200, "ratio": 0.5, "tags": []string{"a"}}).Warning("slow query")
+	logger.Debug("tick")
+
+	entries := sink.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+	e := entries[0]
+	if e.Level != LogLevelWarning || e.Message != "slow query" || e.Logger != "db" || !e.Time.Equal(start) {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if len(entries.FieldEquals("status", 200)) != 1 || e.Fields["ratio"] != 0.5 || e.Fields["tags"].([]any)[0] != "a" {
+		t.Errorf("Expected fields comparable to the logged values, got %v", e.Fields)
+	}
+	if _, ok := e.Fields["message"]; ok {
+		t.Errorf("Expected message, time and level kept out of fields, got %v", e.Fields)
+	}
+	if entries[1].Level != LogLevelDebug || entries[1].Fields != nil {
+		t.Errorf("Expected a bare debug entry, got %+v", entries[1])
+	}
+
+	if err := sink.WriteBatch([][]byte{[]byte(`{"level":"error","message":"from a batch"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if !sink.Entries().FilterLevel(LogLevelError).Contains("from a batch") {
+		t.Error("Expected batches captured too")
+	}
+	if err := sink.WriteBatch([][]byte{[]byte("not json")}); err == nil {
+		t.Error("Expected undecodable entries rejected")
+	}
+	sink.Reset()
+	if len(sink.Entries()) != 0 {
+		t.Error("Expected Reset to discard entries")
+	}
+}
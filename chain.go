@@ -0,0 +1,246 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// Chain fields appended to each entry by ChainWriter.
+const (
+	ChainPrevField = "prev"
+	ChainHashField = "hash"
+)
+
+// ChainGenesis is the previous hash of the first entry of a chain.
+var ChainGenesis = string(bytes.Repeat([]byte("0"), hex.EncodedLen(sha256.Size)))
+
+var (
+	chainPrevSuffix = []byte(`,"` + ChainPrevField + `":"`)
+	chainHashSuffix = []byte(`,"` + ChainHashField + `":"`)
+)
+
+// chainHashLen is the length of the encoded hash field with the closing
+// brace.
+var chainHashLen = len(chainHashSuffix) + len(ChainGenesis) + len(`"}`)
+
+// Anchor records the head of a hash chain at a point in time. Storing
+// anchors outside the log, for example in a separate system or a signed
+// report, lets VerifyChain detect entries cut from the end of the log.
+type Anchor struct {
+	// Entries is the number of entries chained so far.
+	Entries uint64
+	// Hash is the hash of the last of them.
+	Hash string
+	// Time is when the anchor was taken.
+	Time time.Time
+}
+
+// ChainWriter is a log.Writer for audit streams that links every JSON
+// entry to the one before it: it appends a "prev" field holding the hash
+// of the previous entry and a "hash" field holding the hex SHA-256 of the
+// entry as encoded up to and including "prev". Altering an entry breaks its
+// hash and deleting one breaks the link of its successor, which
+// VerifyChain reports.
+//
+// Every AnchorEvery entries, and on Close, ChainWriter also writes an
+// anchor entry with the message "logging: chain anchor" and passes the
+// Anchor to OnAnchor, if set.
+type ChainWriter struct {
+	// Writer is the destination of entries.
+	Writer log.Writer
+
+	// Prev is the hash of the last entry of an existing chain to continue,
+	// as returned by VerifyChain. Empty starts a new chain at ChainGenesis.
+	Prev string
+
+	// AnchorEvery is the number of entries between anchors. Defaults to
+	// 1000; a negative value writes anchors only on Close.
+	AnchorEvery int
+
+	// OnAnchor, if set, receives every anchor written.
+	OnAnchor func(Anchor)
+
+	mu      sync.Mutex
+	entries uint64
+	since   int
+}
+
+// NewChainWriter returns a ChainWriter chaining the entries written to w.
+func NewChainWriter(w log.Writer) *ChainWriter {
+	return &ChainWriter{Writer: w}
+}
+
+// WriteEntry implements log.Writer.
+func (w *ChainWriter) WriteEntry(e *log.Entry) (int, error) {
+	b := getEntryBuffer()
+	defer putEntryBuffer(b)
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.write(*b, e.Level)
+	if err != nil {
+		return n, err
+	}
+	w.since++
+	if every := w.anchorEvery(); every > 0 && w.since >= every {
+		err = w.anchor()
+	}
+	return n, err
+}
+
+// write chains one encoded entry and writes it.
+func (w *ChainWriter) write(entry []byte, level log.Level) (int, error) {
+	body := bytes.TrimRight(entry, "\n")
+	if len(body) == 0 || body[len(body)-1] != '}' {
+		return 0, errors.New("logging: chain: entry is not a JSON object")
+	}
+	if w.Prev == "" {
+		w.Prev = ChainGenesis
+	}
+	chained := make([]byte, 0, len(body)+len(chainPrevSuffix)+len(w.Prev)+1+chainHashLen)
+	chained = append(chained, body[:len(body)-1]...)
+	chained = append(chained, chainPrevSuffix...)
+	chained = append(chained, w.Prev...)
+	chained = append(chained, '"')
+	sum := chainSum(chained)
+	chained = append(chained, chainHashSuffix...)
+	chained = append(chained, sum...)
+	chained = append(chained, "\"}\n"...)
+
+	c := log.NewContext(chained)
+	c.Level = level
+	n, err := w.Writer.WriteEntry(c)
+	if err != nil {
+		return n, err
+	}
+	w.Prev = sum
+	w.entries++
+	return n, nil
+}
+
+// anchor writes an anchor entry for the current head of the chain.
+func (w *ChainWriter) anchor() error {
+	w.since = 0
+	if w.entries == 0 {
+		return nil
+	}
+	a := Anchor{Entries: w.entries, Hash: w.Prev, Time: time.Now()}
+	entry := fmt.Appendf(nil, `{"time":%q,"level":"info","message":"logging: chain anchor","chain_entries":%d,"chain_head":%q}`,
+		a.Time.UTC().Format(time.RFC3339Nano), a.Entries, a.Hash)
+	if _, err := w.write(entry, log.InfoLevel); err != nil {
+		return err
+	}
+	if w.OnAnchor != nil {
+		w.OnAnchor(a)
+	}
+	return nil
+}
+
+func (w *ChainWriter) anchorEvery() int {
+	if w.AnchorEvery == 0 {
+		return 1000
+	}
+	return w.AnchorEvery
+}
+
+// Head returns the hash of the last entry written, to persist and pass as
+// Prev when the chain continues in a new process.
+func (w *ChainWriter) Head() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.Prev == "" {
+		return ChainGenesis
+	}
+	return w.Prev
+}
+
+// Flush flushes Writer if it buffers entries.
+func (w *ChainWriter) Flush() error {
+	return flushWriter(w.Writer)
+}
+
+// Close writes a final anchor and closes Writer if it is closable.
+func (w *ChainWriter) Close() error {
+	w.mu.Lock()
+	err := w.anchor()
+	w.mu.Unlock()
+	if cerr := closeWriter(w.Writer); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// chainSum returns the hex SHA-256 of a chained entry up to its hash field,
+// closing the JSON object it belongs to.
+func chainSum(chained []byte) string {
+	h := sha256.New()
+	h.Write(chained)
+	h.Write([]byte("}"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ChainError reports where VerifyChain found a chain broken.
+type ChainError struct {
+	// Line is the 1-based line number of the offending entry.
+	Line int
+	// Reason describes the break.
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("logging: chain broken at line %d: %s", e.Line, e.Reason)
+}
+
+// VerifyChain reads entries written by a ChainWriter from r and checks
+// that each is unaltered and linked to the one before it, starting from
+// prev, or ChainGenesis if empty. It returns the hash of the last entry and
+// the number of entries read; compare them with a stored Anchor to detect
+// entries cut from the end. A break is reported as a *ChainError.
+func VerifyChain(r io.Reader, prev string) (head string, n int, err error) {
+	if prev == "" {
+		prev = ChainGenesis
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		line := bytes.TrimRight(sc.Bytes(), "\r")
+		if len(line) == 0 {
+			continue
+		}
+		n++
+		entryPrev, sum, ok := splitChained(line)
+		switch {
+		case !ok:
+			return prev, n - 1, &ChainError{Line: n, Reason: "entry is not chained"}
+		case chainSum(line[:len(line)-chainHashLen]) != sum:
+			return prev, n - 1, &ChainError{Line: n, Reason: "entry was altered"}
+		case entryPrev != prev:
+			return prev, n - 1, &ChainError{Line: n, Reason: "previous entry is missing"}
+		}
+		prev = sum
+	}
+	return prev, n, sc.Err()
+}
+
+// splitChained returns the prev and hash fields of a chained entry.
+func splitChained(line []byte) (prev, sum string, ok bool) {
+	h := len(line) - chainHashLen
+	if h < 0 || !bytes.HasPrefix(line[h:], chainHashSuffix) || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return "", "", false
+	}
+	p := h - len(ChainGenesis) - 1 - len(chainPrevSuffix)
+	if p < 0 || !bytes.HasPrefix(line[p:], chainPrevSuffix) {
+		return "", "", false
+	}
+	return string(line[p+len(chainPrevSuffix) : h-1]), string(line[h+len(chainHashSuffix) : len(line)-2]), true
+}
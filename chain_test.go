@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestChainWriterDetectsTampering(t *testing.T) {
+	var buf bytes.Buffer
+	var anchors []Anchor
+	cw := NewChainWriter(&log.IOWriter{Writer: &buf})
+	cw.AnchorEvery = 2
+	cw.OnAnchor = func(a Anchor) { anchors = append(anchors, a) }
+	logger := NewLogger(WithWriter(cw))
+	for _, user := range []string{"alice", "bob", "carol"} {
+		logger.WithFields(Fields{"actor": user}).Info("role granted")
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	head, n, err := VerifyChain(strings.NewReader(buf.String()), "")
+	if err != nil || n != 5 || head != cw.Head() {
+		t.Fatalf("Expected 3 entries and 2 anchors verified up to the head, got %d, %v", n, err)
+	}
+	if len(anchors) != 2 || anchors[0].Entries != 2 || anchors[1].Entries != 4 || anchors[1].Hash == head {
+		t.Errorf("Expected anchors after 2 entries and on Close, got %+v", anchors)
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	altered := strings.Replace(buf.String(), "bob", "eve", 1)
+	deleted := strings.Join(append(lines[:1:1], lines[2:]...), "")
+	for name, stream := range map[string]string{"altered": altered, "deleted": deleted, "unchained": `{"level":"info"}` + "\n" + buf.String()} {
+		var ce *ChainError
+		if _, _, err := VerifyChain(strings.NewReader(stream), ""); !errors.As(err, &ce) {
+			t.Errorf("Expected a ChainError for the %s stream, got %v", name, err)
+		}
+	}
+
+	var more bytes.Buffer
+	next := NewChainWriter(&log.IOWriter{Writer: &more})
+	next.Prev = head
+	NewLogger(WithWriter(next)).Info("restarted")
+	if _, _, err := VerifyChain(strings.NewReader(buf.String()+more.String()), ""); err != nil {
+		t.Errorf("Expected a continued chain to verify, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestClockDeterministicTimestamps(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), WithClock(NewStepClock(start, 1500*time.Millisecond)))
+	logger.Info("first")
+	logger.WithFields(Fields{"k": 1}).Warning("second %d", 2)
+	logger.Error("")
+
+	want := `{"time":"2024-05-01 12:00:00","level":"info","message":"first"}
+{"time":"2024-05-01 12:00:01","level":"warn","k":1,"message":"second 2"}
+{"time":"2024-05-01 12:00:03","level":"error"}
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected output:\n got %s\nwant %s", buf.String(), want)
+	}
+
+	rec := NewRecorder(WithClock(ClockFunc(func() time.Time { return start })))
+	rec.Info("recorded")
+	if got := rec.Entries()[0].Time; !got.Equal(start) {
+		t.Errorf("Expected recorded entries to use the clock, got %v", got)
+	}
+
+	logger.SetClock(nil)
+	buf.Reset()
+	logger.Info("now")
+	if strings.Contains(buf.String(), "2024-05-01") {
+		t.Errorf("Expected the system clock restored, got %s", buf.String())
+	}
+}
+
+func TestClockTimeFormats(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 250e6, time.UTC)
+	tests := map[string]string{
+		"":                       `"2024-05-01T12:00:00.250Z"`,
+		log.TimeFormatUnix:       `1714564800`,
+		log.TimeFormatUnixMs:     `1714564800250`,
+		log.TimeFormatUnixWithMs: `1714564800.250`,
+	}
+	for format, want := range tests {
+		var buf bytes.Buffer
+		logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), WithTimeFormat(format),
+			WithClock(ClockFunc(func() time.Time { return at })))
+		logger.logger.TimeLocation = time.UTC
+		logger.Info("x")
+		if !strings.HasPrefix(buf.String(), `{"time":`+want+`,"level":"info"`) {
+			t.Errorf("format %q: got %s", format, buf.String())
+		}
+
+		var sys bytes.Buffer
+		system := NewLogger(WithOutput(&sys), WithFormat(FormatJSON), WithTimeFormat(format))
+		system.logger.TimeLocation = time.UTC
+		system.Info("x")
+		shape := regexp.MustCompile(`\d`)
+		if shape.ReplaceAllString(sys.String(), "0") != shape.ReplaceAllString(buf.String(), "0") {
+			t.Errorf("format %q: expected the encoder's layout, got %s and %s", format, buf.String(), sys.String())
+		}
+	}
+}
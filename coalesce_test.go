@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingWriter records how many Write calls it receives.
+type countingWriter struct {
+	mu     sync.Mutex
+	writes int
+	buf    bytes.Buffer
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func (w *countingWriter) stats() (int, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writes, bytes.Count(w.buf.Bytes(), []byte("\n"))
+}
+
+func TestCoalescingWriterLatency(t *testing.T) {
+	sink := &countingWriter{}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(&CoalescingWriter{Writer: sink, Latency: 5 * time.Millisecond})
+
+	for i := 0; i < 50; i++ {
+		logger.Info("entry %d", i)
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, lines := sink.stats(); lines == 50 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	writes, lines := sink.stats()
+	if lines != 50 {
+		t.Fatalf("Expected 50 entries after the latency budget, got %d", lines)
+	}
+	if writes != 1 {
+		t.Errorf("Expected a single coalesced write, got %d", writes)
+	}
+}
+
+func TestCoalescingWriterSize(t *testing.T) {
+	sink := &countingWriter{}
+	w := &CoalescingWriter{Writer: sink, Size: 10, Latency: time.Hour}
+
+	w.Write([]byte("12345"))
+	if writes, _ := sink.stats(); writes != 0 {
+		t.Errorf("Expected no write below Size, got %d", writes)
+	}
+	w.Write([]byte("67890"))
+	if writes, _ := sink.stats(); writes != 1 {
+		t.Errorf("Expected a write once Size is reached, got %d", writes)
+	}
+}
+
+func TestCoalescingWriterFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("Failed to create log file: %v", err)
+	}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewCoalescingWriter(f))
+
+	logger.Info("persisted")
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !bytes.Contains(data, []byte("persisted")) {
+		t.Errorf("Expected entry in file after Flush, got %q", data)
+	}
+	f.Close()
+}
@@ -0,0 +1,349 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/phuslu/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a logger declaratively, so deployments can configure
+// logging without code changes. It is usually loaded with LoadConfig, or
+// with FromMap from a generic configuration library. Its fields carry json
+// and mapstructure tags, so it can also be embedded in an application's own
+// config struct and decoded by viper.
+type Config struct {
+	// Profile selects a Profile ("quiet", "normal", "verbose" or "debug")
+	// applied before the other settings.
+	Profile string `json:"profile" mapstructure:"profile"`
+
+	// Level is the minimum level: "debug", "info", "warning" or "error".
+	// It may also be a level spec such as "info,http=debug" setting the
+	// levels of named loggers; see ParseLevelSpec. Defaults to "info".
+	Level string `json:"level" mapstructure:"level"`
+
+	// Format is the default encoding of sinks: "console" or "json".
+	// Defaults to "console".
+	Format string `json:"format" mapstructure:"format"`
+
+	// TimeFormat is the layout of the time field, as accepted by
+	// time.Format. Defaults to "2006-01-02 15:04:05".
+	TimeFormat string `json:"time_format" mapstructure:"time_format"`
+
+	// Caller adds the file and line of the logging call to each entry.
+	Caller bool `json:"caller" mapstructure:"caller"`
+
+	// Color enables colors in console sinks, overriding the NO_COLOR and
+	// related environment variables. Defaults to the environment.
+	Color *bool `json:"color" mapstructure:"color"`
+
+	// Sinks lists the destinations of entries. Defaults to stdout.
+	Sinks []SinkConfig `json:"sinks" mapstructure:"sinks"`
+
+	// BuildInfo attaches the service version, VCS revision and Go version
+	// to every entry; see WithBuildInfo.
+	BuildInfo bool `json:"build_info" mapstructure:"build_info"`
+
+	// Sampling keeps one in every N entries of the given levels, for
+	// example {"debug": 100}.
+	Sampling map[string]uint64 `json:"sampling" mapstructure:"sampling"`
+}
+
+// SinkConfig describes one destination of entries.
+type SinkConfig struct {
+	// Type is "stdout", "stderr" or "file".
+	Type string `json:"type" mapstructure:"type"`
+
+	// Path is the file written by "file" sinks.
+	Path string `json:"path" mapstructure:"path"`
+
+	// Format overrides Config.Format for this sink.
+	Format string `json:"format" mapstructure:"format"`
+
+	// Rotation configures rotation of "file" sinks.
+	Rotation RotationConfig `json:"rotation" mapstructure:"rotation"`
+
+	// Encryption encrypts "file" sinks at rest.
+	Encryption EncryptionConfig `json:"encryption" mapstructure:"encryption"`
+}
+
+// EncryptionConfig describes the encryption of a file sink with an
+// EncryptingWriter. The key itself never appears in the configuration.
+type EncryptionConfig struct {
+	// KeyEnv names the environment variable holding the base64-encoded AES
+	// key. Empty disables encryption.
+	KeyEnv string `json:"key_env" mapstructure:"key_env"`
+
+	// KeyID is stored with each record to name the key for decryption.
+	KeyID string `json:"key_id" mapstructure:"key_id"`
+}
+
+// RotationConfig describes when file sinks are rotated.
+type RotationConfig struct {
+	// MaxSize is the size in bytes at which the file is rotated. Zero
+	// disables rotation.
+	MaxSize int64 `json:"max_size" mapstructure:"max_size"`
+
+	// MaxBackups is the number of rotated files to keep. Zero keeps all.
+	MaxBackups int `json:"max_backups" mapstructure:"max_backups"`
+
+	// LocalTime names rotated files using local time instead of UTC.
+	LocalTime bool `json:"local_time" mapstructure:"local_time"`
+}
+
+// LoadConfig reads a Config from path and validates it. The format is
+// chosen by the extension: JSON for .json or none, YAML for .yaml and .yml,
+// TOML for .toml. Keys are those of the JSON form in every format, and
+// unknown keys are rejected so typos do not silently fall back to defaults.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("logging: read config: %w", err)
+	}
+	return parseConfigFile(path, data)
+}
+
+// parseConfigFile parses data, read from path, in the format named by the
+// extension of path.
+func parseConfigFile(path string, data []byte) (*Config, error) {
+	var m map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", "":
+		return parseConfig(data)
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("logging: parse config: %w", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("logging: parse config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("logging: unsupported config format %q, use .json, .yaml, .yml or .toml", ext)
+	}
+	return FromMap(m)
+}
+
+func parseConfig(data []byte) (*Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("logging: parse config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// FromMap builds a validated Config from m, as returned for a "logging"
+// section by configuration libraries such as viper's Get or AllSettings.
+// Keys are matched like LoadConfig matches JSON keys, and unknown keys are
+// rejected. Nested maps may have keys of any type with a string form.
+func FromMap(m map[string]any) (*Config, error) {
+	data, err := json.Marshal(normalizeMap(m))
+	if err != nil {
+		return nil, fmt.Errorf("logging: parse config: %w", err)
+	}
+	return parseConfig(data)
+}
+
+// normalizeMap converts the map[any]any values produced by some YAML
+// decoders into map[string]any so they can be encoded as JSON.
+func normalizeMap(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = normalizeMap(e)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[fmt.Sprint(k)] = normalizeMap(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = normalizeMap(e)
+		}
+		return out
+	}
+	return v
+}
+
+// NewLoggerFromConfig creates a Logger as described by cfg. A nil cfg
+// yields the same logger as NewLogger().
+func NewLoggerFromConfig(cfg *Config) (*Logger, error) {
+	if cfg == nil {
+		return NewLogger(), nil
+	}
+	o, sampler, err := cfg.build()
+	if err != nil {
+		return nil, err
+	}
+	logger := newLogger(o)
+	if sampler != nil {
+		logger.SetSampler(sampler)
+	}
+	return logger, nil
+}
+
+// build validates cfg and returns the logger options and sampler it
+// describes. The sinks are opened, so the caller owns o.writer.
+func (cfg *Config) build() (*options, Sampler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	o := defaultOptions()
+	if cfg.Profile != "" {
+		p, err := ParseProfile(cfg.Profile)
+		if err != nil {
+			return nil, nil, err
+		}
+		p.apply(&o)
+	}
+	if cfg.Level != "" {
+		def, ok, levels, err := ParseLevelSpec(cfg.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			o.level = def
+		}
+		o.levels = levels
+	}
+	if cfg.TimeFormat != "" {
+		o.timeFormat = cfg.TimeFormat
+	}
+	if cfg.Color != nil {
+		o.color = cfg.Color
+	}
+	o.caller = o.caller || cfg.Caller
+	o.buildInfo = cfg.BuildInfo
+	format, err := parseFormat(cfg.Format, FormatConsole)
+	if err != nil {
+		return nil, nil, err
+	}
+	o.format = format
+	sampler, err := cfg.sampler()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sinks := cfg.Sinks
+	if len(sinks) == 0 {
+		sinks = []SinkConfig{{Type: "stdout"}}
+	}
+	writers := make(log.MultiEntryWriter, 0, len(sinks))
+	for _, sc := range sinks {
+		w, err := sc.writer(&o)
+		if err != nil {
+			_ = closeWriter(&writers)
+			return nil, nil, err
+		}
+		writers = append(writers, w)
+	}
+	o.writer = &writers
+	if len(writers) == 1 {
+		o.writer = writers[0]
+	}
+	return &o, sampler, nil
+}
+
+func (cfg *Config) sampler() (Sampler, error) {
+	if len(cfg.Sampling) == 0 {
+		return nil, nil
+	}
+	s := make(LevelSampler, len(cfg.Sampling))
+	for name, n := range cfg.Sampling {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		s[level] = EveryN(n)
+	}
+	return s, nil
+}
+
+// writer builds the sink described by sc, using o for defaults.
+func (sc SinkConfig) writer(o *options) (log.Writer, error) {
+	format, err := parseFormat(sc.Format, o.format)
+	if err != nil {
+		return nil, err
+	}
+	var out io.Writer
+	switch sc.Type {
+	case "stdout", "":
+		out = os.Stdout
+	case "stderr":
+		out = os.Stderr
+	case "file":
+		if sc.Path == "" {
+			return nil, fmt.Errorf("logging: file sink requires a path")
+		}
+		fw := &log.FileWriter{
+			Filename:     sc.Path,
+			MaxSize:      sc.Rotation.MaxSize,
+			MaxBackups:   sc.Rotation.MaxBackups,
+			LocalTime:    sc.Rotation.LocalTime,
+			EnsureFolder: true,
+		}
+		if sc.Encryption.KeyEnv != "" {
+			ew, err := sc.Encryption.writer(fw)
+			if err != nil {
+				return nil, err
+			}
+			out = ew
+			break
+		}
+		if format == FormatJSON {
+			return fw, nil
+		}
+		out = fw
+	default:
+		return nil, fmt.Errorf("logging: unknown sink type %q", sc.Type)
+	}
+	so := *o
+	so.output, so.format = out, format
+	if sc.Type == "file" {
+		so.color = new(bool)
+	}
+	return so.newWriter(), nil
+}
+
+// writer returns an EncryptingWriter to w with the key from the environment.
+func (ec EncryptionConfig) writer(w io.Writer) (*EncryptingWriter, error) {
+	encoded := os.Getenv(ec.KeyEnv)
+	if encoded == "" {
+		return nil, fmt.Errorf("logging: encryption key variable %s is not set", ec.KeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("logging: encryption key variable %s: %w", ec.KeyEnv, err)
+	}
+	return NewEncryptingWriter(w, key, ec.KeyID)
+}
+
+func parseFormat(s string, def Format) (Format, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return def, nil
+	case "console", "text":
+		return FormatConsole, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return def, fmt.Errorf("unknown format %q (valid: console, json)", s)
+}
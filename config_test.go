@@ -0,0 +1,189 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, "logging.json", `{
+		"level": "warning",
+		"format": "json",
+		"sinks": [{"type": "file", "path": "app.log", "rotation": {"max_size": 1048576, "max_backups": 3}}],
+		"sampling": {"debug": 10}
+	}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Level != "warning" || cfg.Format != "json" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+	if len(cfg.Sinks) != 1 || cfg.Sinks[0].Rotation.MaxBackups != 3 {
+		t.Errorf("Unexpected sinks: %+v", cfg.Sinks)
+	}
+	if cfg.Sampling["debug"] != 10 {
+		t.Errorf("Unexpected sampling: %v", cfg.Sampling)
+	}
+}
+
+func TestLoadConfigRejectsUnknownKeys(t *testing.T) {
+	path := writeConfig(t, "logging.json", `{"levle": "debug"}`)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+}
+
+func TestLoadConfigYAMLAndTOML(t *testing.T) {
+	files := map[string]string{
+		"logging.yaml": `
+level: warning
+format: json
+sinks:
+  - type: file
+    path: app.log
+    rotation: {max_size: 1048576, max_backups: 3}
+sampling:
+  debug: 10
+`,
+		"logging.toml": `
+level = "warning"
+format = "json"
+
+[[sinks]]
+type = "file"
+path = "app.log"
+rotation = { max_size = 1048576, max_backups = 3 }
+
+[sampling]
+debug = 10
+`,
+	}
+	for name, data := range files {
+		cfg, err := LoadConfig(writeConfig(t, name, data))
+		if err != nil {
+			t.Fatalf("%s: LoadConfig failed: %v", name, err)
+		}
+		if cfg.Level != "warning" || cfg.Format != "json" || cfg.Sampling["debug"] != 10 {
+			t.Errorf("%s: unexpected config: %+v", name, cfg)
+		}
+		if len(cfg.Sinks) != 1 || cfg.Sinks[0].Path != "app.log" || cfg.Sinks[0].Rotation.MaxBackups != 3 {
+			t.Errorf("%s: unexpected sinks: %+v", name, cfg.Sinks)
+		}
+	}
+
+	for name, data := range map[string]string{"typo.yml": "levle: debug\n", "typo.toml": "levle = \"debug\"\n", "bad.yaml": "level: [\n"} {
+		if _, err := LoadConfig(writeConfig(t, name, data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadConfigRejectsUnsupportedFormat(t *testing.T) {
+	path := writeConfig(t, "logging.ini", "level=debug\n")
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("Expected an unsupported format error, got %v", err)
+	}
+}
+
+func TestNewLoggerFromConfigFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	logger, err := NewLoggerFromConfig(&Config{
+		Level: "warning",
+		Sinks: []SinkConfig{{Type: "file", Path: path, Format: "json"}},
+	})
+	if err != nil {
+		t.Fatalf("NewLoggerFromConfig failed: %v", err)
+	}
+
+	logger.Info("filtered")
+	logger.Warning("kept")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	entry, err := parseLogEntry(bytes.NewBuffer(data))
+	if err != nil {
+		t.Fatalf("Expected a JSON entry: %v (%q)", err, data)
+	}
+	if entry.Message != "kept" {
+		t.Errorf("Expected only the warning, got %q", data)
+	}
+}
+
+func TestNewLoggerFromConfigErrors(t *testing.T) {
+	tests := map[string]*Config{
+		"level":        {Level: "loud"},
+		"format":       {Format: "xml"},
+		"sink type":    {Sinks: []SinkConfig{{Type: "syslog"}}},
+		"sink path":    {Sinks: []SinkConfig{{Type: "file"}}},
+		"sample level": {Sampling: map[string]uint64{"trace": 2}},
+	}
+	for name, cfg := range tests {
+		if _, err := NewLoggerFromConfig(cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNewLoggerFromConfigSampling(t *testing.T) {
+	logger, err := NewLoggerFromConfig(&Config{Level: "debug", Sampling: map[string]uint64{"debug": 2}})
+	if err != nil {
+		t.Fatalf("NewLoggerFromConfig failed: %v", err)
+	}
+	var buf bytes.Buffer
+	logger.SetWriter(&log.IOWriter{Writer: &buf})
+	for i := 0; i < 4; i++ {
+		logger.Debug("tick")
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 2 {
+		t.Errorf("Expected 2 of 4 debug entries, got %d", n)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	cfg, err := FromMap(map[string]any{
+		"level":  "debug",
+		"format": "json",
+		"sinks": []any{
+			map[any]any{"type": "file", "path": "app.log", "rotation": map[any]any{"max_size": 1024, "max_backups": 2}},
+		},
+		"sampling": map[string]any{"debug": 10},
+	})
+	if err != nil {
+		t.Fatalf("FromMap failed: %v", err)
+	}
+	if cfg.Level != "debug" || cfg.Format != "json" || cfg.Sampling["debug"] != 10 {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+	if len(cfg.Sinks) != 1 || cfg.Sinks[0].Rotation.MaxSize != 1024 || cfg.Sinks[0].Rotation.MaxBackups != 2 {
+		t.Errorf("Unexpected sinks: %+v", cfg.Sinks)
+	}
+}
+
+func TestFromMapRejectsInvalid(t *testing.T) {
+	if _, err := FromMap(map[string]any{"levle": "debug"}); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+	if _, err := FromMap(map[string]any{"level": "loud"}); err == nil {
+		t.Error("Expected a validation error")
+	}
+}
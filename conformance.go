@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// EntrySource is implemented by loggers whose entries can be inspected,
+// such as Recorder. TestLoggerInterface checks the level filtering and
+// message formatting of implementations that provide it.
+type EntrySource interface {
+	Entries() Entries
+}
+
+// TestLoggerInterface runs a conformance suite against the LoggerInterface
+// implementations returned by newLogger, which must return a fresh one on
+// every call. Run it from the tests of a mock or adapter so it keeps
+// behaving like Logger as the interface grows:
+//
+//	func TestMockLogger(t *testing.T) {
+//		logging.TestLoggerInterface(t, func() logging.LoggerInterface { return newMockLogger() })
+//	}
+//
+// Every implementation must accept calls from concurrent goroutines, return
+// nil from Flush and Close, and accept a second Close. Implementations that
+// also implement EntrySource must record exactly the entries at or above the
+// level set by SetLogLevel, which applies immediately, with their arguments
+// formatted as by fmt.Sprintf, and a message without arguments verbatim.
+func TestLoggerInterface(t *testing.T, newLogger func() LoggerInterface) {
+	t.Helper()
+	levels := []LogLevel{LogLevelInfo, LogLevelWarning, LogLevelError}
+
+	t.Run("levels", func(t *testing.T) {
+		for _, minLevel := range append([]LogLevel{LogLevelDebug}, levels...) {
+			l := newLogger()
+			l.SetLogLevel(minLevel)
+			logEach(l, "message")
+			src, ok := l.(EntrySource)
+			if !ok {
+				continue
+			}
+			var want []LogLevel
+			for _, level := range levels {
+				if level >= minLevel {
+					want = append(want, level)
+				}
+			}
+			if got := entryLevels(src.Entries()); !slices.Equal(got, want) {
+				t.Errorf("At level %s, logged %v, want %v", minLevel, got, want)
+			}
+		}
+	})
+
+	t.Run("level change", func(t *testing.T) {
+		l := newLogger()
+		l.SetLogLevel(LogLevelError)
+		l.Warning("dropped")
+		l.SetLogLevel(LogLevelWarning)
+		l.Warning("kept")
+		l.Info("dropped")
+		if src, ok := l.(EntrySource); ok {
+			entries := src.Entries()
+			if len(entries) != 1 || entries[0].Level != LogLevelWarning || entries[0].Message != "kept" {
+				t.Errorf("Expected only the warning logged after lowering the level, got %v", entries)
+			}
+		}
+	})
+
+	t.Run("formatting", func(t *testing.T) {
+		l := newLogger()
+		l.SetLogLevel(LogLevelInfo)
+		l.Info("user %s has %d items", "alice", 3)
+		l.Warning("disk at 95%")
+		l.Error("%v", nil)
+		src, ok := l.(EntrySource)
+		if !ok {
+			return
+		}
+		want := []string{"user alice has 3 items", "disk at 95%", "<nil>"}
+		entries := src.Entries()
+		if len(entries) != len(want) {
+			t.Fatalf("Expected %d entries, got %v", len(want), entries)
+		}
+		for i, e := range entries {
+			if e.Message != want[i] {
+				t.Errorf("Entry %d: got message %q, want %q", i, e.Message, want[i])
+			}
+		}
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		l := newLogger()
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 50 {
+					if i%10 == 0 {
+						l.SetLogLevel(levels[(g+i)%len(levels)])
+					}
+					logEach(l, "goroutine %d entry %d", g, i)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("flush and close", func(t *testing.T) {
+		l := newLogger()
+		l.Info("before flush")
+		if err := l.Flush(); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+		if err := l.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+		if err := l.Close(); err != nil {
+			t.Errorf("Second Close: %v", err)
+		}
+	})
+}
+
+// logEach logs the message at Info, Warning and Error level.
+func logEach(l LoggerInterface, format string, v ...any) {
+	l.Info(format, v...)
+	l.Warning(format, v...)
+	l.Error(format, v...)
+}
+
+func entryLevels(entries Entries) []LogLevel {
+	levels := make([]LogLevel, len(entries))
+	for i, e := range entries {
+		levels[i] = e.Level
+	}
+	return levels
+}
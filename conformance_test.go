@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+// mockLogger is the kind of hand-written mock TestLoggerInterface guards.
+type mockLogger struct {
+	mu      sync.Mutex
+	level   LogLevel
+	entries Entries
+}
+
+func (m *mockLogger) log(level LogLevel, format string, v []any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if level < m.level {
+		return
+	}
+	if len(v) > 0 {
+		format = fmt.Sprintf(format, v...)
+	}
+	m.entries = append(m.entries, Entry{Level: level, Message: format})
+}
+
+func (m *mockLogger) Info(format string, v ...any)    { m.log(LogLevelInfo, format, v) }
+func (m *mockLogger) Warning(format string, v ...any) { m.log(LogLevelWarning, format, v) }
+func (m *mockLogger) Error(format string, v ...any)   { m.log(LogLevelError, format, v) }
+func (m *mockLogger) Flush() error                    { return nil }
+func (m *mockLogger) Close() error                    { return nil }
+
+func (m *mockLogger) SetLogLevel(level LogLevel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.level = level
+}
+
+func (m *mockLogger) Entries() Entries {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append(Entries(nil), m.entries...)
+}
+
+func TestLoggerInterfaceConformance(t *testing.T) {
+	t.Run("Logger", func(t *testing.T) {
+		TestLoggerInterface(t, func() LoggerInterface { return NewLogger(WithOutput(io.Discard)) })
+	})
+	t.Run("Recorder", func(t *testing.T) {
+		TestLoggerInterface(t, func() LoggerInterface { return NewRecorder() })
+	})
+	t.Run("mock", func(t *testing.T) {
+		TestLoggerInterface(t, func() LoggerInterface { return &mockLogger{level: LogLevelInfo} })
+	})
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// connIDs numbers the connections wrapped by NewConnLogger.
+var connIDs atomic.Uint64
+
+// ConnOptions configures connection logging.
+type ConnOptions struct {
+	// ChatterLimit caps the entries per message template each connection
+	// logs per ChatterInterval, so one noisy client cannot flood the log.
+	// Zero disables the cap.
+	ChatterLimit int
+
+	// ChatterInterval is the window of ChatterLimit. Defaults to 1s.
+	ChatterInterval time.Duration
+}
+
+// ConnLogger is a net.Conn that logs its lifetime: an entry when it is
+// opened and one when it is closed with its duration and the bytes read
+// and written. Log entries about the connection through Logger so they
+// carry its conn_id and remote_addr.
+type ConnLogger struct {
+	net.Conn
+
+	logger  *Logger
+	opened  time.Time
+	read    atomic.Int64
+	written atomic.Int64
+	once    sync.Once
+}
+
+// NewConnLogger wraps c, logging to l, and logs that it was opened.
+func NewConnLogger(l *Logger, c net.Conn, opts ConnOptions) *ConnLogger {
+	cl := l.WithFields(Fields{
+		"conn_id":     connIDs.Add(1),
+		"remote_addr": c.RemoteAddr().String(),
+	})
+	if opts.ChatterLimit > 0 {
+		cl.SetRateLimiter(NewRateLimiter(opts.ChatterLimit, opts.ChatterInterval))
+	}
+	cl.Info("connection opened")
+	return &ConnLogger{Conn: c, logger: cl, opened: time.Now()}
+}
+
+// Logger returns the logger tagged with the connection's fields.
+func (c *ConnLogger) Logger() *Logger {
+	return c.logger
+}
+
+// Read implements net.Conn, counting the bytes read.
+func (c *ConnLogger) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// Write implements net.Conn, counting the bytes written.
+func (c *ConnLogger) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// Close closes the connection and logs its duration and traffic. Only the
+// first call is logged.
+func (c *ConnLogger) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		fields := Fields{
+			"duration":      time.Since(c.opened),
+			"bytes_read":    c.read.Load(),
+			"bytes_written": c.written.Load(),
+		}
+		if err != nil {
+			fields["error"] = err
+		}
+		c.logger.WithFields(fields).Info("connection closed")
+	})
+	return err
+}
+
+// WrapListener returns a net.Listener whose accepted connections are
+// ConnLoggers logging to l.
+func WrapListener(l *Logger, ln net.Listener, opts ConnOptions) net.Listener {
+	return &connListener{Listener: ln, logger: l, opts: opts}
+}
+
+type connListener struct {
+	net.Listener
+	logger *Logger
+	opts   ConnOptions
+}
+
+func (ln *connListener) Accept() (net.Conn, error) {
+	c, err := ln.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return NewConnLogger(ln.logger, c, ln.opts), nil
+}
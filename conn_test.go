@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestConnLogger(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	server, client := net.Pipe()
+	c := NewConnLogger(logger, server, ConnOptions{ChatterLimit: 2})
+
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+		_, _ = io.ReadFull(client, make([]byte, 6))
+		client.Close()
+	}()
+	if _, err := io.ReadFull(c, make([]byte, 4)); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if _, err := c.Write([]byte("pong!!")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		c.Logger().Info("chatter")
+	}
+	c.Close()
+	c.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected open, two chatter and close entries, got %d: %q", len(lines), buf.Bytes())
+	}
+	var opened, closed map[string]any
+	_ = json.Unmarshal([]byte(lines[0]), &opened)
+	_ = json.Unmarshal([]byte(lines[3]), &closed)
+	if opened["message"] != "connection opened" || closed["message"] != "connection closed" {
+		t.Errorf("Unexpected lifecycle entries: %v, %v", opened, closed)
+	}
+	if closed["conn_id"] != opened["conn_id"] || closed["remote_addr"] == nil {
+		t.Errorf("Expected entries tagged with the connection, got %v", closed)
+	}
+	if closed["bytes_read"] != float64(4) || closed["bytes_written"] != float64(6) {
+		t.Errorf("Unexpected traffic counters: %v", closed)
+	}
+}
+
+func TestWrapListener(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen: %v", err)
+	}
+	wrapped := WrapListener(logger, ln, ConnOptions{})
+	defer wrapped.Close()
+	go func() {
+		if c, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+			c.Close()
+		}
+	}()
+	c, err := wrapped.Accept()
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	defer c.Close()
+	if _, ok := c.(*ConnLogger); !ok {
+		t.Errorf("Expected a *ConnLogger, got %T", c)
+	}
+}
@@ -0,0 +1,28 @@
+package logging
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l, for request-scoped loggers.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Logger carried by ctx, or Default if there is
+// none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+// withLevel returns a derived Logger with a level of its own, which
+// SetLogLevel on other loggers does not change.
+func (l *Logger) withLevel(level LogLevel) *Logger {
+	c := l.derive()
+	c.state = newLoggerState(level, l.state.tree)
+	c.state.entries = l.state.entries
+	return c
+}
@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	if got := FromContext(NewContext(context.Background(), logger)); got != logger {
+		t.Error("Expected the logger stored in the context")
+	}
+	if got := FromContext(context.Background()); got != Default() {
+		t.Error("Expected Default for a context without a logger")
+	}
+}
+
+func TestWithLevelIsIndependent(t *testing.T) {
+	logger, _ := testLogger(LogLevelWarning)
+	debug := logger.withLevel(LogLevelDebug)
+	logger.SetLogLevel(LogLevelError)
+	if debug.Level() != LogLevelDebug || logger.Level() != LogLevelError {
+		t.Errorf("Expected independent levels, got %v and %v", debug.Level(), logger.Level())
+	}
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// CrashOptions configures what a logger saves when the process dies from
+// Fatal, Exit or a panic caught by FlushOnPanic.
+type CrashOptions struct {
+	// Path is the crash file the entries of the ring buffer (see
+	// SetRingBuffer) are appended to, so the events leading up to a crash
+	// survive even when the sinks lost them. "-" writes them to stderr;
+	// empty skips the dump.
+	Path string
+
+	// FlushTimeout bounds flushing the writer chain, so a hung sink cannot
+	// keep the process alive. Defaults to 2s.
+	FlushTimeout time.Duration
+}
+
+// exitFunc terminates the process; tests replace it.
+var exitFunc = os.Exit
+
+// SetCrashOptions sets what l and every logger derived or named from the
+// same root save when the process dies. Async buffers are flushed on
+// Fatal, Exit and FlushOnPanic even without crash options.
+func (l *Logger) SetCrashOptions(opts CrashOptions) {
+	l.state.tree.crash.Store(&opts)
+}
+
+// FlushOnPanic saves the state of l when a panic unwinds past it, then
+// lets the panic continue. Defer it at the top of main and of long-lived
+// goroutines:
+//
+//	defer logger.FlushOnPanic()
+//
+// The panic is logged at Error level with its stack, the writer chain is
+// flushed and the ring buffer written to the crash file.
+func (l *Logger) FlushOnPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	l.WithFields(Fields{"panic": fmt.Sprint(r), "stack": string(debug.Stack())}).Error("logging: process panicked")
+	l.crashFlush()
+	panic(r)
+}
+
+// Exit flushes l as on a crash and terminates the process with code. Use it
+// instead of os.Exit, which would drop buffered entries.
+func (l *Logger) Exit(code int) {
+	l.crashFlush()
+	exitFunc(code)
+}
+
+// crashFlush flushes the writer chain within the flush timeout and appends
+// the ring buffer to the crash file.
+func (l *Logger) crashFlush() {
+	var opts CrashOptions
+	if o := l.state.tree.crash.Load(); o != nil {
+		opts = *o
+	}
+	timeout := opts.FlushTimeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = l.Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+
+	ring := findRingWriter(l.logger.Writer)
+	if ring == nil || opts.Path == "" {
+		return
+	}
+	var w io.Writer = os.Stderr
+	if opts.Path != "-" {
+		f, err := os.OpenFile(opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+	for _, e := range ring.Entries() {
+		_, _ = w.Write(e)
+	}
+	if f, ok := w.(*os.File); ok {
+		_ = f.Sync()
+	}
+}
+
+// crashWriter runs the crash flush after writing the fatal entry, which
+// phuslu/log follows with os.Exit.
+type crashWriter struct {
+	log.Writer
+	l *Logger
+}
+
+func (w crashWriter) WriteEntry(e *log.Entry) (int, error) {
+	n, err := w.Writer.WriteEntry(e)
+	w.l.crashFlush()
+	return n, err
+}
+
+// findRingWriter returns the first RingWriter in the writer chain starting
+// at w, including the branches of fan-out writers, or nil.
+func findRingWriter(w any) *RingWriter {
+	var ring *RingWriter
+	walkWriters(w, func(w any) {
+		if rw, ok := w.(*RingWriter); ok && ring == nil {
+			ring = rw
+		}
+	})
+	return ring
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestExitFlushesAndDumpsRing(t *testing.T) {
+	code := -1
+	exitFunc = func(c int) { code = c }
+	defer func() { exitFunc = os.Exit }()
+
+	path := filepath.Join(t.TempDir(), "crash.log")
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetAsync(16)
+	logger.SetRingBuffer(10)
+	logger.SetCrashOptions(CrashOptions{Path: path})
+	logger.Info("first")
+	logger.Warning("second")
+	logger.Exit(3)
+
+	if code != 3 {
+		t.Errorf("Expected exit code 3, got %d", code)
+	}
+	if !strings.Contains(buf.String(), "second") {
+		t.Errorf("Expected async buffers flushed before exit, got %q", buf.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected a crash file: %v", err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 2 || !strings.Contains(string(data), "first") {
+		t.Errorf("Expected the ring buffer in the crash file, got %q", data)
+	}
+}
+
+func TestExitDumpsRingBehindFanOut(t *testing.T) {
+	exitFunc = func(int) {}
+	defer func() { exitFunc = os.Exit }()
+
+	path := filepath.Join(t.TempDir(), "crash.log")
+	sink := new(syncBuffer)
+	fanOut := log.MultiEntryWriter{log.IOWriter{Writer: io.Discard}, NewRingWriter(log.IOWriter{Writer: sink}, 10)}
+	logger := NewLogger(WithWriter(&fanOut))
+	logger.SetCrashOptions(CrashOptions{Path: path})
+	logger.Warning("before crash")
+	logger.Exit(1)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected a crash file: %v", err)
+	}
+	if !strings.Contains(string(data), "before crash") {
+		t.Errorf("Expected the ring buffer behind the fan-out in the crash file, got %q", data)
+	}
+}
+
+func TestFlushOnPanicRepanics(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetAsync(16)
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		defer logger.FlushOnPanic()
+		panic(errors.New("boom"))
+	}()
+
+	if err, ok := recovered.(error); !ok || err.Error() != "boom" {
+		t.Errorf("Expected the panic to continue, got %v", recovered)
+	}
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 1 || entries[0]["message"] != "logging: process panicked" || entries[0]["panic"] != "boom" {
+		t.Errorf("Expected the panic logged and flushed, got %v", entries)
+	}
+}
+
+func TestFatalFlushesBeforeExit(t *testing.T) {
+	if path := os.Getenv("LOGGING_TEST_FATAL"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			os.Exit(2)
+		}
+		logger := NewLogger(WithOutput(f), WithFormat(FormatJSON))
+		logger.SetAsync(16)
+		logger.Info("before the end")
+		logger.Fatal("giving up")
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatalFlushesBeforeExit$")
+	cmd.Env = append(os.Environ(), "LOGGING_TEST_FATAL="+path)
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 255 {
+		t.Fatalf("Expected Fatal to exit with 255, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "before the end") || !strings.Contains(string(data), "giving up") {
+		t.Errorf("Expected buffered entries flushed before exiting, got %q", data)
+	}
+}
@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Rejection identifies an entry of a batch a collector refused.
+type Rejection struct {
+	// Index is the position of the entry in the batch.
+	Index int
+	// Reason is the collector's explanation, such as a schema error.
+	Reason string
+}
+
+// RejectedError is returned by a sink whose collector accepted a batch
+// except for some entries. The rejected entries are not retried.
+type RejectedError struct {
+	Rejected []Rejection
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("logging: collector rejected %d entries", len(e.Rejected))
+}
+
+// DeadLetterSink is a BatchSink that writes the entries Sink permanently
+// rejects to a local dead-letter file with the rejection reason, so nothing
+// disappears silently. Entries are rejected by a RejectedError or by an
+// error wrapped with Permanent; other errors are returned for retrying.
+// Each dead letter is one JSON line:
+//
+//	{"time":"2024-05-01T12:00:00Z","reason":"collector returned 400 Bad Request","entry":{...}}
+type DeadLetterSink struct {
+	// Sink receives the batches.
+	Sink BatchSink
+
+	// Path is the dead-letter file, opened for appending on first use.
+	Path string
+
+	// Writer, if set, receives the dead letters instead of Path.
+	Writer io.Writer
+
+	mu       sync.Mutex
+	file     *os.File
+	counters deliveryCounters
+}
+
+// NewDeadLetterSink returns a DeadLetterSink delivering to sink and writing
+// rejected entries to the file at path.
+func NewDeadLetterSink(sink BatchSink, path string) *DeadLetterSink {
+	return &DeadLetterSink{Sink: sink, Path: path}
+}
+
+// WriteBatch implements BatchSink. Rejected entries count as handled once
+// they are in the dead-letter file; only the error of writing it is
+// returned for them.
+func (s *DeadLetterSink) WriteBatch(entries [][]byte) error {
+	err := s.Sink.WriteBatch(entries)
+	if err == nil {
+		s.counters.deliver(len(entries), batchBytes(entries))
+		return nil
+	}
+	var rejected *RejectedError
+	var perm permanentError
+	switch {
+	case errors.As(err, &rejected):
+		var derr error
+		refused := make(map[int]bool, len(rejected.Rejected))
+		for _, r := range rejected.Rejected {
+			if r.Index < 0 || r.Index >= len(entries) || refused[r.Index] {
+				continue
+			}
+			refused[r.Index] = true
+			if werr := s.deadLetter(entries[r.Index], r.Reason); derr == nil {
+				derr = werr
+			}
+		}
+		for i, e := range entries {
+			if !refused[i] {
+				s.counters.deliver(1, len(e))
+			}
+		}
+		return derr
+	case errors.As(err, &perm):
+		var derr error
+		for _, e := range entries {
+			if werr := s.deadLetter(e, err.Error()); derr == nil {
+				derr = werr
+			}
+		}
+		return derr
+	}
+	return err
+}
+
+// deadLetter appends entry with reason to the dead-letter file.
+func (s *DeadLetterSink) deadLetter(entry []byte, reason string) error {
+	record := struct {
+		Time   time.Time `json:"time"`
+		Reason string    `json:"reason"`
+		Entry  any       `json:"entry"`
+	}{Time: time.Now().UTC(), Reason: reason}
+	if trimmed := bytes.TrimSpace(entry); json.Valid(trimmed) {
+		record.Entry = json.RawMessage(trimmed)
+	} else {
+		record.Entry = string(trimmed)
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("logging: dead letter: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := s.Writer
+	if w == nil {
+		if s.file == nil {
+			f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return fmt.Errorf("logging: dead letter: %w", err)
+			}
+			s.file = f
+		}
+		w = s.file
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("logging: dead letter: %w", err)
+	}
+	s.counters.deadLettered.Add(1)
+	return nil
+}
+
+// DeadLettered returns the number of entries written to the dead-letter file.
+func (s *DeadLetterSink) DeadLettered() uint64 {
+	return s.counters.deadLettered.Load()
+}
+
+// Stats returns the delivery counters of s.
+func (s *DeadLetterSink) Stats() DeliveryStats {
+	return s.counters.snapshot()
+}
+
+// Close closes the dead-letter file and Sink if it is closable.
+func (s *DeadLetterSink) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+		s.file = nil
+	}
+	s.mu.Unlock()
+	if cerr := closeWriter(s.Sink); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeadLetterSinkPermanentFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	sink := NewDeadLetterSink(BatchFunc(func([][]byte) error {
+		return Permanent(errors.New("collector returned 400 Bad Request"))
+	}), path)
+	err := sink.WriteBatch([][]byte{[]byte(`{"message":"one"}` + "\n"), []byte("plain text\n")})
+	if err != nil {
+		t.Fatalf("Rejected entries should be handled once dead-lettered, got %v", err)
+	}
+	_ = sink.Close()
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || sink.DeadLettered() != 2 {
+		t.Fatalf("Expected 2 dead letters, got %q", data)
+	}
+	var first struct {
+		Reason string         `json:"reason"`
+		Entry  map[string]any `json:"entry"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Dead letters should be JSON lines: %v", err)
+	}
+	if first.Reason != "collector returned 400 Bad Request" || first.Entry["message"] != "one" {
+		t.Errorf("Unexpected dead letter: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"entry":"plain text"`) {
+		t.Errorf("Expected a non-JSON entry embedded as a string, got %s", lines[1])
+	}
+}
+
+func TestDeadLetterSinkPartialRejection(t *testing.T) {
+	var dead bytes.Buffer
+	sink := &DeadLetterSink{
+		Sink: BatchFunc(func([][]byte) error {
+			return &RejectedError{Rejected: []Rejection{{Index: 1, Reason: "field user_id: wrong type"}}}
+		}),
+		Writer: &dead,
+	}
+	if err := sink.WriteBatch([][]byte{[]byte(`{"n":0}`), []byte(`{"n":1}`), []byte(`{"n":2}`)}); err != nil {
+		t.Fatalf("WriteBatch failed: %v", err)
+	}
+	if n := strings.Count(dead.String(), "\n"); n != 1 || !strings.Contains(dead.String(), `"entry":{"n":1}`) {
+		t.Errorf("Expected only the rejected entry dead-lettered, got %q", dead.String())
+	}
+}
+
+func TestDeadLetterSinkReturnsTransientErrors(t *testing.T) {
+	var dead bytes.Buffer
+	sink := &DeadLetterSink{Sink: failingSink(), Writer: &dead}
+	if err := sink.WriteBatch([][]byte{[]byte(`{}`)}); !errors.Is(err, errCollectorDown) {
+		t.Errorf("Expected transient errors returned for retrying, got %v", err)
+	}
+	if dead.Len() != 0 {
+		t.Errorf("Transient failures should not be dead-lettered, got %q", dead.String())
+	}
+	var p RetryPolicy
+	if p.retryable(&RejectedError{}) {
+		t.Error("Expected rejected entries not to be retried")
+	}
+}
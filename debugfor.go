@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"time"
+)
+
+// debugWindow is a temporary Debug level set by EnableDebugFor.
+type debugWindow struct {
+	timer    *time.Timer
+	prev     LogLevel
+	explicit bool // whether a named logger had a level of its own
+}
+
+// EnableDebugFor enables Debug on the default logger for d; see
+// Logger.EnableDebugFor.
+func EnableDebugFor(d time.Duration) (restore func()) {
+	return Default().EnableDebugFor(d)
+}
+
+// EnableDebugFor sets the level of l to Debug and restores the previous
+// level after d, so debugging a production service cannot be left on by
+// accident. Calling it again before d has elapsed extends the window
+// instead of stacking; the level from before the first call is restored.
+// The returned function ends the window early.
+//
+// For a named logger only that logger and its inheriting descendants are
+// affected. The start and end of the window are logged.
+func (l *Logger) EnableDebugFor(d time.Duration) (restore func()) {
+	n := &l.state.tree.names
+	n.mu.Lock()
+	w, extended := n.windows[l.name]
+	if extended {
+		w.timer.Reset(d)
+	} else {
+		w = &debugWindow{prev: LogLevel(n.root.level.Load())}
+		if l.name != "" {
+			w.prev, w.explicit = n.explicit[l.name]
+		}
+		if n.windows == nil {
+			n.windows = make(map[string]*debugWindow)
+		}
+		n.windows[l.name] = w
+		n.setLevelLocked(l.name, LogLevelDebug)
+		w.timer = time.AfterFunc(d, func() { l.endDebug(w) })
+	}
+	n.mu.Unlock()
+
+	l.WithFields(Fields{"duration": d.String(), "extended": extended}).Warning("logging: debug enabled temporarily")
+	return func() { l.endDebug(w) }
+}
+
+// endDebug restores the level from before w began, unless w has already
+// ended.
+func (l *Logger) endDebug(w *debugWindow) {
+	n := &l.state.tree.names
+	n.mu.Lock()
+	if n.windows[l.name] != w {
+		n.mu.Unlock()
+		return
+	}
+	delete(n.windows, l.name)
+	w.timer.Stop()
+	switch {
+	case l.name == "" || w.explicit:
+		n.setLevelLocked(l.name, w.prev)
+	default:
+		delete(n.explicit, l.name)
+		n.update()
+	}
+	n.mu.Unlock()
+
+	l.WithFields(Fields{"level": l.Level().String()}).Info("logging: temporary debug ended, level restored")
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnableDebugForRestores(t *testing.T) {
+	logger := NewLogger(LogLevelWarning, WithOutput(new(syncBuffer)))
+	logger.EnableDebugFor(20 * time.Millisecond)
+	if logger.Level() != LogLevelDebug {
+		t.Fatalf("Expected debug, got %v", logger.Level())
+	}
+	waitFor(t, "the level to be restored", func() bool { return logger.Level() == LogLevelWarning })
+}
+
+func TestEnableDebugForExtends(t *testing.T) {
+	logger := NewLogger(LogLevelError, WithOutput(new(syncBuffer)))
+	logger.EnableDebugFor(time.Hour)
+	restore := logger.EnableDebugFor(time.Hour)
+	restore()
+	if logger.Level() != LogLevelError {
+		t.Errorf("Expected the level from before the first call, got %v", logger.Level())
+	}
+	restore()
+	if logger.Level() != LogLevelError {
+		t.Errorf("Restoring twice should be a no-op, got %v", logger.Level())
+	}
+}
+
+func TestEnableDebugForNamed(t *testing.T) {
+	logger := NewLogger(LogLevelInfo, WithOutput(new(syncBuffer)))
+	db := logger.Named("db")
+	pool := db.Named("pool")
+	restore := db.EnableDebugFor(time.Hour)
+	if logger.Level() != LogLevelInfo || pool.Level() != LogLevelDebug {
+		t.Errorf("Expected only db and its descendants at debug, got root=%v pool=%v", logger.Level(), pool.Level())
+	}
+	restore()
+	logger.SetLogLevel(LogLevelWarning)
+	if db.Level() != LogLevelWarning {
+		t.Errorf("db should inherit the root level again after the window, got %v", db.Level())
+	}
+}
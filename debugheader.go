@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDebugHeader is the request header read by DebugMiddleware.
+const DefaultDebugHeader = "X-Debug-Log"
+
+// DebugHeaderOptions configures DebugMiddleware. A request is elevated to
+// Debug if its header value is one of Tokens or a valid token signed with
+// Key, and Allow, if set, accepts it. With neither Tokens nor Key no
+// request is ever elevated.
+type DebugHeaderOptions struct {
+	// Header is the request header holding the token. Defaults to
+	// DefaultDebugHeader.
+	Header string
+
+	// Tokens are static values accepted in the header.
+	Tokens []string
+
+	// Key verifies tokens created by SignDebugToken, which expire.
+	Key []byte
+
+	// Allow, if set, must also accept the request, for example to check
+	// the client address against an allowlist.
+	Allow func(r *http.Request) bool
+}
+
+// DebugMiddleware returns HTTP middleware that stores a request-scoped
+// logger in the request context, retrieved with FromContext. For requests
+// carrying an accepted debug header (see DebugHeaderOptions) the logger is
+// at Debug level, so a single production request can be traced without
+// raising verbosity for all traffic; other requests get l unchanged.
+func (l *Logger) DebugMiddleware(opts DebugHeaderOptions) func(http.Handler) http.Handler {
+	header := opts.Header
+	if header == "" {
+		header = DefaultDebugHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := l
+			if value := r.Header.Get(header); value != "" && opts.accept(value, r) {
+				logger = l.withLevel(LogLevelDebug).WithFields(Fields{"debug_request": true})
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), logger)))
+		})
+	}
+}
+
+func (o *DebugHeaderOptions) accept(value string, r *http.Request) bool {
+	ok := false
+	for _, token := range o.Tokens {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(token)) == 1 {
+			ok = true
+		}
+	}
+	if !ok && len(o.Key) > 0 {
+		ok = verifyDebugToken(o.Key, value, time.Now())
+	}
+	return ok && (o.Allow == nil || o.Allow(r))
+}
+
+// SignDebugToken returns a debug header token valid until expiry, for
+// DebugHeaderOptions.Key. The token has the form "<unix expiry>.<hex
+// HMAC-SHA256 of the expiry>".
+func SignDebugToken(key []byte, expiry time.Time) string {
+	exp := strconv.FormatInt(expiry.Unix(), 10)
+	return exp + "." + hex.EncodeToString(debugTokenMAC(key, exp))
+}
+
+func verifyDebugToken(key []byte, token string, now time.Time) bool {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	mac, err := hex.DecodeString(sig)
+	return err == nil && hmac.Equal(mac, debugTokenMAC(key, exp))
+}
+
+func debugTokenMAC(key []byte, exp string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(exp))
+	return h.Sum(nil)
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// requestLevel returns the level of the logger DebugMiddleware gives a
+// request with the debug header set to value.
+func requestLevel(t *testing.T, mw func(http.Handler) http.Handler, value string) LogLevel {
+	t.Helper()
+	var level LogLevel
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level = FromContext(r.Context()).Level()
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		req.Header.Set(DefaultDebugHeader, value)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return level
+}
+
+func TestDebugMiddlewareTokens(t *testing.T) {
+	logger, _ := testLogger(LogLevelWarning)
+	mw := logger.DebugMiddleware(DebugHeaderOptions{Tokens: []string{"s3cret"}})
+
+	if got := requestLevel(t, mw, "s3cret"); got != LogLevelDebug {
+		t.Errorf("Expected an allowlisted token to elevate the request, got %v", got)
+	}
+	if got := requestLevel(t, mw, "guess"); got != LogLevelWarning {
+		t.Errorf("Expected an unknown token to be ignored, got %v", got)
+	}
+	if got := requestLevel(t, mw, ""); got != LogLevelWarning {
+		t.Errorf("Expected requests without the header to be unchanged, got %v", got)
+	}
+	if logger.Level() != LogLevelWarning {
+		t.Errorf("Elevating a request should not change the logger, got %v", logger.Level())
+	}
+}
+
+func TestDebugMiddlewareSignedTokens(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	key := []byte("key")
+	mw := logger.DebugMiddleware(DebugHeaderOptions{Key: key})
+
+	if got := requestLevel(t, mw, SignDebugToken(key, time.Now().Add(time.Minute))); got != LogLevelDebug {
+		t.Errorf("Expected a signed token to elevate the request, got %v", got)
+	}
+	if got := requestLevel(t, mw, SignDebugToken(key, time.Now().Add(-time.Minute))); got != LogLevelInfo {
+		t.Errorf("Expected an expired token to be rejected, got %v", got)
+	}
+	if got := requestLevel(t, mw, SignDebugToken([]byte("other"), time.Now().Add(time.Minute))); got != LogLevelInfo {
+		t.Errorf("Expected a token signed with another key to be rejected, got %v", got)
+	}
+}
+
+func TestDebugMiddlewareAllow(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	mw := logger.DebugMiddleware(DebugHeaderOptions{
+		Tokens: []string{"s3cret"},
+		Allow:  func(r *http.Request) bool { return false },
+	})
+	if got := requestLevel(t, mw, "s3cret"); got != LogLevelInfo {
+		t.Errorf("Expected Allow to veto the request, got %v", got)
+	}
+	if got := requestLevel(t, logger.DebugMiddleware(DebugHeaderOptions{}), "anything"); got != LogLevelInfo {
+		t.Errorf("Expected no elevation without tokens or key, got %v", got)
+	}
+}
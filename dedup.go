@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// DedupWriter is a log.Writer that collapses identical consecutive entries
+// seen within Window, like syslog's "last message repeated N times": the
+// first entry is written at once, and its repeats are held and written as
+// a single entry, the last of them, with a repeat_count field. Entries are
+// identical if they have the same level and message.
+//
+// Repeats are held until a different entry arrives, Window elapses, or the
+// writer is flushed, so Window bounds how late they are reported.
+type DedupWriter struct {
+	// Writer is the destination of collapsed entries.
+	Writer log.Writer
+
+	// Window is how long repeats of an entry are collapsed. Defaults to 1s.
+	Window time.Duration
+
+	mu      sync.Mutex
+	key     string       // level and message of the last entry written
+	pending *entryBuffer // last repeat held, or nil
+	level   log.Level
+	count   int // repeats held
+	timer   *time.Timer
+}
+
+// NewDedupWriter returns a DedupWriter writing to w.
+func NewDedupWriter(w log.Writer, window time.Duration) *DedupWriter {
+	return &DedupWriter{Writer: w, Window: window}
+}
+
+// WriteEntry implements log.Writer.
+func (w *DedupWriter) WriteEntry(e *log.Entry) (int, error) {
+	b := getEntryBuffer()
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+	n := len(*b)
+	key := dedupKey(*b)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer != nil && key == w.key {
+		if w.pending != nil {
+			putEntryBuffer(w.pending)
+		}
+		w.pending, w.level = b, e.Level
+		w.count++
+		return n, nil
+	}
+	err := w.flushLocked()
+	if _, werr := w.Writer.WriteEntry(e); err == nil {
+		err = werr
+	}
+	putEntryBuffer(b)
+	w.key = key
+	window := w.Window
+	if window <= 0 {
+		window = time.Second
+	}
+	w.timer = time.AfterFunc(window, func() { _ = w.flushPending() })
+	return n, err
+}
+
+func (w *DedupWriter) buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == nil {
+		return 0
+	}
+	return 1
+}
+
+// Flush writes the held entry, then flushes Writer if it buffers entries.
+func (w *DedupWriter) Flush() error {
+	if err := w.flushPending(); err != nil {
+		return err
+	}
+	return flushWriter(w.Writer)
+}
+
+// Close flushes the held entry and closes Writer if it is closable.
+func (w *DedupWriter) Close() error {
+	err := w.flushPending()
+	if cerr := closeWriter(w.Writer); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (w *DedupWriter) flushPending() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flushLocked()
+}
+
+// flushLocked writes the held repeats and ends the window of the last
+// entry, so its next occurrence is written at once.
+func (w *DedupWriter) flushLocked() error {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.key = ""
+	if w.pending == nil {
+		return nil
+	}
+	buf := withRepeatCount(*w.pending, w.count)
+	e := log.NewContext(buf)
+	e.Level = w.level
+	_, err := w.Writer.WriteEntry(e)
+	*w.pending = buf
+	putEntryBuffer(w.pending)
+	w.pending, w.count = nil, 0
+	return err
+}
+
+// dedupKey returns the level and message of an encoded entry.
+func dedupKey(b []byte) string {
+	var entry struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(b, &entry) != nil {
+		return string(b)
+	}
+	return entry.Level + "\x00" + entry.Message
+}
+
+// withRepeatCount inserts a repeat_count field before the closing brace of
+// a JSON entry.
+func withRepeatCount(b []byte, count int) []byte {
+	end := bytes.LastIndexByte(b, '}')
+	if end < 0 {
+		return b
+	}
+	tail := append([]byte(nil), b[end:]...)
+	b = append(b[:end], `,"repeat_count":`...)
+	b = strconv.AppendInt(b, int64(count), 10)
+	return append(b, tail...)
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func dedupEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestDedupWriterCollapsesRepeats(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	buf := new(bytes.Buffer)
+	logger.SetWriter(log.IOWriter{Writer: buf})
+	logger.SetDedup(time.Hour)
+
+	for i := 0; i < 5; i++ {
+		logger.Warning("disk almost full")
+	}
+	logger.Warning("disk full")
+	logger.Error("disk full")
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	entries := dedupEntries(t, buf)
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	if _, ok := entries[0]["repeat_count"]; ok || entries[0]["message"] != "disk almost full" {
+		t.Errorf("Expected the first occurrence written as is, got %v", entries[0])
+	}
+	if entries[1]["message"] != "disk almost full" || entries[1]["repeat_count"] != float64(4) {
+		t.Errorf("Expected collapsed repeats with repeat_count 4, got %v", entries[1])
+	}
+	if _, ok := entries[2]["repeat_count"]; ok {
+		t.Errorf("Unique entries should not carry repeat_count, got %v", entries[2])
+	}
+	if entries[3]["level"] != "error" {
+		t.Errorf("Entries with a different level should not be collapsed, got %v", entries[3])
+	}
+}
+
+func TestDedupWriterWritesFirstAtOnce(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	buf := new(bytes.Buffer)
+	logger.SetWriter(log.IOWriter{Writer: buf})
+	logger.SetDedup(time.Hour)
+
+	logger.Info("retrying %s, attempt %d", "db", 1)
+	if !bytes.Contains(buf.Bytes(), []byte("attempt 1")) {
+		t.Fatalf("Expected the first entry written at once, got %q", buf.Bytes())
+	}
+	logger.Info("retrying %s, attempt %d", "db", 2)
+	if !bytes.Contains(buf.Bytes(), []byte("attempt 2")) {
+		t.Errorf("Expected messages differing after a comma not collapsed, got %q", buf.Bytes())
+	}
+}
+
+func TestDedupWriterComparesMessages(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewDedupWriter(log.IOWriter{Writer: buf}, time.Hour)
+	for _, entry := range []string{`{"message":"disk a, full"}`, `{"message":"disk b, full"}`} {
+		if _, err := w.WriteEntry(log.NewContext([]byte(entry + "\n"))); err != nil {
+			t.Fatalf("WriteEntry failed: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if entries := dedupEntries(t, buf); len(entries) != 2 || entries[1]["message"] != "disk b, full" {
+		t.Errorf("Expected entries with different messages kept apart, got %v", entries)
+	}
+}
+
+func TestDedupWriterWindowExpiry(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	buf := new(syncBuffer)
+	logger.SetWriter(log.IOWriter{Writer: buf})
+	logger.SetDedup(10 * time.Millisecond)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	logger.Info("tick")
+	deadline := time.Now().Add(time.Second)
+	for !bytes.Contains(buf.Bytes(), []byte("repeat_count")) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"repeat_count":2`)) {
+		t.Errorf("Expected the held repeats to be written after the window, got %q", buf.Bytes())
+	}
+}
@@ -0,0 +1,53 @@
+package logging
+
+import "sync/atomic"
+
+var defaultLogger atomic.Pointer[Logger]
+
+// Default returns the package-level logger used by Get. Unless replaced
+// with SetDefault, it is NewLogger().
+func Default() *Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	defaultLogger.CompareAndSwap(nil, NewLogger())
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the package-level logger used by Get. Loggers
+// returned by Get before the call keep using the previous one.
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+// Get returns the logger named name, such as "server.http", derived from
+// Default. Repeated calls with the same name return the same Logger, so it
+// can be fetched wherever it is needed:
+//
+//	var log = logging.Get("server.http")
+//
+// Its level is inherited from "server", and from the default logger above
+// it, unless set explicitly, so verbosity can be tuned per subsystem from
+// one place with SetLevelSpec or SetLogLevel.
+func Get(name string) *Logger {
+	root := Default()
+	n := &root.state.tree.names
+	n.mu.Lock()
+	l, ok := n.loggers[name]
+	n.mu.Unlock()
+	if ok {
+		return l
+	}
+
+	l = root.Named(name)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if cached, ok := n.loggers[name]; ok {
+		return cached
+	}
+	if n.loggers == nil {
+		n.loggers = make(map[string]*Logger)
+	}
+	n.loggers[name] = l
+	return l
+}
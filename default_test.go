@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func useDefault(t *testing.T, l *Logger) {
+	t.Helper()
+	prev := defaultLogger.Load()
+	SetDefault(l)
+	t.Cleanup(func() { defaultLogger.Store(prev) })
+}
+
+func TestGetCachesLoggers(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	useDefault(t, logger)
+
+	a := Get("server.http")
+	if b := Get("server.http"); a != b {
+		t.Error("Get should return the same logger for the same name")
+	}
+	a.Info("request")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry["logger"] != "server.http" {
+		t.Errorf("Expected logger 'server.http', got %v", entry["logger"])
+	}
+}
+
+func TestGetInheritsLevel(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	useDefault(t, logger)
+	server := Get("server")
+	http := Get("server.http")
+	db := Get("db")
+
+	server.SetLogLevel(LogLevelDebug)
+	if http.Level() != LogLevelDebug {
+		t.Errorf("server.http should inherit the level of server, got %v", http.Level())
+	}
+	if db.Level() != LogLevelInfo {
+		t.Errorf("db should keep the root level, got %v", db.Level())
+	}
+
+	http.SetLogLevel(LogLevelError)
+	server.SetLogLevel(LogLevelWarning)
+	if http.Level() != LogLevelError {
+		t.Errorf("An explicit level should not be overridden by the parent, got %v", http.Level())
+	}
+
+	logger.SetLogLevel(LogLevelError)
+	if db.Level() != LogLevelError || server.Level() != LogLevelWarning {
+		t.Errorf("Root changes should reach only loggers without an explicit level, got db=%v server=%v", db.Level(), server.Level())
+	}
+}
+
+func TestGetConcurrent(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	useDefault(t, logger)
+
+	var wg sync.WaitGroup
+	got := make([]*Logger, 8)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = Get("worker")
+			got[i].SetLogLevel(LogLevelWarning)
+		}(i)
+	}
+	wg.Wait()
+	for _, l := range got[1:] {
+		if l != got[0] {
+			t.Fatal("Concurrent Get calls should return the same logger")
+		}
+	}
+}
+
+func TestDefaultLazy(t *testing.T) {
+	useDefault(t, nil)
+	if Default() == nil || Default() != Default() {
+		t.Error("Default should create one logger lazily")
+	}
+}
@@ -0,0 +1,180 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// ResolvedConfig is the effective configuration of a Logger, as reported
+// by Logger.Config for debugging "why isn't this logging" situations.
+type ResolvedConfig struct {
+	Name        string            `json:"name,omitempty"`
+	Level       string            `json:"level"`
+	NamedLevels map[string]string `json:"named_levels,omitempty"`
+	Overrides   map[string]string `json:"level_overrides,omitempty"`
+	Fields      Fields            `json:"fields,omitempty"`
+	Writer      string            `json:"writer"`
+	Sampler     string            `json:"sampler,omitempty"`
+	RateLimit   string            `json:"rate_limit,omitempty"`
+	Caller      bool              `json:"caller"`
+	TimeFormat  string            `json:"time_format"`
+	Sequence    bool              `json:"sequence"`
+	FormatCheck bool              `json:"format_check"`
+	Closed      bool              `json:"closed"`
+}
+
+// Config returns the effective configuration of l: its level and those of
+// the loggers named from the same root, its fields, the chain of writers
+// entries pass through, and sampling and rate limiting.
+func (l *Logger) Config() ResolvedConfig {
+	c := ResolvedConfig{
+		Name:        l.name,
+		Level:       l.Level().String(),
+		Fields:      l.fields,
+		Writer:      describeWriter(l.writer()),
+		Sampler:     describeSampler(l.sampler),
+		Caller:      l.state.tree.caller.Load(),
+		TimeFormat:  l.logger.TimeFormat,
+		Sequence:    l.sequence,
+		FormatCheck: l.checkFormat,
+		Closed:      l.state.tree.closed.Load(),
+	}
+	if l.limiter != nil {
+		c.RateLimit = fmt.Sprintf("%d per %v per message", l.limiter.limit, l.limiter.interval)
+	}
+	n := &l.state.tree.names
+	n.mu.Lock()
+	if len(n.states) > 0 {
+		c.NamedLevels = make(map[string]string, len(n.states))
+		for name, s := range n.states {
+			c.NamedLevels[name] = LogLevel(s.level.Load()).String()
+		}
+	}
+	if len(n.explicit) > 0 {
+		c.Overrides = make(map[string]string, len(n.explicit))
+		for name, level := range n.explicit {
+			c.Overrides[name] = level.String()
+		}
+	}
+	n.mu.Unlock()
+	return c
+}
+
+// DumpConfig writes the effective configuration of l to w as indented JSON.
+func (l *Logger) DumpConfig(w io.Writer) error {
+	data, err := json.MarshalIndent(l.Config(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// describeWriter returns a one-line description of the writer chain
+// starting at w.
+func describeWriter(w any) string {
+	switch w := w.(type) {
+	case nil:
+		return "none"
+	case *os.File:
+		return describeFile(w)
+	case *AsyncWriter:
+		policy := "block"
+		switch w.Policy {
+		case OverflowDropNewest:
+			policy = "drop_newest"
+		case OverflowDropOldest:
+			policy = "drop_oldest"
+		}
+		queue := w.QueueSize
+		if queue <= 0 {
+			queue = 1024
+		}
+		return fmt.Sprintf("async(queue=%d, policy=%s) -> %s", queue, policy, describeWriter(w.Writer))
+	case *BatchWriter:
+		return fmt.Sprintf("batch(max=%d, interval=%v) -> %s", w.maxEntries(), w.interval(), describeWriter(w.Sink))
+	case *DedupWriter:
+		window := w.Window
+		if window <= 0 {
+			window = time.Second
+		}
+		return fmt.Sprintf("dedup(window=%v) -> %s", window, describeWriter(w.Writer))
+	case *CoalescingWriter:
+		return fmt.Sprintf("coalescing(size=%d, latency=%v) -> %s", w.size(), w.latency(), describeWriter(w.Writer))
+	case *ShardedWriter:
+		return fmt.Sprintf("sharded(shards=%d) -> %s", w.Shards, describeWriter(w.Writer))
+	case *FallbackWriter:
+		return fmt.Sprintf("fallback(failures=%d) -> %s", w.Failures(), describeWriter(w.Writer))
+	case *PreallocFileWriter:
+		return fmt.Sprintf("prealloc_file(%s, mmap=%v)", w.Filename, w.Mmap)
+	case *swapWriter:
+		return "reloadable -> " + describeWriter(w.unwrap())
+	case *splitWriter:
+		return fmt.Sprintf("split(error: %s, other: %s)", describeWriter(w.err), describeWriter(w.out))
+	case IOBatchSink:
+		return describeWriter(w.Writer)
+	case log.IOWriter:
+		return "json -> " + describeWriter(w.Writer)
+	case *log.IOWriter:
+		return "json -> " + describeWriter(w.Writer)
+	case log.IOWriteCloser:
+		return "json -> " + describeWriter(w.WriteCloser)
+	case *log.ConsoleWriter:
+		return fmt.Sprintf("console(color=%v) -> %s", w.ColorOutput, describeWriter(w.Writer))
+	case *log.FileWriter:
+		return fmt.Sprintf("file(%s, max_size=%d, max_backups=%d)", w.Filename, w.MaxSize, w.MaxBackups)
+	case *log.MultiEntryWriter:
+		parts := make([]string, len(*w))
+		for i, inner := range *w {
+			parts[i] = describeWriter(inner)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return fmt.Sprintf("%T", w)
+}
+
+func describeFile(f *os.File) string {
+	switch f {
+	case os.Stdout:
+		return "stdout"
+	case os.Stderr:
+		return "stderr"
+	}
+	return "file(" + f.Name() + ")"
+}
+
+// describeSampler returns a short description of s.
+func describeSampler(s Sampler) string {
+	switch s := s.(type) {
+	case nil:
+		return ""
+	case *everyN:
+		return fmt.Sprintf("1 in %d", s.n)
+	case *burst:
+		return fmt.Sprintf("first %d per %v, then 1 in %d", s.first, time.Duration(s.tick), s.thereafter)
+	case *swapSampler:
+		if b := s.p.Load(); b != nil {
+			return describeSampler(b.Sampler)
+		}
+		return ""
+	case LevelSampler:
+		levels := make([]LogLevel, 0, len(s))
+		for level := range s {
+			levels = append(levels, level)
+		}
+		sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+		parts := make([]string, len(levels))
+		for i, level := range levels {
+			parts[i] = level.String() + ": " + describeSampler(s[level])
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprintf("%T", s)
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestLoggerConfig(t *testing.T) {
+	logger := NewLogger(WithLevel(LogLevelWarning), WithCaller(true), WithColor(true))
+	logger.SetSampler(LevelSampler{LogLevelDebug: EveryN(10)})
+	logger.SetRateLimiter(NewRateLimiter(5, time.Second))
+	logger.SetDedup(time.Second)
+	logger.SetAsync(64)
+	if err := logger.SetLevelSpec("warning,db=debug"); err != nil {
+		t.Fatalf("SetLevelSpec failed: %v", err)
+	}
+	db := logger.Named("db").WithFields(Fields{"shard": 3})
+
+	c := db.Config()
+	if c.Name != "db" || c.Level != "debug" {
+		t.Errorf("Unexpected name or level: %q %q", c.Name, c.Level)
+	}
+	if c.NamedLevels["db"] != "debug" || c.Overrides["db"] != "debug" {
+		t.Errorf("Unexpected named levels: %v %v", c.NamedLevels, c.Overrides)
+	}
+	if c.Fields["shard"] != 3 || c.Fields["logger"] != "db" {
+		t.Errorf("Unexpected fields: %v", c.Fields)
+	}
+	want := "async(queue=64, policy=block) -> dedup(window=1s) -> console(color=true) -> stdout"
+	if c.Writer != want {
+		t.Errorf("Unexpected writer chain:\n got %s\nwant %s", c.Writer, want)
+	}
+	if c.Sampler != "debug: 1 in 10" || c.RateLimit != "5 per 1s per message" {
+		t.Errorf("Unexpected sampling: %q %q", c.Sampler, c.RateLimit)
+	}
+	if !c.Caller {
+		t.Error("Expected caller capture to be reported")
+	}
+	logger.Close()
+}
+
+func TestDumpConfig(t *testing.T) {
+	logger := NewLogger(WithWriter(&log.MultiEntryWriter{
+		&log.IOWriter{Writer: os.Stderr},
+		&log.FileWriter{Filename: "app.log", MaxSize: 1 << 20},
+	}))
+	var buf bytes.Buffer
+	if err := logger.DumpConfig(&buf); err != nil {
+		t.Fatalf("DumpConfig failed: %v", err)
+	}
+	var c ResolvedConfig
+	if err := json.Unmarshal(buf.Bytes(), &c); err != nil {
+		t.Fatalf("Expected JSON: %v (%q)", err, buf.Bytes())
+	}
+	if c.Level != "info" || !strings.Contains(c.Writer, "json -> stderr") || !strings.Contains(c.Writer, "file(app.log") {
+		t.Errorf("Unexpected dump: %+v", c)
+	}
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrUnknownKey is returned by a key lookup passed to NewDecryptingReader
+// for a key ID it does not know.
+var ErrUnknownKey = errors.New("logging: unknown encryption key")
+
+// maxEncryptedRecord bounds the records DecryptingReader accepts, so a
+// corrupt length cannot make it allocate without limit.
+const maxEncryptedRecord = 64 << 20
+
+// EncryptingWriter is an io.Writer encrypting each write, one entry when
+// used under log.IOWriter or log.ConsoleWriter, with AES-GCM before passing
+// it to Writer, typically a log.FileWriter, so logs at rest are unreadable
+// without the key. Each write becomes one self-contained record:
+//
+//	length  uint32, big-endian, of the rest of the record
+//	key ID  one length byte followed by KeyID
+//	nonce   12 random bytes
+//	sealed  the entry encrypted and authenticated, with the key ID
+//	        as additional data
+//
+// Records survive rotation and can be read back with DecryptingReader.
+// KeyID names the key for decryption: for envelope encryption, encrypt a
+// random data key with a key management service and store the wrapped key,
+// or its ID, as KeyID. Random nonces limit a key to about 2³² records;
+// rotate data keys well before.
+type EncryptingWriter struct {
+	// Writer receives the records.
+	Writer io.Writer
+
+	// KeyID is stored with every record; at most 255 bytes.
+	KeyID string
+
+	mu   sync.Mutex
+	aead cipher.AEAD
+	buf  []byte
+}
+
+// NewEncryptingWriter returns an EncryptingWriter encrypting to w with key,
+// a 16, 24 or 32 byte AES key.
+func NewEncryptingWriter(w io.Writer, key []byte, keyID string) (*EncryptingWriter, error) {
+	if len(keyID) > 255 {
+		return nil, fmt.Errorf("logging: encryption key ID longer than 255 bytes")
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptingWriter{Writer: w, KeyID: keyID, aead: aead}, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("logging: encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Write encrypts p as one record and writes it to Writer.
+func (w *EncryptingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	nonceSize := w.aead.NonceSize()
+	head := 4 + 1 + len(w.KeyID)
+	b := append(w.buf[:0], make([]byte, head+nonceSize)...)
+	binary.BigEndian.PutUint32(b, uint32(head-4+nonceSize+len(p)+w.aead.Overhead()))
+	b[4] = byte(len(w.KeyID))
+	copy(b[5:], w.KeyID)
+	nonce := b[head:]
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, err
+	}
+	b = w.aead.Seal(b, nonce, p, []byte(w.KeyID))
+	w.buf = b
+	if _, err := w.Writer.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes Writer if it is closable.
+func (w *EncryptingWriter) Close() error {
+	return closeWriter(w.Writer)
+}
+
+// DecryptingReader reads the records written by an EncryptingWriter and
+// returns the decrypted entries, for tooling authorized to read the logs.
+type DecryptingReader struct {
+	r     *bufio.Reader
+	keys  func(keyID string) ([]byte, error)
+	aeads map[string]cipher.AEAD
+	buf   []byte
+	out   []byte
+}
+
+// NewDecryptingReader returns a reader decrypting the records read from r.
+// keys returns the AES key for a key ID, unwrapping it with a key
+// management service if needed; each key ID is looked up once.
+func NewDecryptingReader(r io.Reader, keys func(keyID string) ([]byte, error)) *DecryptingReader {
+	return &DecryptingReader{r: bufio.NewReader(r), keys: keys, aeads: make(map[string]cipher.AEAD)}
+}
+
+// Read implements io.Reader. A record that fails to decrypt is an error,
+// since it was altered or encrypted with another key.
+func (d *DecryptingReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// next decrypts the next record into out.
+func (d *DecryptingReader) next() error {
+	var size [4]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("logging: truncated encrypted record")
+		}
+		return err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n == 0 || n > maxEncryptedRecord {
+		return fmt.Errorf("logging: corrupt encrypted record of %d bytes", n)
+	}
+	if cap(d.buf) < int(n) {
+		d.buf = make([]byte, n)
+	}
+	rec := d.buf[:n]
+	if _, err := io.ReadFull(d.r, rec); err != nil {
+		return fmt.Errorf("logging: truncated encrypted record")
+	}
+	idLen := int(rec[0])
+	if 1+idLen > len(rec) {
+		return fmt.Errorf("logging: corrupt encrypted record")
+	}
+	keyID := string(rec[1 : 1+idLen])
+	aead, err := d.aead(keyID)
+	if err != nil {
+		return err
+	}
+	rest := rec[1+idLen:]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return fmt.Errorf("logging: corrupt encrypted record")
+	}
+	nonce, sealed := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	d.out, err = aead.Open(sealed[:0], nonce, sealed, []byte(keyID))
+	if err != nil {
+		return fmt.Errorf("logging: decrypt record with key %q: %w", keyID, err)
+	}
+	return nil
+}
+
+func (d *DecryptingReader) aead(keyID string) (cipher.AEAD, error) {
+	if aead, ok := d.aeads[keyID]; ok {
+		return aead, nil
+	}
+	key, err := d.keys(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("logging: key %q: %w", keyID, err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	d.aeads[keyID] = aead
+	return aead, nil
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedFileSink(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	t.Setenv("LOG_KEY", base64.StdEncoding.EncodeToString(key))
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := NewLoggerFromConfig(&Config{
+		Sinks: []SinkConfig{{Type: "file", Path: path, Format: "json",
+			Encryption: EncryptionConfig{KeyEnv: "LOG_KEY", KeyID: "kms/v1"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewLoggerFromConfig failed: %v", err)
+	}
+	logger.WithFields(Fields{"card": "4111"}).Info("charged")
+	logger.Warning("refunded")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("charged")) || bytes.Contains(data, []byte("4111")) {
+		t.Fatalf("Expected the file encrypted, got %q", data)
+	}
+	var asked []string
+	plain, err := io.ReadAll(NewDecryptingReader(bytes.NewReader(data), func(id string) ([]byte, error) {
+		asked = append(asked, id)
+		return key, nil
+	}))
+	if err != nil {
+		t.Fatalf("Decrypting failed: %v", err)
+	}
+	entries := decodeEntries(t, string(plain))
+	if len(entries) != 2 || entries[0]["card"] != "4111" || entries[1]["message"] != "refunded" {
+		t.Errorf("Expected both entries decrypted, got %q", plain)
+	}
+	if strings.Join(asked, ",") != "kms/v1" {
+		t.Errorf("Expected the key looked up once by ID, got %v", asked)
+	}
+
+	data[len(data)-1] ^= 1
+	if _, err := io.ReadAll(NewDecryptingReader(bytes.NewReader(data), func(string) ([]byte, error) { return key, nil })); err == nil {
+		t.Error("Expected an altered record to fail decryption")
+	}
+	_, err = io.ReadAll(NewDecryptingReader(bytes.NewReader(data), func(string) ([]byte, error) { return nil, ErrUnknownKey }))
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected the key lookup error, got %v", err)
+	}
+}
+
+func TestEncryptionConfigErrors(t *testing.T) {
+	t.Setenv("SHORT_KEY", base64.StdEncoding.EncodeToString([]byte("short")))
+	for _, env := range []string{"UNSET_KEY", "SHORT_KEY"} {
+		_, err := NewLoggerFromConfig(&Config{Sinks: []SinkConfig{{Type: "file", Path: filepath.Join(t.TempDir(), "x.log"),
+			Encryption: EncryptionConfig{KeyEnv: env}}}})
+		if err == nil {
+			t.Errorf("%s: expected an error", env)
+		}
+	}
+}
+
+func TestValidateEncryption(t *testing.T) {
+	cfg := &Config{Sinks: []SinkConfig{
+		{Type: "stdout", Encryption: EncryptionConfig{KeyEnv: "LOG_KEY"}},
+		{Type: "file", Path: "app.log", Encryption: EncryptionConfig{KeyID: "v1"}},
+	}}
+	var cerr *ConfigError
+	if err := cfg.Validate(); !errors.As(err, &cerr) || len(cerr.Errors) != 2 ||
+		cerr.Errors[0].Path != "sinks[0].encryption" || cerr.Errors[1].Path != "sinks[1].encryption.key_id" {
+		t.Errorf("Expected encryption problems reported, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package logging
+
+import "time"
+
+// Entry is a log entry as a structured value, for in-process consumers
+// that should not have to parse the encoded output: subscribers, Recorder
+// and CaptureSink.
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string // formatted
+	Logger  string // name of the named logger, if any
+	Fields  Fields
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvLevel  = "LOG_LEVEL"
+	EnvFormat = "LOG_FORMAT"
+	EnvFile   = "LOG_FILE"
+	EnvColor  = "LOG_COLOR"
+)
+
+// ConfigFromEnv returns a Config built from the environment, so the same
+// binary can log differently in development, CI and production:
+//
+//	LOG_LEVEL   debug, info, warning, error or a spec like "info,db=debug"
+//	LOG_FORMAT  console or json
+//	LOG_FILE    path of a file to log to instead of stdout
+//	LOG_COLOR   true or false, for console output
+//	LOG_PROFILE quiet, normal, verbose or debug; see Profile
+//
+// Unset variables keep their defaults.
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		Level:   os.Getenv(EnvLevel),
+		Format:  os.Getenv(EnvFormat),
+		Profile: os.Getenv(EnvProfile),
+	}
+	if path := os.Getenv(EnvFile); path != "" {
+		cfg.Sinks = []SinkConfig{{Type: "file", Path: path}}
+	}
+	if s := os.Getenv(EnvColor); s != "" {
+		color, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("logging: invalid %s %q", EnvColor, s)
+		}
+		cfg.Color = &color
+	}
+	return cfg, nil
+}
+
+// NewLoggerFromEnv creates a Logger configured by the environment
+// variables described by ConfigFromEnv.
+func NewLoggerFromEnv() (*Logger, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewLoggerFromConfig(cfg)
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewLoggerFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(EnvLevel, "error")
+	t.Setenv(EnvFormat, "json")
+	t.Setenv(EnvFile, path)
+	t.Setenv(EnvColor, "false")
+
+	logger, err := NewLoggerFromEnv()
+	if err != nil {
+		t.Fatalf("NewLoggerFromEnv failed: %v", err)
+	}
+	if logger.Level() != LogLevelError {
+		t.Errorf("Expected level error, got %v", logger.Level())
+	}
+	logger.Error("to file")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if len(data) == 0 || data[0] != '{' {
+		t.Errorf("Expected a JSON entry in LOG_FILE, got %q", data)
+	}
+}
+
+func TestConfigFromEnvDefaults(t *testing.T) {
+	for _, key := range []string{EnvLevel, EnvFormat, EnvFile, EnvColor} {
+		t.Setenv(key, "")
+	}
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+	if cfg.Level != "" || cfg.Sinks != nil || cfg.Color != nil {
+		t.Errorf("Expected an empty config, got %+v", cfg)
+	}
+}
+
+func TestConfigFromEnvInvalid(t *testing.T) {
+	t.Setenv(EnvColor, "sometimes")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("Expected an error for an invalid LOG_COLOR")
+	}
+	t.Setenv(EnvColor, "")
+	t.Setenv(EnvLevel, "loud")
+	if _, err := NewLoggerFromEnv(); err == nil {
+		t.Error("Expected an error for an invalid LOG_LEVEL")
+	}
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// BindCmd sends the output of cmd to l line by line, standard output at
+// Info level and standard error at Warning, with a process field holding
+// name (the base name of cmd.Path if empty) and a stream field. It must be
+// called before cmd is started. Call the returned function once cmd.Wait
+// has returned to log a final line without a trailing newline. Options
+// such as WithLineRules apply to both streams.
+func BindCmd(l *Logger, cmd *exec.Cmd, name string, opts ...LineOption) (flush func()) {
+	if name == "" {
+		name = filepath.Base(cmd.Path)
+	}
+	stdout := NewLineWriter(l.WithFields(Fields{"process": name, "stream": "stdout"}), LogLevelInfo, opts...)
+	stderr := NewLineWriter(l.WithFields(Fields{"process": name, "stream": "stderr"}), LogLevelWarning, opts...)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	return func() {
+		_ = stdout.Flush()
+		_ = stderr.Flush()
+	}
+}
+
+// RunCmd runs cmd with its output bound to l as by BindCmd and logs how it
+// exited: at Info level on success, at Error level with the exit code or
+// error otherwise. It returns the error of cmd.Run.
+func RunCmd(l *Logger, cmd *exec.Cmd, name string, opts ...LineOption) error {
+	if name == "" {
+		name = filepath.Base(cmd.Path)
+	}
+	flush := BindCmd(l, cmd, name, opts...)
+	start := time.Now()
+	err := cmd.Run()
+	flush()
+	fields := Fields{"process": name, "duration": time.Since(start)}
+	if err == nil {
+		l.WithFields(fields).Info("process exited")
+		return nil
+	}
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		fields["exit_code"] = exit.ExitCode()
+	}
+	fields["error"] = err
+	l.WithFields(fields).Error("process failed")
+	return err
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestRunCmd(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	// stdout and stderr are copied by separate goroutines.
+	buf := new(syncBuffer)
+	logger := NewLogger(WithOutput(buf), WithFormat(FormatJSON))
+	err = RunCmd(logger, exec.Command(sh, "-c", "echo out; echo err >&2; printf tail; exit 3"), "worker")
+	if err == nil {
+		t.Fatal("Expected the exit error")
+	}
+
+	byMessage := map[string]map[string]any{}
+	for _, e := range decodeEntries(t, string(buf.Bytes())) {
+		byMessage[e["message"].(string)] = e
+	}
+	if e := byMessage["out"]; e == nil || e["level"] != "info" || e["stream"] != "stdout" || e["process"] != "worker" {
+		t.Errorf("Unexpected stdout entry: %v", e)
+	}
+	if e := byMessage["err"]; e == nil || e["level"] != "warn" || e["stream"] != "stderr" {
+		t.Errorf("Unexpected stderr entry: %v", e)
+	}
+	if byMessage["tail"] == nil {
+		t.Error("Expected the final partial line to be flushed")
+	}
+	if e := byMessage["process failed"]; e == nil || e["exit_code"] != float64(3) {
+		t.Errorf("Unexpected exit entry: %v", e)
+	}
+}
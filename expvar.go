@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"expvar"
+)
+
+// levelNames lists the levels in the order of levelIndex.
+var levelNames = [...]string{"debug", "info", "warning", "error"}
+
+// levelIndex maps a level to its index in loggerTree.entries.
+func levelIndex(level LogLevel) int {
+	switch {
+	case level <= LogLevelDebug:
+		return 0
+	case level >= LogLevelError:
+		return 3
+	}
+	return int(level) + 1
+}
+
+// Vars returns an expvar.Var reporting the health of l, for publishing
+// under a name of your choice or adding to an existing expvar.Map:
+//
+//	{"level": "info", "entries": {"debug": 0, "info": 120, ...},
+//	 "sampled": {"debug": 0, ...}, "rate_limited": {"debug": 0, ...},
+//	 "dropped": 0, "pending": 3, "write_failures": 0, "closed": false,
+//	 "sinks": [{"sink": "RetrySink", "delivered": 118, "retries": 2, ...}]}
+//
+// Entries are counted across l and every logger derived or named from the
+// same root once they pass sampling and rate limiting; sampled and
+// rate_limited count those that did not, as Suppressed does. Dropped
+// counts the entries discarded by full AsyncWriter queues, SpoolWriter
+// directories and open circuit breakers, and write_failures those the
+// sinks failed to write, when SetErrorHandler is installed. Sinks holds the DeliveryStats
+// of each sink wrapper, as returned by Stats.
+func (l *Logger) Vars() expvar.Var {
+	return expvar.Func(func() any {
+		entries := make(map[string]uint64, len(levelNames))
+		sampled := make(map[string]uint64, len(levelNames))
+		limited := make(map[string]uint64, len(levelNames))
+		for _, c := range l.EntryCounts() {
+			entries[levelNames[levelIndex(c.Level)]] += c.Count
+		}
+		for i, name := range levelNames {
+			sampled[name] = l.state.tree.sampled[i].Load()
+			limited[name] = l.state.tree.limited[i].Load()
+		}
+		var sinks []map[string]any
+		for _, s := range l.Stats() {
+			sinks = append(sinks, map[string]any{
+				"sink":          s.Sink,
+				"delivered":     s.Delivered,
+				"retries":       s.Retries,
+				"failed":        s.Failed,
+				"dropped":       s.Dropped,
+				"dead_lettered": s.DeadLettered,
+				"bytes":         s.Bytes,
+			})
+		}
+		return map[string]any{
+			"level":          l.Level().String(),
+			"entries":        entries,
+			"sampled":        sampled,
+			"rate_limited":   limited,
+			"dropped":        droppedEntries(l.logger.Writer),
+			"pending":        pendingEntries(l.logger.Writer),
+			"write_failures": l.WriteFailures(),
+			"closed":         l.state.tree.closed.Load(),
+			"sinks":          sinks,
+		}
+	})
+}
+
+// PublishExpvar publishes Vars under name, so it appears at /debug/vars
+// next to the runtime's memstats. Like expvar.Publish, it panics if name is
+// already in use.
+func (l *Logger) PublishExpvar(name string) {
+	expvar.Publish(name, l.Vars())
+}
+
+// droppedEntries counts the entries discarded by the writers reached from
+// w, in every branch of fan-out writers.
+func droppedEntries(w any) uint64 {
+	var n uint64
+	walkWriters(w, func(w any) {
+		if d, ok := w.(interface{ Dropped() uint64 }); ok {
+			n += d.Dropped()
+		}
+	})
+	return n
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestVarsReportsCounters(t *testing.T) {
+	logger, _ := testLogger(LogLevelDebug)
+	db := logger.Named("db")
+	logger.Info("one")
+	logger.Info("two")
+	db.Error("three")
+	db.Debug("four")
+	logger.withLevel(LogLevelDebug).Debug("five")
+	logger.SetLogLevel(LogLevelWarning)
+	logger.Info("disabled")
+
+	var got struct {
+		Level   string            `json:"level"`
+		Entries map[string]uint64 `json:"entries"`
+		Dropped uint64            `json:"dropped"`
+		Closed  bool              `json:"closed"`
+	}
+	if err := json.Unmarshal([]byte(logger.Vars().String()), &got); err != nil {
+		t.Fatalf("Vars should render JSON: %v", err)
+	}
+	if got.Level != "warning" || got.Closed {
+		t.Errorf("Unexpected state: %+v", got)
+	}
+	want := map[string]uint64{"debug": 2, "info": 2, "warning": 0, "error": 1}
+	for level, n := range want {
+		if got.Entries[level] != n {
+			t.Errorf("Expected %d %s entries, got %d", n, level, got.Entries[level])
+		}
+	}
+}
+
+func TestVarsReportsDropped(t *testing.T) {
+	sink := blockingWriter{release: make(chan struct{})}
+	defer close(sink.release)
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(&AsyncWriter{Writer: sink, QueueSize: 1, Policy: OverflowDropNewest})
+	for i := 0; i < 5; i++ {
+		logger.Info("entry %d", i)
+	}
+	if n := droppedEntries(logger.logger.Writer); n == 0 {
+		t.Error("Expected dropped entries to be reported")
+	}
+	if n := droppedEntries(&log.MultiEntryWriter{&log.IOWriter{Writer: io.Discard}, logger.logger.Writer}); n == 0 {
+		t.Error("Expected dropped entries behind a fan-out to be reported")
+	}
+}
+
+// published numbers the names used by TestPublishExpvar, so the test can
+// run repeatedly in one process.
+var published int
+
+func TestPublishExpvar(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	published++
+	name := fmt.Sprintf("logging_test_%d", published)
+	logger.PublishExpvar(name)
+	if expvar.Get(name) == nil {
+		t.Error("Expected the logger to be published")
+	}
+}
+
+func TestVarsReportsSuppressed(t *testing.T) {
+	logger, _ := testLogger(LogLevelDebug)
+	logger.SetSampler(SamplerFunc(func(level LogLevel) bool { return level != LogLevelDebug }))
+	logger.Debug("sampled out")
+	logger.Debug("sampled out")
+
+	var got struct {
+		Sampled     map[string]uint64 `json:"sampled"`
+		RateLimited map[string]uint64 `json:"rate_limited"`
+	}
+	if err := json.Unmarshal([]byte(logger.Vars().String()), &got); err != nil {
+		t.Fatalf("Vars should render JSON: %v", err)
+	}
+	if got.Sampled["debug"] != 2 || got.RateLimited["debug"] != 0 {
+		t.Errorf("Expected 2 debug entries sampled out, got %+v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/phuslu/log"
+)
+
+// FallbackWriter is a log.Writer that reports write failures of Writer
+// instead of swallowing them: the failed entry is written to Fallback, the
+// error is passed to OnError and the failure is counted.
+type FallbackWriter struct {
+	// Writer is the primary destination of entries.
+	Writer log.Writer
+
+	// Fallback receives entries Writer failed to write. Defaults to os.Stderr.
+	Fallback io.Writer
+
+	// OnError, if set, is called with every write error. It runs on the
+	// logging goroutine and must not log through the same writer.
+	OnError func(err error)
+
+	failures atomic.Uint64
+}
+
+// NewFallbackWriter returns a FallbackWriter writing to w and reporting
+// failures to onError.
+func NewFallbackWriter(w log.Writer, onError func(err error)) *FallbackWriter {
+	return &FallbackWriter{Writer: w, OnError: onError}
+}
+
+// WriteEntry implements log.Writer. The error of Writer is returned after
+// it has been reported.
+func (w *FallbackWriter) WriteEntry(e *log.Entry) (int, error) {
+	n, err := w.Writer.WriteEntry(e)
+	if err == nil {
+		return n, nil
+	}
+	w.failures.Add(1)
+	fallback := w.Fallback
+	if fallback == nil {
+		fallback = os.Stderr
+	}
+	_, _ = log.IOWriter{Writer: fallback}.WriteEntry(e)
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+	return n, err
+}
+
+// Failures returns the number of entries Writer failed to write.
+func (w *FallbackWriter) Failures() uint64 {
+	return w.failures.Load()
+}
+
+// Flush flushes Writer if it buffers entries.
+func (w *FallbackWriter) Flush() error {
+	return flushWriter(w.Writer)
+}
+
+// Close closes Writer if it is closable.
+func (w *FallbackWriter) Close() error {
+	return closeWriter(w.Writer)
+}
+
+// findFallbackWriter returns the first FallbackWriter in the writer chain
+// starting at w, or nil.
+func findFallbackWriter(w any) *FallbackWriter {
+	for w != nil {
+		if fw, ok := w.(*FallbackWriter); ok {
+			return fw
+		}
+		inner, ok := w.(wrapper)
+		if !ok {
+			return nil
+		}
+		w = inner.unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+// failingWriter fails every write with err.
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) WriteEntry(*log.Entry) (int, error) {
+	return 0, w.err
+}
+
+func TestFallbackWriterReportsFailures(t *testing.T) {
+	sinkErr := errors.New("disk full")
+	var fallback bytes.Buffer
+	var reported []error
+	w := &FallbackWriter{
+		Writer:   failingWriter{err: sinkErr},
+		Fallback: &fallback,
+		OnError:  func(err error) { reported = append(reported, err) },
+	}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(w)
+
+	logger.Info("first")
+	logger.Error("second")
+
+	if w.Failures() != 2 {
+		t.Errorf("Expected 2 failures, got %d", w.Failures())
+	}
+	if len(reported) != 2 || !errors.Is(reported[0], sinkErr) {
+		t.Errorf("Expected OnError to receive the sink error twice, got %v", reported)
+	}
+	if !bytes.Contains(fallback.Bytes(), []byte("first")) || !bytes.Contains(fallback.Bytes(), []byte("second")) {
+		t.Errorf("Failed entries should be written to the fallback, got %q", fallback.Bytes())
+	}
+}
+
+func TestFallbackWriterPassesThrough(t *testing.T) {
+	var buf bytes.Buffer
+	var fallback bytes.Buffer
+	w := &FallbackWriter{Writer: &log.IOWriter{Writer: &buf}, Fallback: &fallback}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(w)
+
+	logger.Info("ok")
+	if !bytes.Contains(buf.Bytes(), []byte("ok")) {
+		t.Errorf("Expected entry in primary writer, got %q", buf.Bytes())
+	}
+	if fallback.Len() != 0 || w.Failures() != 0 {
+		t.Errorf("Successful writes should not reach the fallback, got %q", fallback.Bytes())
+	}
+}
+
+func TestSetErrorHandlerBehindAsync(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(failingWriter{err: errors.New("broken pipe")})
+	calls := 0
+	logger.SetErrorHandler(func(error) { calls++ })
+	logger.SetAsync(16)
+	w := findFallbackWriter(logger.logger.Writer)
+	w.Fallback = &bytes.Buffer{}
+
+	logger.Info("lost")
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if logger.WriteFailures() != 1 {
+		t.Errorf("Expected 1 write failure, got %d", logger.WriteFailures())
+	}
+	if calls != 1 {
+		t.Errorf("Expected the handler to be called once, got %d", calls)
+	}
+}
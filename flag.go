@@ -0,0 +1,68 @@
+package logging
+
+import "flag"
+
+var (
+	_ flag.Value = (*LogLevel)(nil)
+	_ flag.Value = (*Format)(nil)
+)
+
+// Set implements flag.Value, so a LogLevel can be bound to a command-line
+// flag:
+//
+//	level := logging.LogLevelInfo
+//	flag.Var(&level, "log-level", "debug, info, warning or error")
+func (level *LogLevel) Set(s string) error {
+	l, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	*level = l
+	return nil
+}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	if f == FormatJSON {
+		return "json"
+	}
+	return "console"
+}
+
+// Set implements flag.Value, accepting "console" or "json".
+func (f *Format) Set(s string) error {
+	format, err := parseFormat(s, *f)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// LevelFlag returns a flag.Value that reads and changes the level of l, so
+// a flag can adjust a logger that already exists:
+//
+//	flag.Var(logger.LevelFlag(), "log-level", "debug, info, warning or error")
+func (l *Logger) LevelFlag() flag.Value {
+	return levelFlag{l}
+}
+
+type levelFlag struct {
+	l *Logger
+}
+
+func (f levelFlag) String() string {
+	if f.l == nil {
+		return LogLevelInfo.String()
+	}
+	return f.l.Level().String()
+}
+
+func (f levelFlag) Set(s string) error {
+	level, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	f.l.SetLogLevel(level)
+	return nil
+}
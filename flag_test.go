@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestLogLevelFlag(t *testing.T) {
+	level := LogLevelInfo
+	format := FormatConsole
+	fs := newFlagSet()
+	fs.Var(&level, "log-level", "")
+	fs.Var(&format, "log-format", "")
+
+	if err := fs.Parse([]string{"-log-level", "debug", "-log-format", "json"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if level != LogLevelDebug || format != FormatJSON {
+		t.Errorf("Expected debug and json, got %v and %v", level, format)
+	}
+}
+
+func TestLogLevelFlagInvalid(t *testing.T) {
+	level := LogLevelInfo
+	fs := newFlagSet()
+	fs.Var(&level, "log-level", "")
+
+	err := fs.Parse([]string{"-log-level", "loud"})
+	if err == nil || !strings.Contains(err.Error(), "debug, info, warning, error") {
+		t.Errorf("Expected the valid levels in the error, got %v", err)
+	}
+	if level != LogLevelInfo {
+		t.Errorf("An invalid value should leave the level unchanged, got %v", level)
+	}
+}
+
+func TestLoggerLevelFlag(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	fs := newFlagSet()
+	fs.Var(logger.LevelFlag(), "log-level", "")
+
+	if err := fs.Parse([]string{"-log-level=error"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if logger.Level() != LogLevelError {
+		t.Errorf("Expected the flag to set the logger level, got %v", logger.Level())
+	}
+	if got := fs.Lookup("log-level").Value.String(); got != "error" {
+		t.Errorf("Expected the flag to report 'error', got %q", got)
+	}
+}
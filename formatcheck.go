@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// SetFormatCheck enables or disables checking that format strings match
+// their arguments. When enabled, every entry whose formatted message
+// contains fmt's error markers, such as %!v(MISSING) or %!(EXTRA ...), is
+// followed by a warning naming the calling file and line. This catches
+// mismatches go vet cannot see through the wrapper, at the cost of
+// formatting each message twice, so it is meant for development and tests.
+// It should be called before the logger is shared between goroutines.
+func (l *Logger) SetFormatCheck(enabled bool) {
+	l.checkFormat = enabled
+}
+
+// formatMismatch reports whether formatting v with format produces any of
+// fmt's error markers, and returns the formatted message.
+func formatMismatch(format string, v []any) (string, bool) {
+	if len(v) == 0 && !strings.Contains(format, "%") {
+		return format, false
+	}
+	s := fmt.Sprintf(format, v...)
+	return s, strings.Contains(s, "%!")
+}
+
+// warnFormat emits a warning if format does not match v. skip is the number
+// of stack frames between warnFormat and the user's call.
+func (l *Logger) warnFormat(format string, v []any, skip int) {
+	formatted, bad := formatMismatch(format, v)
+	if !bad {
+		return
+	}
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	l.appendFields(l.logger.Warn()).
+		Str("caller", caller).
+		Str("format", format).
+		Str("formatted", formatted).
+		Msg("logging: format string does not match its arguments")
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatCheckWarnsOnMismatch(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetFormatCheck(true)
+
+	logger.Info("user %s logged in from %s", "john")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected the entry and a warning, got %q", buf.Bytes())
+	}
+	var warning map[string]any
+	if err := json.Unmarshal(lines[1], &warning); err != nil {
+		t.Fatalf("Failed to parse warning: %v", err)
+	}
+	if warning["level"] != "warn" {
+		t.Errorf("Expected level 'warn', got '%v'", warning["level"])
+	}
+	if caller, _ := warning["caller"].(string); !strings.HasPrefix(caller, "formatcheck_test.go:") {
+		t.Errorf("Expected the caller location of the bad call, got %v", warning["caller"])
+	}
+	if formatted, _ := warning["formatted"].(string); !strings.Contains(formatted, "%!s(MISSING)") {
+		t.Errorf("Expected the formatted message, got %v", warning["formatted"])
+	}
+}
+
+func TestFormatCheckIgnoresValidCalls(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetFormatCheck(true)
+
+	logger.Info("user %s logged in", "john")
+	logger.Warning("static message")
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 2 {
+		t.Errorf("Expected no warnings for valid calls, got %q", buf.Bytes())
+	}
+}
+
+func TestFormatCheckDisabledByDefault(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.Info("%d items", "three")
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 1 {
+		t.Errorf("Format checking should be opt-in, got %q", buf.Bytes())
+	}
+}
@@ -2,4 +2,8 @@ module github.com/flyzard/go-logging
 
 go 1.24.0
 
-require github.com/phuslu/log v1.0.113
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/phuslu/log v1.0.113
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// DefaultVolatileFields are the fields normalized by NormalizeLog and
+// AssertGolden when called without field names: values that change from
+// run to run.
+var DefaultVolatileFields = []string{
+	"time", "pid", "hostname", "caller", "stack", "seq",
+	"request_id", "trace_id", "span_id", "run_id", "tx_id",
+	"duration", "elapsed",
+}
+
+// NormalizeLog replaces the values of the volatile fields in output, JSON
+// entries or console lines with key=value fields, by "<key>", so output
+// can be compared across runs. Without fields, DefaultVolatileFields are
+// normalized.
+func NormalizeLog(output []byte, fields ...string) []byte {
+	if len(fields) == 0 {
+		fields = DefaultVolatileFields
+	}
+	for _, key := range fields {
+		k := regexp.QuoteMeta(key)
+		value := `("(?:[^"\\]|\\.)*"|[^,}\]\s]*)`
+		output = regexp.MustCompile(`"`+k+`":`+value).ReplaceAll(output, []byte(`"`+key+`":"<`+key+`>"`))
+		output = regexp.MustCompile(`(^|\s)`+k+`=`+value).ReplaceAll(output, []byte(`${1}`+key+`=<`+key+`>`))
+	}
+	return output
+}
+
+// AssertGolden compares output, normalized by NormalizeLog with fields,
+// with the golden file at path and fails t, showing the first difference,
+// if they differ. This locks down log formats that operators depend on.
+//
+// When the test binary was run with -update, the golden file is written
+// instead. The flag is not registered by this package; declare it in the
+// test package:
+//
+//	var _ = flag.Bool("update", false, "update golden files")
+func AssertGolden(t testing.TB, path string, output []byte, fields ...string) bool {
+	t.Helper()
+	got := NormalizeLog(output, fields...)
+	if f := flag.Lookup("update"); f != nil && f.Value.String() == "true" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("logging: update golden file: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("logging: update golden file: %v", err)
+		}
+		return true
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("logging: golden file: %v (run the test with -update to create it)", err)
+		return false
+	}
+	if bytes.Equal(got, want) {
+		return true
+	}
+	t.Errorf("logging: output differs from %s (run the test with -update to accept it):\n%s", path, firstDiff(want, got))
+	return false
+}
+
+// firstDiff describes the first line at which want and got differ.
+func firstDiff(want, got []byte) string {
+	wl := strings.Split(string(want), "\n")
+	gl := strings.Split(string(got), "\n")
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g || i >= len(wl) || i >= len(gl) {
+			return fmt.Sprintf("line %d:\n\twant: %s\n\t got: %s", i+1, w, g)
+		}
+	}
+	return ""
+}
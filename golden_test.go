@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestNormalizeLog(t *testing.T) {
+	in := `{"time":"2024-05-01T12:00:00Z","level":"info","request_id":"a\"b","duration":1.5,"message":"ok"}` + "\n" +
+		`12:00:00 INF request_id=abc duration=12ms path=/ message="done"` + "\n"
+	want := `{"time":"<time>","level":"info","request_id":"<request_id>","duration":"<duration>","message":"ok"}` + "\n" +
+		`12:00:00 INF request_id=<request_id> duration=<duration> path=/ message="done"` + "\n"
+	if got := string(NormalizeLog([]byte(in))); got != want {
+		t.Errorf("Unexpected normalization:\n got %s\nwant %s", got, want)
+	}
+	if got := string(NormalizeLog([]byte(`{"path":"/x","time":"t"}`), "path")); got != `{"path":"<path>","time":"t"}` {
+		t.Errorf("Expected only the given fields normalized, got %s", got)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), WithTimeFormat(""))
+	logger.WithFields(Fields{"request_id": newRunID(), "status": 200}).Info("request served")
+	AssertGolden(t, filepath.Join("testdata", "request.golden"), buf.Bytes())
+}
+
+func TestAssertGoldenFailures(t *testing.T) {
+	if *update {
+		t.Skip("golden files are being updated")
+	}
+	var buf bytes.Buffer
+	NewLogger(WithOutput(&buf), WithFormat(FormatJSON)).Info("request served")
+	path := filepath.Join(t.TempDir(), "changed.golden")
+	os.WriteFile(path, []byte(`{"level":"info","message":"request served"}`+"\n"), 0o644)
+	failed := &fakeTB{}
+	if AssertGolden(failed, path, buf.Bytes()) || len(failed.errors) != 1 ||
+		!strings.Contains(failed.errors[0], "line 1:\n\twant: {\"level\"") {
+		t.Errorf("Expected the first differing line reported, got %v", failed.errors)
+	}
+	missing := &fakeTB{}
+	if AssertGolden(missing, filepath.Join(t.TempDir(), "missing.golden"), buf.Bytes()) || !strings.Contains(missing.errors[0], "-update") {
+		t.Errorf("Expected a missing golden file reported, got %v", missing.errors)
+	}
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+// HammerOptions configures Hammer.
+type HammerOptions struct {
+	// Goroutines is the number of goroutines logging at once. Defaults to 8.
+	Goroutines int
+
+	// Iterations is the number of rounds each goroutine runs. Defaults
+	// to 200.
+	Iterations int
+
+	// Sinks, if set, are swapped in turn as the destination of the logger
+	// while it is hammered. The original destination is restored
+	// afterwards; the sinks are neither flushed nor closed.
+	Sinks []log.Writer
+}
+
+// Hammer logs through l from many goroutines at once while they change
+// its level, the levels of named loggers, caller capture, stack traces and,
+// with HammerOptions.Sinks, its destination, deriving loggers with fields,
+// names and Clone and flushing as they go. It is meant to run under the race
+// detector, in the tests of code that configures or wraps a Logger:
+//
+//	func TestLoggingRace(t *testing.T) {
+//		logger := app.NewLogger(cfg)
+//		logging.Hammer(t, logger, logging.HammerOptions{})
+//	}
+//
+// A goroutine that panics fails t. Hammer owns l until it returns: l must
+// not be used elsewhere meanwhile. It restores the level, caller capture,
+// stack traces and destination of l afterwards, but not the levels of the
+// named loggers it creates, "hammer0" to "hammer3" and their children.
+func Hammer(t testing.TB, l *Logger, opts HammerOptions) {
+	t.Helper()
+	if opts.Goroutines <= 0 {
+		opts.Goroutines = 8
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = 200
+	}
+	tree := l.state.tree
+	level, caller, stack := l.Level(), tree.caller.Load(), tree.stack.Load()
+	defer func() {
+		l.SetLogLevel(level)
+		tree.caller.Store(caller)
+		tree.stack.Store(stack)
+	}()
+	var sinks *swapWriter
+	if len(opts.Sinks) > 0 {
+		orig := l.logger.Writer
+		sinks = &swapWriter{w: l.writer()}
+		l.logger.Writer = sinks
+		defer func() { l.logger.Writer = orig }()
+	}
+
+	levels := []LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarning, LogLevelError}
+	var wg sync.WaitGroup
+	for g := range opts.Goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("logging: hammer goroutine %d panicked: %v", g, r)
+				}
+			}()
+			named := l.Named(fmt.Sprintf("hammer%d", g%4))
+			for i := range opts.Iterations {
+				next := levels[(g+i)%len(levels)]
+				switch i % 8 {
+				case 0:
+					l.SetLogLevel(next)
+				case 1:
+					named.SetLogLevel(next)
+				case 2:
+					l.SetCaller(i%16 == 2)
+				case 3:
+					l.SetStackTrace(i%24 == 3, LogLevelError)
+				case 4:
+					if sinks != nil {
+						sinks.swap(opts.Sinks[(g+i)%len(opts.Sinks)])
+					}
+				case 5:
+					_ = l.Flush()
+				case 6:
+					l.Clone().WithFields(Fields{"clone": g}).Warning("hammer clone %d/%d", g, i)
+				case 7:
+					named.Named("child").Info("hammer child %d/%d", g, i)
+				}
+				fields := l.WithFields(Fields{"goroutine": g, "iteration": i})
+				fields.Debug("hammer %d/%d", g, i)
+				fields.Info("hammer %d/%d", g, i)
+				named.Warning("hammer %d/%d", g, i)
+				fields.Error("hammer %d/%d", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+}
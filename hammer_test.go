@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// checkEntries fails t unless sink holds only valid JSON entries.
+func checkEntries(t *testing.T, sink *syncBuffer) int {
+	t.Helper()
+	lines := bytes.Split(bytes.TrimSpace(sink.Bytes()), []byte("\n"))
+	for _, line := range lines {
+		if len(line) > 0 && !json.Valid(line) {
+			t.Fatalf("Corrupt entry %q", line)
+		}
+	}
+	return len(lines)
+}
+
+func TestHammerWriterChains(t *testing.T) {
+	chains := map[string]func(sink *syncBuffer) []Option{
+		"sync": func(sink *syncBuffer) []Option {
+			return []Option{WithOutput(sink)}
+		},
+		"async": func(sink *syncBuffer) []Option {
+			return []Option{WithWriter(NewAsyncWriter(log.IOWriter{Writer: sink}, 16))}
+		},
+		"sharded+dedup": func(sink *syncBuffer) []Option {
+			return []Option{WithWriter(NewShardedWriter(NewDedupWriter(log.IOWriter{Writer: sink}, time.Millisecond), 4))}
+		},
+		"batch+ring": func(sink *syncBuffer) []Option {
+			return []Option{WithWriter(NewRingWriter(NewBatchWriter(IOBatchSink{sink}, 7, time.Millisecond), 32))}
+		},
+		"filters+clock": func(sink *syncBuffer) []Option {
+			return []Option{
+				WithOutput(sink),
+				WithRedaction("password"),
+				WithPIIScrubbing(),
+				WithFieldAllowlist(FieldAllowlist{Keys: []string{"goroutine", "logger", "clone"}}),
+				WithClock(NewStepClock(time.Unix(0, 0), time.Millisecond)),
+				WithSequence(true),
+			}
+		},
+	}
+	for name, chain := range chains {
+		t.Run(name, func(t *testing.T) {
+			sink := new(syncBuffer)
+			logger := NewLogger(append(chain(sink), WithFormat(FormatJSON))...)
+			Hammer(t, logger, HammerOptions{Iterations: 50})
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			if n := checkEntries(t, sink); n == 0 {
+				t.Error("Expected entries")
+			}
+		})
+	}
+}
+
+func TestHammerSwapsAndRestores(t *testing.T) {
+	orig, first, second := new(syncBuffer), new(syncBuffer), new(syncBuffer)
+	logger := NewLogger(WithOutput(orig), WithFormat(FormatJSON), LogLevelWarning)
+	writer := logger.logger.Writer
+
+	Hammer(t, logger, HammerOptions{
+		Goroutines: 4,
+		Sinks:      []log.Writer{log.IOWriter{Writer: first}, log.IOWriter{Writer: second}},
+	})
+
+	for _, sink := range []*syncBuffer{orig, first, second} {
+		checkEntries(t, sink)
+	}
+	if first.Len() == 0 || second.Len() == 0 {
+		t.Error("Expected entries in every sink swapped in")
+	}
+	if logger.logger.Writer != writer {
+		t.Error("Expected the original writer restored")
+	}
+	if logger.Level() != LogLevelWarning || logger.state.tree.caller.Load() || logger.state.tree.stack.Load() != noStack {
+		t.Error("Expected the level, caller capture and stack traces restored")
+	}
+	before := orig.Len()
+	logger.Warning("after")
+	if orig.Len() == before {
+		t.Error("Expected entries back in the original writer")
+	}
+}
+
+func TestHammerRecorder(t *testing.T) {
+	rec := NewRecorder()
+	Hammer(t, rec.Logger(), HammerOptions{Iterations: 50})
+	if len(rec.Entries()) == 0 {
+		t.Error("Expected entries recorded")
+	}
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// levelState is the JSON document served and accepted by LevelHandler.
+type levelState struct {
+	Level      string            `json:"level,omitempty"`
+	Loggers    map[string]string `json:"loggers,omitempty"`
+	Caller     *bool             `json:"caller,omitempty"`
+	StackTrace string            `json:"stack_trace,omitempty"` // a level or "off"
+}
+
+// LevelHandler returns an http.Handler for the levels of the default
+// logger and its named loggers; see Logger.LevelHandler.
+func LevelHandler() http.Handler {
+	return Default().LevelHandler()
+}
+
+// LevelHandler returns an http.Handler that lets operators read and change
+// log levels at runtime, without redeploying:
+//
+//	GET  returns {"level": "info", "loggers": {"db": "debug"},
+//	     "caller": false, "stack_trace": "off"}
+//	PUT  accepts the same document; "level" sets the root level, each
+//	     entry of "loggers" sets the level of that named logger, and
+//	     "caller" and "stack_trace" toggle caller and stack capture (see
+//	     SetCaller and SetStackTrace)
+//
+// Fields missing from a PUT are left unchanged.
+//
+// The handler exposes no authentication; mount it on an internal or
+// protected listener.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if err := l.putLevels(r); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(l.levelState())
+	})
+}
+
+func (l *Logger) putLevels(r *http.Request) error {
+	var req levelState
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return err
+	}
+	// Parse everything first so a bad entry changes nothing.
+	var root LogLevel
+	if req.Level != "" {
+		level, err := ParseLevel(req.Level)
+		if err != nil {
+			return err
+		}
+		root = level
+	}
+	named := make(map[string]LogLevel, len(req.Loggers))
+	for name, s := range req.Loggers {
+		level, err := ParseLevel(s)
+		if err != nil {
+			return err
+		}
+		named[name] = level
+	}
+	var stack LogLevel
+	if req.StackTrace != "" && req.StackTrace != "off" {
+		level, err := ParseLevel(req.StackTrace)
+		if err != nil {
+			return err
+		}
+		stack = level
+	}
+
+	if req.Caller != nil {
+		l.SetCaller(*req.Caller)
+	}
+	if req.StackTrace != "" {
+		l.SetStackTrace(req.StackTrace != "off", stack)
+	}
+	n := &l.state.tree.names
+	if req.Level != "" {
+		n.setLevel("", root)
+	}
+	for name, level := range named {
+		n.setLevel(name, level)
+	}
+	return nil
+}
+
+// levelState returns the root level and the levels of all named loggers.
+func (l *Logger) levelState() levelState {
+	root, named := l.state.tree.names.snapshot()
+	caller := l.state.tree.caller.Load()
+	s := levelState{Level: root.String(), Caller: &caller, StackTrace: "off"}
+	if stack := l.state.tree.stack.Load(); stack != noStack {
+		s.StackTrace = LogLevel(stack).String()
+	}
+	if len(named) > 0 {
+		s.Loggers = make(map[string]string, len(named))
+		for name, level := range named {
+			s.Loggers[name] = level.String()
+		}
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveLevels(t *testing.T, h http.Handler, method, body string) (*httptest.ResponseRecorder, levelState) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, "/log/level", strings.NewReader(body)))
+	var s levelState
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+	}
+	return rec, s
+}
+
+func TestLevelHandlerGet(t *testing.T) {
+	logger, _ := testLogger(LogLevelWarning)
+	logger.Named("db").SetLogLevel(LogLevelDebug)
+
+	rec, s := serveLevels(t, logger.LevelHandler(), http.MethodGet, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if s.Level != "warning" || s.Loggers["db"] != "debug" {
+		t.Errorf("Unexpected levels: %+v", s)
+	}
+}
+
+func TestLevelHandlerPut(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	db := logger.Named("db")
+
+	rec, s := serveLevels(t, logger.LevelHandler(), http.MethodPut, `{"level": "error", "loggers": {"db": "debug", "http": "warn"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if logger.Level() != LogLevelError || db.Level() != LogLevelDebug {
+		t.Errorf("Expected levels to change, got root=%v db=%v", logger.Level(), db.Level())
+	}
+	if logger.Named("http").Level() != LogLevelWarning {
+		t.Error("Levels set for loggers not named yet should apply when they are")
+	}
+	if s.Loggers["http"] != "warning" {
+		t.Errorf("Expected the response to reflect the change, got %+v", s)
+	}
+}
+
+func TestLevelHandlerRejectsInvalid(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	h := logger.LevelHandler()
+
+	rec, _ := serveLevels(t, h, http.MethodPut, `{"level": "debug", "loggers": {"db": "loud"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+	if logger.Level() != LogLevelInfo {
+		t.Errorf("A rejected request should change nothing, got %v", logger.Level())
+	}
+	rec, _ = serveLevels(t, h, http.MethodDelete, "")
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") == "" {
+		t.Errorf("Expected 405 with Allow, got %d", rec.Code)
+	}
+}
+
+func TestLevelHandlerTogglesCallerAndStack(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	h := logger.LevelHandler()
+
+	_, s := serveLevels(t, h, http.MethodGet, "")
+	if s.Caller == nil || *s.Caller || s.StackTrace != "off" {
+		t.Errorf("Expected caller and stack traces off, got %+v", s)
+	}
+	rec, s := serveLevels(t, h, http.MethodPut, `{"caller": true, "stack_trace": "error"}`)
+	if rec.Code != http.StatusOK || !*s.Caller || s.StackTrace != "error" {
+		t.Fatalf("Expected caller and stack traces on, got %d %+v", rec.Code, s)
+	}
+	logger.Error("incident")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "handler_test.go") {
+		t.Errorf("Expected the caller of the logging call, got %q", caller)
+	}
+	if !strings.Contains(buf.String(), `"stack":"`) {
+		t.Errorf("Expected a stack trace, got %q", buf.Bytes())
+	}
+
+	serveLevels(t, h, http.MethodPut, `{"caller": false, "stack_trace": "off"}`)
+	buf.Reset()
+	logger.Error("resolved")
+	if strings.Contains(buf.String(), "caller") || strings.Contains(buf.String(), "stack") {
+		t.Errorf("Expected caller and stack traces off again, got %q", buf.Bytes())
+	}
+}
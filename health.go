@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrLoggerClosed is reported by Health once the logger has been closed.
+var ErrLoggerClosed = errors.New("logging: logger is closed")
+
+// HealthChecker is implemented by sink wrappers that can tell whether their
+// sink works. Healthy returns nil, or the reason the sink is unhealthy.
+type HealthChecker interface {
+	Healthy() error
+}
+
+// healthWindow is the number of recent deliveries a failure rate is
+// computed over.
+const healthWindow = 32
+
+// failureWindow tracks the outcome of the last healthWindow deliveries.
+type failureWindow struct {
+	mu       sync.Mutex
+	failed   [healthWindow]bool
+	n, next  int
+	failures int
+}
+
+// record adds the outcome of a delivery.
+func (w *failureWindow) record(failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.n == healthWindow && w.failed[w.next] {
+		w.failures--
+	}
+	w.failed[w.next] = failed
+	if failed {
+		w.failures++
+	}
+	w.next = (w.next + 1) % healthWindow
+	w.n = min(w.n+1, healthWindow)
+}
+
+// healthy reports an error when more than half of the recent deliveries
+// failed.
+func (w *failureWindow) healthy() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failures*2 > w.n {
+		return fmt.Errorf("%d of the last %d deliveries failed", w.failures, w.n)
+	}
+	return nil
+}
+
+// Healthy implements HealthChecker.
+func (s *RetrySink) Healthy() error { return s.recent.healthy() }
+
+// Healthy implements HealthChecker.
+func (w *RetryWriter) Healthy() error { return w.recent.healthy() }
+
+// Healthy implements HealthChecker. The sink is unhealthy while its breaker
+// is open.
+func (s *BreakerSink) Healthy() error { return s.breakerHealth() }
+
+// Healthy implements HealthChecker. The writer is unhealthy while its
+// breaker is open.
+func (w *BreakerWriter) Healthy() error { return w.breakerHealth() }
+
+func (b *breaker) breakerHealth() error {
+	if b.isOpen() {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// Healthy implements HealthChecker. The spool is unhealthy if its directory
+// cannot be used or most recent deliveries failed.
+func (w *SpoolWriter) Healthy() error {
+	w.once.Do(w.start)
+	if w.err != nil {
+		return w.err
+	}
+	return w.recent.healthy()
+}
+
+// Health checks every sink wrapper in the writer chain of l that
+// implements HealthChecker, in each branch of a chain fanning out to
+// several sinks, and returns their errors joined, each prefixed with the
+// wrapper's type, or nil if the log pipeline is healthy.
+func (l *Logger) Health() error {
+	if l.state.tree.closed.Load() {
+		return ErrLoggerClosed
+	}
+	var errs []error
+	walkWriters(l.logger.Writer, func(w any) {
+		if h, ok := w.(HealthChecker); ok {
+			if err := h.Healthy(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", sinkName(w), err))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// HealthHandler returns an http.Handler reporting the health of the default
+// logger; see Logger.HealthHandler.
+func HealthHandler() http.Handler {
+	return Default().HealthHandler()
+}
+
+// HealthHandler returns an http.Handler for /healthz-style probes: it
+// responds 200 "ok" when Health returns nil and 503 with the errors
+// otherwise, so a broken log pipeline is visible. To fold it into an
+// existing check, call Health directly.
+func (l *Logger) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := l.Health(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
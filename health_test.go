@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestHealthReportsFailingSinks(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	down := true
+	collector := BatchFunc(func([][]byte) error {
+		if down {
+			return Permanent(errCollectorDown)
+		}
+		return nil
+	})
+	retry := NewRetrySink(collector, RetryPolicy{})
+	breaker := NewBreakerSink(retry, BreakerPolicy{Threshold: 100, Report: io.Discard})
+	logger.SetWriter(NewBatchWriter(breaker, 1, time.Second))
+	if err := logger.Health(); err != nil {
+		t.Fatalf("Expected a fresh pipeline to be healthy, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		logger.Info("lost")
+	}
+	err := logger.Health()
+	if err == nil || !strings.Contains(err.Error(), "RetrySink: 3 of the last 3 deliveries failed") {
+		t.Errorf("Expected the retry sink to report its failure rate, got %v", err)
+	}
+
+	down = false
+	for i := 0; i < 4; i++ {
+		logger.Info("shipped")
+	}
+	if err := logger.Health(); err != nil {
+		t.Errorf("Expected the pipeline to recover once most deliveries succeed, got %v", err)
+	}
+}
+
+func TestHealthReportsOpenBreaker(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewBreakerWriter(failingWriter{err: errCollectorDown}, BreakerPolicy{Threshold: 1, Report: io.Discard}))
+	logger.Info("fails")
+	if err := logger.Health(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestHealthReportsFanOut(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(&log.MultiEntryWriter{
+		&log.IOWriter{Writer: io.Discard},
+		NewBreakerWriter(failingWriter{err: errCollectorDown}, BreakerPolicy{Threshold: 1, Report: io.Discard}),
+	})
+	logger.Info("fails")
+	if err := logger.Health(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected the sink behind the fan-out checked, got %v", err)
+	}
+}
+
+func TestHealthReportsBrokenSpool(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewSpoolWriter(filepath.Join(file, "spool"), &collectSink{}))
+	if err := logger.Health(); err == nil || !strings.Contains(err.Error(), "SpoolWriter: logging: spool") {
+		t.Errorf("Expected an unusable spool directory to be reported, got %v", err)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	rec := httptest.NewRecorder()
+	logger.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok\n" {
+		t.Errorf("Expected 200 ok, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	_ = logger.Close()
+	rec = httptest.NewRecorder()
+	logger.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "closed") {
+		t.Errorf("Expected 503 for a closed logger, got %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// PanicError is returned by RunJob when the job panicked.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("logging: job panicked: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// RunJob runs fn as the job name, logging its start, and its success or
+// failure with the duration. Entries logged through FromContext inside fn
+// carry the job name and a run ID unique to this run, so the entries of
+// one run can be told apart from the others. A panic in fn is logged with
+// its stack, like LogPanic, and returned as a *PanicError.
+func RunJob(ctx context.Context, l *Logger, name string, fn func(ctx context.Context) error) (err error) {
+	jl := l.scoped(ctx).WithFields(Fields{"job": name, "run_id": newRunID()})
+	ctx = NewContext(ctx, jl)
+	start := time.Now()
+	jl.Info("job started")
+	defer func() {
+		if r := recover(); r != nil {
+			jl.LogPanic(ctx, r, Fields{"duration": time.Since(start)})
+			err = &PanicError{Value: r}
+			return
+		}
+		if err != nil {
+			jl.WithFields(Fields{"duration": time.Since(start), "error": err}).Error("job failed")
+			return
+		}
+		jl.WithFields(Fields{"duration": time.Since(start)}).Info("job succeeded")
+	}()
+	return fn(ctx)
+}
+
+// newRunID returns a random 16-character hex ID.
+func newRunID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
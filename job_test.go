@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func decodeEntries(t *testing.T, data string) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Failed to parse entry %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestRunJobTagsEntries(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	err := RunJob(context.Background(), logger, "reindex", func(ctx context.Context) error {
+		FromContext(ctx).Info("progress")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunJob failed: %v", err)
+	}
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 3 || entries[2]["message"] != "job succeeded" {
+		t.Fatalf("Expected start, progress and success entries, got %v", entries)
+	}
+	for _, e := range entries {
+		if e["job"] != "reindex" || e["run_id"] != entries[0]["run_id"] {
+			t.Errorf("Expected every entry tagged with the run, got %v", e)
+		}
+	}
+}
+
+func TestRunJobFailures(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	boom := errors.New("boom")
+	if err := RunJob(context.Background(), logger, "sync", func(context.Context) error { return boom }); err != boom {
+		t.Errorf("Expected the job's error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), `"message":"job failed"`) {
+		t.Errorf("Expected a failure entry, got %q", buf.Bytes())
+	}
+
+	buf.Reset()
+	err := RunJob(context.Background(), logger, "sync", func(context.Context) error { panic(boom) })
+	var perr *PanicError
+	if !errors.As(err, &perr) || !errors.Is(err, boom) {
+		t.Errorf("Expected a *PanicError wrapping the panic, got %v", err)
+	}
+	if !strings.Contains(buf.String(), `"stack":"`) {
+		t.Errorf("Expected the panic logged with its stack, got %q", buf.Bytes())
+	}
+}
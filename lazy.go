@@ -0,0 +1,28 @@
+package logging
+
+import "fmt"
+
+// LazyString defers an expensive rendering until an entry is actually
+// emitted. Pass it as a format argument and the function is only called if
+// the level, sampler and rate limiter let the entry through:
+//
+//	l.Debug("state: %s", logging.LazyString(func() string { return dump(state) }))
+//
+// Values given to WithFields are encoded when WithFields is called, so
+// laziness only applies to message arguments.
+type LazyString func() string
+
+// String calls f.
+func (f LazyString) String() string {
+	return f()
+}
+
+// LazyValue is like LazyString for arbitrary values. The value returned by
+// the function is formatted with the verb and flags of the format string,
+// so %d, %x or %+v behave as if the value had been passed directly.
+type LazyValue func() any
+
+// Format implements fmt.Formatter.
+func (f LazyValue) Format(s fmt.State, verb rune) {
+	fmt.Fprintf(s, fmt.FormatString(s, verb), f())
+}
@@ -0,0 +1,39 @@
+package logging
+
+import "testing"
+
+func TestLazyStringNotEvaluatedWhenDisabled(t *testing.T) {
+	logger, buf := testLogger(LogLevelWarning)
+	calls := 0
+	expensive := LazyString(func() string {
+		calls++
+		return "rendered"
+	})
+
+	logger.Info("state: %s", expensive)
+	if calls != 0 {
+		t.Errorf("LazyString should not be evaluated for disabled levels, got %d calls", calls)
+	}
+
+	logger.Warning("state: %s", expensive)
+	entry, err := parseLogEntry(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if calls != 1 || entry.Message != "state: rendered" {
+		t.Errorf("Expected one evaluation and rendered message, got %d calls and '%s'", calls, entry.Message)
+	}
+}
+
+func TestLazyValueFormatting(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.Info("count=%05d hex=%x", LazyValue(func() any { return 42 }), LazyValue(func() any { return 255 }))
+	entry, err := parseLogEntry(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry.Message != "count=00042 hex=ff" {
+		t.Errorf("Expected verbs to apply to the lazy value, got '%s'", entry.Message)
+	}
+}
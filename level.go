@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns the lower-case name of the level.
+func (level LogLevel) String() string {
+	switch {
+	case level <= LogLevelDebug:
+		return "debug"
+	case level == LogLevelInfo:
+		return "info"
+	case level == LogLevelWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// ParseLevel returns the level named s. Names are case-insensitive and
+// "warn" is accepted for "warning".
+func ParseLevel(s string) (LogLevel, error) {
+	level, err := parseLevel(s)
+	if err != nil {
+		return level, fmt.Errorf("logging: %w", err)
+	}
+	return level, nil
+}
+
+func parseLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarning, nil
+	case "error":
+		return LogLevelError, nil
+	}
+	return LogLevelInfo, fmt.Errorf("unknown level %q (valid: debug, info, warning, error)", s)
+}
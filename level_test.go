@@ -0,0 +1,31 @@
+package logging
+
+import "testing"
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		"debug":    LogLevelDebug,
+		"INFO":     LogLevelInfo,
+		"warn":     LogLevelWarning,
+		" Warning": LogLevelWarning,
+		"error":    LogLevelError,
+	}
+	for s, want := range tests {
+		got, err := ParseLevel(s)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestLevelStringRoundTrip(t *testing.T) {
+	for _, level := range []LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarning, LogLevelError} {
+		got, err := ParseLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", level.String(), got, err, level)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/phuslu/log"
+)
+
+// wrapper is implemented by writers in this package that forward entries to
+// another destination, so lifecycle operations can walk the chain.
+type wrapper interface {
+	unwrap() any
+}
+
+func (w *AsyncWriter) unwrap() any      { return w.Writer }
+func (w *BatchWriter) unwrap() any      { return w.Sink }
+func (s IOBatchSink) unwrap() any       { return s.Writer }
+func (w *DedupWriter) unwrap() any      { return w.Writer }
+func (w *CoalescingWriter) unwrap() any { return w.Writer }
+func (w *ShardedWriter) unwrap() any    { return w.Writer }
+func (w *FallbackWriter) unwrap() any   { return w.Writer }
+func (w *RingWriter) unwrap() any       { return w.Writer }
+func (s *RetrySink) unwrap() any        { return s.Sink }
+func (w *RetryWriter) unwrap() any      { return w.Writer }
+func (w *SpoolWriter) unwrap() any      { return w.Sink }
+func (s *DeadLetterSink) unwrap() any   { return s.Sink }
+func (s *BreakerSink) unwrap() any      { return s.Sink }
+func (w *BreakerWriter) unwrap() any    { return w.Writer }
+func (s *AckSink) unwrap() any          { return s.Sender }
+func (s *verifiedSink) unwrap() any     { return s.BatchSink }
+func (w *TimeoutWriter) unwrap() any    { return w.Writer }
+func (s *TimeoutSink) unwrap() any      { return s.Sink }
+func (w *secretWriter) unwrap() any     { return w.Writer }
+func (w *SigningWriter) unwrap() any    { return w.Writer }
+func (w *ChainWriter) unwrap() any      { return w.Writer }
+func (w *EncryptingWriter) unwrap() any { return w.Writer }
+
+// walkWriters calls visit with w and every writer it forwards entries to,
+// outermost first, descending into each branch of fan-out writers such as
+// the MultiEntryWriter built for configs with several sinks.
+func walkWriters(w any, visit func(w any)) {
+	pruneWriters(w, func(w any) bool {
+		visit(w)
+		return true
+	})
+}
+
+// pruneWriters is walkWriters, skipping the writers that a writer for which
+// visit returns false forwards entries to.
+func pruneWriters(w any, visit func(w any) bool) {
+	if w == nil || !visit(w) {
+		return
+	}
+	switch w := w.(type) {
+	case *log.MultiEntryWriter:
+		for _, inner := range *w {
+			pruneWriters(inner, visit)
+		}
+	case *splitWriter:
+		pruneWriters(w.out, visit)
+		pruneWriters(w.err, visit)
+	case log.IOWriter:
+		pruneWriters(w.Writer, visit)
+	case *log.IOWriter:
+		pruneWriters(w.Writer, visit)
+	case *log.ConsoleWriter:
+		pruneWriters(w.Writer, visit)
+	case wrapper:
+		pruneWriters(w.unwrap(), visit)
+	}
+}
+
+// ShutdownError is returned by Logger.Shutdown when its context expires
+// before all pending entries were written.
+type ShutdownError struct {
+	// Dropped is the number of entries still pending when the deadline hit.
+	Dropped int
+	// Err is the context error.
+	Err error
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("logging: shutdown: %d entries not written: %v", e.Dropped, e.Err)
+}
+
+// Unwrap returns the context error.
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// bufferer is implemented by writers that hold entries before passing them on.
+type bufferer interface {
+	buffered() int
+}
+
+// pendingEntries counts the entries held by the writer chain starting at w.
+func pendingEntries(w any) int {
+	n := 0
+	for w != nil {
+		if b, ok := w.(bufferer); ok {
+			n += b.buffered()
+		}
+		inner, ok := w.(wrapper)
+		if !ok {
+			break
+		}
+		w = inner.unwrap()
+	}
+	return n
+}
+
+// closedWriter receives the entries of loggers that have been closed.
+var closedWriter io.Writer = os.Stderr
+
+// logClosed writes an entry logged after Close to closedWriter, so it is
+// neither lost silently nor sent to a closed sink. Registered secrets are
+// still masked.
+func (l *Logger) logClosed(level LogLevel, format string, v []any) {
+	fallback := *l.logger
+	fallback.Writer = log.IOWriter{Writer: closedWriter}
+	if secrets := l.secrets(); secrets != nil {
+		fallback.Writer = &secretWriter{Writer: fallback.Writer, secrets: secrets}
+	}
+	e := fallback.WithLevel(level.phuslu())
+	if l.state.tree.caller.Load() {
+		e = e.Caller(callerDepth + 1)
+	}
+	text, args := l.scrubbed(clampFormat(format, v))
+	msg(l.appendFields(e), text, args)
+}
+
+// flushWriter flushes w if it buffers entries. Buffering writers in this
+// package flush the writers they wrap themselves.
+func flushWriter(w any) error {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// syncWriter commits every file at the end of the writer chain starting at
+// w to stable storage.
+func syncWriter(w any) error {
+	switch w := w.(type) {
+	case nil:
+		return nil
+	case *os.File:
+		if isStdStream(w) {
+			return nil
+		}
+		return w.Sync()
+	case *PreallocFileWriter:
+		return w.Flush()
+	case interface{ Sync() error }:
+		return w.Sync()
+	case wrapper:
+		return syncWriter(w.unwrap())
+	case log.IOWriter:
+		return syncWriter(w.Writer)
+	case *log.IOWriter:
+		return syncWriter(w.Writer)
+	case log.IOWriteCloser:
+		return syncWriter(w.WriteCloser)
+	case *log.ConsoleWriter:
+		return syncWriter(w.Writer)
+	case *log.MultiEntryWriter:
+		var err error
+		for _, inner := range *w {
+			if serr := syncWriter(inner); err == nil {
+				err = serr
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// closeWriter closes w if it is closable. Writers wrapping the process's
+// standard output or error are left open.
+func closeWriter(w any) error {
+	switch w := w.(type) {
+	case nil:
+		return nil
+	case *os.File:
+		if isStdStream(w) {
+			return nil
+		}
+	case *log.ConsoleWriter:
+		if f, ok := w.Writer.(*os.File); ok && isStdStream(f) {
+			return nil
+		}
+	case log.IOWriteCloser:
+		if f, ok := w.WriteCloser.(*os.File); ok && isStdStream(f) {
+			return nil
+		}
+	case log.IOWriter:
+		return closeWriter(w.Writer)
+	case *log.IOWriter:
+		return closeWriter(w.Writer)
+	case *log.MultiEntryWriter:
+		var err error
+		for _, inner := range *w {
+			if cerr := closeWriter(inner); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func isStdStream(f *os.File) bool {
+	return f == os.Stdout || f == os.Stderr
+}
@@ -0,0 +1,214 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// syncFile records Sync and Close calls on top of a real file.
+type syncFile struct {
+	*os.File
+	synced, closed bool
+}
+
+func (f *syncFile) Sync() error {
+	f.synced = true
+	return f.File.Sync()
+}
+
+func (f *syncFile) Close() error {
+	f.closed = true
+	return f.File.Close()
+}
+
+func TestCloseDrainsAndClosesSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create log file: %v", err)
+	}
+	file := &syncFile{File: f}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(log.IOWriteCloser{WriteCloser: file})
+	logger.SetDedup(time.Hour)
+	logger.SetAsync(16)
+
+	logger.Info("final words")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !file.closed {
+		t.Error("Close should close the file sink")
+	}
+	data, _ := os.ReadFile(path)
+	if !bytes.Contains(data, []byte("final words")) {
+		t.Errorf("Close should drain buffered entries, got %q", data)
+	}
+}
+
+func TestSyncReachesFileSink(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatalf("Failed to create log file: %v", err)
+	}
+	defer f.Close()
+	file := &syncFile{File: f}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewCoalescingWriter(file))
+	logger.SetAsync(16)
+
+	logger.Info("durable")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !file.synced {
+		t.Error("Sync should reach the file at the end of the writer chain")
+	}
+}
+
+func TestCloseKeepsStdStreamsOpen(t *testing.T) {
+	logger := NewLogger(LogLevelInfo)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stdout.Stat(); err != nil {
+		t.Errorf("Close should not close stdout: %v", err)
+	}
+}
+
+func TestCloseTwiceIsNoop(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	closes := 0
+	logger.SetWriter(log.IOWriteCloser{WriteCloser: closeCounter{&closes}})
+	derived := logger.WithFields(Fields{"component": "db"})
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Second Close should be a no-op, got %v", err)
+	}
+	if err := derived.Close(); err != nil {
+		t.Errorf("Closing a derived logger after its parent should be a no-op, got %v", err)
+	}
+	if closes != 1 {
+		t.Errorf("Expected the sink to be closed once, got %d", closes)
+	}
+}
+
+func TestLogAfterCloseGoesToStderr(t *testing.T) {
+	var stderr bytes.Buffer
+	closedWriter = &stderr
+	defer func() { closedWriter = os.Stderr }()
+
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetAsync(16)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	logger.WithFields(Fields{"component": "db"}).Warning("late %s", "entry")
+
+	if buf.Len() != 0 {
+		t.Errorf("Entries logged after Close should not reach the closed sink, got %q", buf.Bytes())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected the entry on stderr: %v (%q)", err, stderr.Bytes())
+	}
+	if entry["message"] != "late entry" || entry["level"] != "warn" || entry["component"] != "db" {
+		t.Errorf("Unexpected fallback entry: %v", entry)
+	}
+}
+
+func TestLogAfterCloseMasksSecrets(t *testing.T) {
+	var stderr bytes.Buffer
+	closedWriter = &stderr
+	defer func() { closedWriter = os.Stderr }()
+
+	logger, _ := testLogger(LogLevelInfo)
+	logger.RegisterSecrets("sk-live-1234")
+	logger.SetAsync(16)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	logger.Warning("retrying with key %s", "sk-live-1234")
+
+	if strings.Contains(stderr.String(), "sk-live-1234") || !strings.Contains(stderr.String(), Redacted) {
+		t.Errorf("Expected the secret masked after Close, got %q", stderr.Bytes())
+	}
+}
+
+// closeCounter is a WriteCloser counting Close calls.
+type closeCounter struct {
+	n *int
+}
+
+func (c closeCounter) Write(p []byte) (int, error) { return len(p), nil }
+func (c closeCounter) Close() error                { *c.n++; return nil }
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+}
+
+func (w blockingWriter) WriteEntry(e *log.Entry) (int, error) {
+	<-w.release
+	return 0, nil
+}
+
+func TestShutdownDrainsAndStopsLogging(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetAsync(16)
+	derived := logger.WithFields(Fields{"component": "db"})
+
+	logger.Info("before shutdown")
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("before shutdown")) {
+		t.Errorf("Shutdown should drain queued entries, got %q", buf.Bytes())
+	}
+	closedWriter = io.Discard
+	defer func() { closedWriter = os.Stderr }()
+	n := buf.Len()
+	logger.Info("after shutdown")
+	derived.Error("after shutdown")
+	if buf.Len() != n {
+		t.Errorf("Entries logged after Shutdown should not reach the sinks, got %q", buf.Bytes())
+	}
+}
+
+func TestShutdownDeadlineReportsDropped(t *testing.T) {
+	sink := blockingWriter{release: make(chan struct{})}
+	defer close(sink.release)
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(sink)
+	logger.SetAsync(16)
+	for i := 0; i < 5; i++ {
+		logger.Info("entry %d", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := logger.Shutdown(ctx)
+	var serr *ShutdownError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Expected *ShutdownError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the error to wrap the context error, got %v", err)
+	}
+	if serr.Dropped != 5 {
+		t.Errorf("Expected 5 dropped entries, got %d", serr.Dropped)
+	}
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// maxLineLength bounds the buffered part of a line; longer lines are
+// logged in pieces.
+const maxLineLength = 64 << 10
+
+// LineWriter is an io.Writer that logs each line written to it as one
+// entry at a fixed level, for bridging line-oriented output such as a
+// child process's into structured logs. A trailing partial line is held
+// until it is completed or Flush is called.
+type LineWriter struct {
+	logger *Logger
+	level  LogLevel
+	json   bool
+	rules  []LineRule
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// LineOption configures a LineWriter.
+type LineOption func(w *LineWriter)
+
+// WithJSONPassthrough makes a LineWriter recognize lines holding a JSON
+// object, such as the output of another structured logger, and log them
+// without wrapping them in a second entry: the object's "message" (or
+// "msg") becomes the message, its "level" the level when it names one, its
+// "time" the source_time field and its other members fields.
+func WithJSONPassthrough() LineOption {
+	return func(w *LineWriter) { w.json = true }
+}
+
+// NewLineWriter returns a LineWriter logging to l at level.
+func NewLineWriter(l *Logger, level LogLevel, opts ...LineOption) *LineWriter {
+	w := &LineWriter{logger: l, level: level}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// CopyLines logs each line read from r, such as a pipe, file or socket,
+// at level until r is exhausted, and returns the read error, if any,
+// other than io.EOF.
+func CopyLines(l *Logger, level LogLevel, r io.Reader, opts ...LineOption) error {
+	w := NewLineWriter(l, level, opts...)
+	_, err := io.Copy(w, r)
+	_ = w.Flush()
+	return err
+}
+
+// Write implements io.Writer.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) >= maxLineLength {
+				w.emit(w.buf)
+				w.buf = w.buf[:0]
+			}
+			break
+		}
+		if len(w.buf) > 0 {
+			w.buf = append(w.buf, p[:i]...)
+			w.emit(w.buf)
+			w.buf = w.buf[:0]
+		} else {
+			w.emit(p[:i])
+		}
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Flush logs a pending partial line.
+func (w *LineWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = w.buf[:0]
+	}
+	return nil
+}
+
+// emit logs line, without its carriage return if it ends with \r\n.
+func (w *LineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if w.json && w.emitJSON(line) {
+		return
+	}
+	level, ok := w.applyRules(line)
+	if !ok || !w.logger.enabled(level) {
+		return
+	}
+	w.logger.log(level, "%s", []any{line})
+}
+
+// emitJSON logs line as a structured entry if it holds a JSON object.
+func (w *LineWriter) emitJSON(line []byte) bool {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	var fields Fields
+	if json.Unmarshal(trimmed, &fields) != nil {
+		return false
+	}
+	level := w.level
+	if s, ok := fields["level"].(string); ok {
+		if l, err := parseLevel(s); err == nil {
+			level = l
+			delete(fields, "level")
+		}
+	}
+	if !w.logger.enabled(level) {
+		return true
+	}
+	var msg any = ""
+	for _, key := range []string{"message", "msg"} {
+		if m, ok := fields[key]; ok {
+			msg = m
+			delete(fields, key)
+			break
+		}
+	}
+	if t, ok := fields["time"]; ok {
+		fields["source_time"] = t
+		delete(fields, "time")
+	}
+	w.logger.WithFields(fields).log(level, "%v", []any{msg})
+	return true
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineWriterSplitsLines(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	w := NewLineWriter(logger, LogLevelWarning)
+	_, _ = w.Write([]byte("first\r\nsec"))
+	_, _ = w.Write([]byte("ond 100%\nthird"))
+	if strings.Contains(buf.String(), "third") {
+		t.Error("A partial line should be held until completed or flushed")
+	}
+	_ = w.Flush()
+
+	entries := decodeEntries(t, buf.String())
+	want := []string{"first", "second 100%", "third"}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, msg := range want {
+		if entries[i]["message"] != msg || entries[i]["level"] != "warn" {
+			t.Errorf("Entry %d: expected %q at warn, got %v", i, msg, entries[i])
+		}
+	}
+}
+
+func TestLineWriterSplitsLongLines(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	w := NewLineWriter(logger, LogLevelInfo)
+	_, _ = w.Write([]byte(strings.Repeat("x", maxLineLength+10)))
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Errorf("Expected an over-long line to be logged once it hits the limit, got %d entries", n)
+	}
+}
+
+func TestCopyLinesJSONPassthrough(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	input := "plain line\n" +
+		`{"level":"error","time":"2024-01-02T03:04:05Z","msg":"disk full","disk":"/dev/sda"}` + "\n" +
+		`{"level":"debug","message":"hidden"}` + "\n" +
+		"{not json}\n"
+	if err := CopyLines(logger, LogLevelInfo, strings.NewReader(input), WithJSONPassthrough()); err != nil {
+		t.Fatalf("CopyLines failed: %v", err)
+	}
+
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %q", len(entries), buf.Bytes())
+	}
+	if entries[0]["message"] != "plain line" || entries[0]["level"] != "info" {
+		t.Errorf("Unexpected plain entry: %v", entries[0])
+	}
+	structured := entries[1]
+	if structured["message"] != "disk full" || structured["level"] != "error" || structured["disk"] != "/dev/sda" {
+		t.Errorf("Expected the JSON line passed through, got %v", structured)
+	}
+	if structured["source_time"] != "2024-01-02T03:04:05Z" {
+		t.Errorf("Expected the source time kept, got %v", structured)
+	}
+	if entries[2]["message"] != "{not json}" {
+		t.Errorf("Invalid JSON should be logged as text, got %v", entries[2])
+	}
+}
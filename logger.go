@@ -1,8 +1,20 @@
 // Package logging provides a simple logging interface for the application.
+//
+// Entries logged by a single goroutine reach every sink in the order they
+// were logged, whatever combination of the writers in this package sits in
+// between: AsyncWriter, BatchWriter, DedupWriter, CoalescingWriter and
+// ShardedWriter all preserve it. Entries from different goroutines may
+// interleave; enable SetSequence to record the global order.
 package logging
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"os"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/phuslu/log"
 )
@@ -17,69 +29,544 @@ const (
 	LogLevelError
 )
 
+// LogLevelDebug enables debug messages in addition to all other levels.
+const LogLevelDebug LogLevel = -1
+
+// phuslu maps a LogLevel to the equivalent phuslu/log level.
+func (level LogLevel) phuslu() log.Level {
+	switch {
+	case level <= LogLevelDebug:
+		return log.DebugLevel
+	case level == LogLevelInfo:
+		return log.InfoLevel
+	case level == LogLevelWarning:
+		return log.WarnLevel
+	default:
+		return log.ErrorLevel
+	}
+}
+
 // LoggerInterface is the interface for the application's logging.
 type LoggerInterface interface {
 	Info(format string, v ...any)
 	Warning(format string, v ...any)
 	Error(format string, v ...any)
 	SetLogLevel(level LogLevel)
+	Flush() error
+	Close() error
+}
+
+var _ LoggerInterface = (*Logger)(nil)
+
+// Fields holds structured key/value pairs attached to log entries.
+type Fields map[string]any
+
+// loggerState holds the mutable state a logger shares with the loggers
+// derived from it.
+type loggerState struct {
+	level atomic.Int32
+	tree  *loggerTree
+	// entries counts emitted entries by level, see levelIndex. The states
+	// of withLevel loggers share the counters of the state they were
+	// derived from, so every entry is counted under a root or named logger.
+	entries *[4]atomic.Uint64
+}
+
+// loggerTree holds the state shared by a root logger and every logger
+// derived or named from it.
+type loggerTree struct {
+	closed  atomic.Bool
+	names   namedLevels
+	sampled [4]atomic.Uint64 // entries dropped by the sampler, by level
+	limited [4]atomic.Uint64 // entries dropped by the rate limiter, by level
+	caller  atomic.Bool      // add the caller to each entry
+	stack   atomic.Int32     // lowest level with a stack trace, or noStack
+	subs    subscribers
+	crash   atomic.Pointer[CrashOptions]
+	redact  atomic.Pointer[redactor]
+	scrub   atomic.Pointer[scrubber]
+	allow   atomic.Pointer[allowlist]
+	subject atomic.Pointer[[]byte] // key hashing subject identifiers
+	clock   atomic.Pointer[clockHolder]
+}
+
+func newLoggerTree(level LogLevel) *loggerTree {
+	tree := new(loggerTree)
+	tree.names.root = newLoggerState(level, tree)
+	tree.stack.Store(noStack)
+	return tree
+}
+
+// noStack disables stack traces when stored in loggerTree.stack.
+const noStack = math.MaxInt32
+
+func newLoggerState(level LogLevel, tree *loggerTree) *loggerState {
+	s := &loggerState{tree: tree, entries: new([4]atomic.Uint64)}
+	s.level.Store(int32(level))
+	return s
 }
 
 // Logger is the application's logging interface.
 type Logger struct {
-	logger   *log.Logger
-	logLevel LogLevel
+	logger  *log.Logger
+	state   *loggerState // shared with derived loggers
+	fields  Fields       // never modified once the logger is created
+	context log.Context  // fields pre-encoded in key order
+	sampler Sampler
+	limiter *RateLimiter
+	rateKey string
+	name    string // set by Named
+
+	checkFormat bool
+	sequence    bool
 }
 
-// NewLogger creates a new Logger instance.
-func NewLogger(logLevel LogLevel) *Logger {
+// NewLogger creates a new Logger configured by opts. Without options it logs
+// at LogLevelInfo to stdout in colored console format.
+func NewLogger(opts ...Option) *Logger {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return newLogger(&o)
+}
+
+func newLogger(o *options) *Logger {
 	l := log.Logger{
-		Writer: &log.ConsoleWriter{
-			Writer:         os.Stdout,
-			ColorOutput:    true,
-			QuoteString:    true,
-			EndWithMessage: true,
-		},
-		TimeFormat: "2006-01-02 15:04:05",
-	}
-	return &Logger{
+		Writer:     o.newWriter(),
+		TimeFormat: o.timeFormat,
+	}
+	tree := newLoggerTree(o.level)
+	tree.names.explicit = o.levels
+	tree.caller.Store(o.caller)
+	if o.stack != nil {
+		tree.stack.Store(int32(*o.stack))
+	}
+	tree.redact.Store(o.redact)
+	tree.scrub.Store(o.scrub)
+	tree.allow.Store(o.allow)
+	if o.clock != nil {
+		tree.clock.Store(&clockHolder{o.clock})
+	}
+	if o.subjectKey != nil {
+		tree.subject.Store(&o.subjectKey)
+	}
+	logger := &Logger{
 		logger:   &l,
-		logLevel: logLevel,
+		state:    tree.names.root,
+		sampler:  o.sampler,
+		limiter:  o.limiter,
+		sequence: o.sequence,
 	}
+	if o.buildInfo {
+		logger = logger.WithFields(buildFields())
+	}
+	return logger
+}
+
+// callerDepth is the number of frames between the user's call and the
+// caller lookup in log.
+const callerDepth = 3
+
+// WithFields returns a derived Logger that attaches fields to every entry.
+// The receiver is left unchanged. Field values are encoded when WithFields
+// is called, not when entries are written; a value whose encoding panics is
+// replaced by a description of the panic. Struct members tagged `log:"-"`
+// or `log:"omit"` are never encoded, like `json:"-"` for encoding/json.
+//
+// Derived loggers are copy-on-write: WithFields never modifies the fields of
+// the receiver or of fields itself, so any number of goroutines may derive
+// from a shared logger concurrently without affecting each other's entries.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	// Only the added fields are redacted, scrubbed and filtered: those of l
+	// already were, and hashing them again would change their values.
+	added := make(Fields, len(fields))
+	for k, v := range fields {
+		added[k] = omitTagged(v)
+	}
+	if r := l.state.tree.redact.Load(); r != nil {
+		r.redact(added)
+	}
+	if s := l.state.tree.scrub.Load(); s != nil {
+		s.scrubFields(added)
+	}
+	merged := make(Fields, len(l.fields)+len(added))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range added {
+		merged[k] = v
+	}
+	if a := l.state.tree.allow.Load(); a != nil {
+		a.filter(merged, added)
+	}
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var ctx []byte
+	for _, k := range keys {
+		ctx = appendField(ctx, k, merged[k])
+	}
+
+	c := l.derive()
+	c.fields = merged
+	c.context = ctx
+	return c
+}
+
+// WithRateLimitKey returns a derived Logger whose entries are rate limited
+// under key instead of their message template.
+func (l *Logger) WithRateLimitKey(key string) *Logger {
+	c := l.derive()
+	c.rateKey = key
+	return c
+}
+
+// derive returns a copy of l sharing its writer, level and configuration.
+func (l *Logger) derive() *Logger {
+	c := *l
+	return &c
+}
+
+// Clone returns an independent copy of l. The copy starts with the same
+// level, fields, writer and configuration, but changing its level, fields
+// or writer does not affect l, so a subsystem can tweak a shared logger
+// without constructing one from scratch. Writers themselves are shared:
+// closing the clone closes the sinks of l too. The sampler and rate limiter
+// are shared as well.
+func (l *Logger) Clone() *Logger {
+	inner := *l.logger
+	c := l.derive()
+	c.logger = &inner
+	tree := newLoggerTree(l.Level())
+	tree.closed.Store(l.state.tree.closed.Load())
+	tree.caller.Store(l.state.tree.caller.Load())
+	tree.stack.Store(l.state.tree.stack.Load())
+	tree.crash.Store(l.state.tree.crash.Load())
+	tree.redact.Store(l.state.tree.redact.Load())
+	tree.scrub.Store(l.state.tree.scrub.Load())
+	tree.allow.Store(l.state.tree.allow.Load())
+	tree.subject.Store(l.state.tree.subject.Load())
+	tree.clock.Store(l.state.tree.clock.Load())
+	c.state = tree.names.root
+	c.name = ""
+	if l.fields != nil {
+		c.fields = make(Fields, len(l.fields))
+		for k, v := range l.fields {
+			c.fields[k] = v
+		}
+	}
+	return c
 }
 
 // Info logs informational messages.
 func (l *Logger) Info(format string, v ...any) {
-	if l.logLevel <= LogLevelInfo {
-		l.logger.Info().Msgf(format, v...)
+	if l.enabled(LogLevelInfo) {
+		l.log(LogLevelInfo, format, v)
 	}
 }
 
 // Warning logs warning messages.
 func (l *Logger) Warning(format string, v ...any) {
-	if l.logLevel <= LogLevelWarning {
-		l.logger.Warn().Msgf(format, v...)
+	if l.enabled(LogLevelWarning) {
+		l.log(LogLevelWarning, format, v)
 	}
 }
 
 // Error logs error messages.
 func (l *Logger) Error(format string, v ...any) {
-	if l.logLevel <= LogLevelError {
-		l.logger.Error().Msgf(format, v...)
+	if l.enabled(LogLevelError) {
+		l.log(LogLevelError, format, v)
+	}
+}
+
+// log emits an entry at level if it passes the sampler and the rate limiter.
+// Once the logger is closed, entries go to stderr instead. A panic while
+// building or writing the entry is recovered and reported as an error entry.
+// Callers check the level first so disabled calls stay a single atomic load
+// and branch that the compiler can inline.
+func (l *Logger) log(level LogLevel, format string, v []any) {
+	defer l.recoverEntry(format)
+	if l.state.tree.closed.Load() {
+		l.logClosed(level, format, v)
+		return
+	}
+	if l.sampler != nil && !l.sampler.Sample(level) {
+		l.state.tree.sampled[levelIndex(level)].Add(1)
+		return
+	}
+	var suppressed int
+	if l.limiter != nil {
+		key := l.rateKey
+		if key == "" {
+			key = format
+		}
+		var ok bool
+		if ok, suppressed = l.limiter.Allow(key); !ok {
+			l.state.tree.limited[levelIndex(level)].Add(1)
+			return
+		}
+	}
+	tree := l.state.tree
+	l.state.entries[levelIndex(level)].Add(1)
+	clock := tree.clock.Load()
+	var e *log.Entry
+	var at time.Time // zero unless a clock is set
+	if clock != nil {
+		at = clock.Now()
+		e = l.clockEntry(level, at)
+	} else {
+		e = l.logger.WithLevel(level.phuslu())
+	}
+	if tree.caller.Load() {
+		e = e.Caller(callerDepth)
+	}
+	e = l.appendFields(e)
+	if l.sequence {
+		e = e.Uint64(SequenceField, sequence.Add(1))
+	}
+	if int32(level) >= tree.stack.Load() {
+		e = e.Stack()
+	}
+	if suppressed > 0 {
+		e = e.Int("suppressed", suppressed)
+	}
+	text, args := l.scrubbed(clampFormat(format, v))
+	publish := tree.subs.active.Load() > 0
+	if publish && len(args) > 0 {
+		// Format once for the entry and the subscribers, so lazy and
+		// Stringer arguments are not evaluated twice.
+		text, args = sanitizeText(fmt.Sprintf(text, args...)), nil
+	}
+	if clock != nil {
+		l.clockMsg(e, text, args)
+	} else {
+		msg(e, text, args)
+	}
+	if publish {
+		l.publish(at, level, text)
+	}
+	if l.checkFormat {
+		// log is called by Info, Warning, Error and Debug.
+		l.warnFormat(format, v, 2)
+	}
+}
+
+// msg finalizes e, skipping fmt formatting entirely when there are no
+// arguments so static messages do not allocate. Invalid UTF-8 and control
+// characters in the message are replaced, so they cannot corrupt the entry.
+func msg(e *log.Entry, format string, v []any) {
+	if len(v) == 0 {
+		e.Msg(sanitizeText(format))
+		return
+	}
+	b := msgBuffers.Get().(*msgBuffer)
+	b.formatted = fmt.Appendf(b.formatted[:0], format, v...)
+	text := b.formatted
+	if !cleanBytes(text) {
+		b.clean = appendCleanText(b.clean[:0], text)
+		text = b.clean
+	}
+	e.Bytes("message", text).Msg("")
+	if cap(b.formatted) <= maxPooledMessage && cap(b.clean) <= maxPooledMessage {
+		msgBuffers.Put(b)
 	}
 }
 
-// SetLogLevel changes the current log level of the logger.
+// appendFields copies the logger's fields into e. They are encoded once by
+// WithFields, so each entry only pays for a copy into its buffer.
+func (l *Logger) appendFields(e *log.Entry) *log.Entry {
+	return e.Context(l.context)
+}
+
+// enabled reports whether entries at level pass the level check. It is a
+// single atomic load, safe to race with SetLogLevel.
+func (l *Logger) enabled(level LogLevel) bool {
+	return LogLevel(l.state.level.Load()) <= level
+}
+
+// Level returns the current log level of the logger.
+func (l *Logger) Level() LogLevel {
+	return LogLevel(l.state.level.Load())
+}
+
+// SetLogLevel changes the current log level of the logger. It is safe to
+// call from any goroutine while others are logging.
+//
+// Loggers derived with WithFields or WithRateLimitKey share their level with
+// the logger they were derived from: changing the level of any of them
+// changes it for all. Use Clone for a logger with an independent level.
+// Named loggers have a level of their own, which their named descendants
+// inherit unless they set theirs; see Named.
 func (l *Logger) SetLogLevel(level LogLevel) {
-	l.logLevel = level
+	l.state.tree.names.setLevel(l.name, level)
+}
+
+// SetCaller turns capturing the file and line of the logging call on or
+// off for l and every logger derived or named from the same root. Caller
+// capture costs a stack walk per entry, so it can be enabled only while
+// investigating an incident; see also LevelHandler. It is safe to call
+// while others are logging.
+func (l *Logger) SetCaller(enabled bool) {
+	l.state.tree.caller.Store(enabled)
+}
+
+// SetStackTrace adds a stack trace to entries at level and above, or turns
+// stack traces off if enabled is false, for l and every logger derived or
+// named from the same root. It is safe to call while others are logging.
+func (l *Logger) SetStackTrace(enabled bool, level LogLevel) {
+	if !enabled {
+		l.state.tree.stack.Store(noStack)
+		return
+	}
+	l.state.tree.stack.Store(int32(level))
+}
+
+// SetSampler installs s to decide which entries are emitted. Sampling runs
+// before any formatting or encoding, so suppressed entries are cheap. A nil
+// sampler emits every entry. It should be called before the logger is
+// shared between goroutines.
+func (l *Logger) SetSampler(s Sampler) {
+	l.sampler = s
+}
+
+// SetRateLimiter caps how often entries sharing a message template (or the
+// key given to WithRateLimitKey) are emitted. A nil limiter disables rate
+// limiting. It should be called before the logger is shared between
+// goroutines.
+func (l *Logger) SetRateLimiter(r *RateLimiter) {
+	l.limiter = r
+}
+
+// SetWriter replaces the destination of log entries, for example with an
+// AsyncWriter configured with a custom OverflowPolicy. It should be called
+// before the logger is shared between goroutines.
+func (l *Logger) SetWriter(w log.Writer) {
+	l.logger.Writer = w
 }
 
-// Fatal logs a fatal message and exits the application.
+// SetAsync switches the logger to asynchronous mode: entries are queued on a
+// bounded channel of queueSize and written by a background goroutine. It
+// should be called before the logger is shared between goroutines.
+func (l *Logger) SetAsync(queueSize int) {
+	if _, ok := l.logger.Writer.(*AsyncWriter); ok {
+		return
+	}
+	l.logger.Writer = NewAsyncWriter(l.writer(), queueSize)
+}
+
+// SetDedup collapses the repeats of an entry seen within window into a
+// single entry carrying a repeat_count field, written after the first. It
+// should be called before the logger is shared between goroutines.
+func (l *Logger) SetDedup(window time.Duration) {
+	l.logger.Writer = NewDedupWriter(l.writer(), window)
+}
+
+// SetErrorHandler makes write failures visible: entries the current writer
+// fails to write are copied to stderr, onError (if not nil) is called with
+// the error, and failures are counted by WriteFailures. Install it before
+// SetAsync so failures of the background writes are caught too. It should
+// be called before the logger is shared between goroutines.
+func (l *Logger) SetErrorHandler(onError func(err error)) {
+	if fw := findFallbackWriter(l.logger.Writer); fw != nil {
+		fw.OnError = onError
+		return
+	}
+	l.logger.Writer = NewFallbackWriter(l.writer(), onError)
+}
+
+// WriteFailures returns the number of entries that failed to reach their
+// sink since SetErrorHandler was called.
+func (l *Logger) WriteFailures() uint64 {
+	if fw := findFallbackWriter(l.logger.Writer); fw != nil {
+		return fw.Failures()
+	}
+	return 0
+}
+
+// Flush blocks until all buffered and queued entries have been handed to
+// their sinks.
+func (l *Logger) Flush() error {
+	return flushWriter(l.logger.Writer)
+}
+
+// Sync flushes buffered entries and commits file sinks to stable storage.
+func (l *Logger) Sync() error {
+	if err := l.Flush(); err != nil {
+		return err
+	}
+	return syncWriter(l.logger.Writer)
+}
+
+// Shutdown stops accepting new entries, then drains pending entries to all
+// sinks and closes them, waiting at most until ctx is done. If ctx expires
+// first, Shutdown returns a *ShutdownError reporting how many entries were
+// still pending; draining continues in the background. Entries logged after
+// Shutdown are handled as after Close.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	if !l.state.tree.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() { done <- l.close() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return &ShutdownError{Dropped: pendingEntries(l.logger.Writer), Err: ctx.Err()}
+	}
+}
+
+// Close flushes buffered entries, stops background goroutines and closes
+// the sinks. The process's standard output and error are never closed.
+// Short-lived programs should call Close before exiting so final entries
+// are not lost.
+//
+// Closing a logger closes the loggers derived from it. Calling Close again
+// is a no-op, and entries logged after Close are written to stderr instead
+// of the closed sinks.
+func (l *Logger) Close() error {
+	if !l.state.tree.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	return l.close()
+}
+
+func (l *Logger) close() error {
+	l.state.tree.subs.closeAll()
+	err := l.Flush()
+	if cerr := closeWriter(l.logger.Writer); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// writer returns the current destination, defaulting to stderr like phuslu/log.
+func (l *Logger) writer() log.Writer {
+	if l.logger.Writer == nil {
+		return log.IOWriter{Writer: os.Stderr}
+	}
+	return l.logger.Writer
+}
+
+// Fatal logs a fatal message and exits the application, after flushing
+// buffered entries and saving the ring buffer as set by SetCrashOptions.
 func (l *Logger) Fatal(format string, v ...any) {
-	l.logger.Fatal().Msgf(format, v...)
+	fatal := *l.logger
+	fatal.Writer = crashWriter{Writer: l.writer(), l: l}
+	e := fatal.Fatal()
+	if l.state.tree.caller.Load() {
+		e = e.Caller(callerDepth - 1)
+	}
+	text, args := l.scrubbed(clampFormat(format, v))
+	msg(l.appendFields(e), text, args)
 }
 
 // Debug logs debug messages.
 func (l *Logger) Debug(format string, v ...any) {
-	log.Debug().Msgf(format, v...)
+	if l.enabled(LogLevelDebug) {
+		l.log(LogLevelDebug, format, v)
+	}
 }
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +30,30 @@ func testLogger(level LogLevel) (*Logger, *bytes.Buffer) {
 	return l, buf
 }
 
+// syncBuffer is a bytes.Buffer safe for use by background writers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func (b *syncBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
+
 func parseLogEntry(buf *bytes.Buffer) (logEntry, error) {
 	var entry logEntry
 	err := json.Unmarshal(buf.Bytes(), &entry)
@@ -36,8 +63,8 @@ func parseLogEntry(buf *bytes.Buffer) (logEntry, error) {
 func TestNewLogger(t *testing.T) {
 	t.Run("default log level", func(t *testing.T) {
 		logger, _ := testLogger(LogLevelInfo)
-		if logger.logLevel != LogLevelInfo {
-			t.Errorf("Expected default log level Info, got %v", logger.logLevel)
+		if logger.Level() != LogLevelInfo {
+			t.Errorf("Expected default log level Info, got %v", logger.Level())
 		}
 	})
 }
@@ -211,3 +238,244 @@ func TestLogFormatting(t *testing.T) {
 		})
 	}
 }
+
+func TestWithFields(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	child := logger.WithFields(Fields{"component": "db"}).WithFields(Fields{"attempt": 2})
+
+	child.Info("connected")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry["component"] != "db" || entry["attempt"] != float64(2) {
+		t.Errorf("Expected fields on entry, got %v", entry)
+	}
+
+	buf.Reset()
+	logger.Info("parent")
+	entry = nil
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if _, ok := entry["component"]; ok {
+		t.Error("WithFields should not modify the parent logger")
+	}
+}
+
+func TestSetWriter(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	buf := new(bytes.Buffer)
+	logger.SetWriter(&log.IOWriter{Writer: buf})
+
+	logger.Info("redirected")
+	entry, err := parseLogEntry(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry.Message != "redirected" {
+		t.Errorf("Expected message 'redirected', got '%s'", entry.Message)
+	}
+}
+
+func TestStaticMessage(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.Info("100% static")
+	entry, err := parseLogEntry(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry.Message != "100% static" {
+		t.Errorf("Expected message without formatting, got '%s'", entry.Message)
+	}
+}
+
+func TestDebugLevel(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.Debug("hidden")
+	if buf.Len() > 0 {
+		t.Error("Debug should not log at Info level")
+	}
+
+	logger.SetLogLevel(LogLevelDebug)
+	logger.Debug("visible")
+	entry, err := parseLogEntry(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry.Level != "debug" {
+		t.Errorf("Expected level 'debug', got '%s'", entry.Level)
+	}
+}
+
+func TestWithFieldsSnapshot(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	fields := Fields{"user": "john"}
+	child := logger.WithFields(fields)
+	fields["user"] = "jane"
+
+	child.Info("login")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry["user"] != "john" {
+		t.Errorf("Expected fields captured at WithFields time, got %v", entry["user"])
+	}
+}
+
+func TestSetLogLevelRace(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(log.IOWriter{Writer: io.Discard})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				logger.Info("message %d", j)
+			}
+		}()
+	}
+	for j := 0; j < 1000; j++ {
+		logger.SetLogLevel(LogLevel(j % 3))
+	}
+	wg.Wait()
+}
+
+func TestSetLogLevelPropagatesToDerived(t *testing.T) {
+	logger, buf := testLogger(LogLevelError)
+	child := logger.WithFields(Fields{"component": "http"})
+	grandchild := child.WithRateLimitKey("requests")
+
+	logger.SetLogLevel(LogLevelInfo)
+	grandchild.Info("visible")
+	if buf.Len() == 0 {
+		t.Error("Derived loggers should see level changes made on the parent")
+	}
+
+	buf.Reset()
+	child.SetLogLevel(LogLevelError)
+	logger.Info("hidden")
+	if buf.Len() > 0 {
+		t.Error("Level changes made on a derived logger should apply to the parent")
+	}
+}
+
+func TestSetLogLevelConcurrentDerived(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(log.IOWriter{Writer: io.Discard})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child := logger.WithFields(Fields{"worker": i})
+			for j := 0; j < 1000; j++ {
+				child.Info("message %d", j)
+			}
+		}(i)
+	}
+	for j := 0; j < 1000; j++ {
+		logger.SetLogLevel(LogLevel(j % 3))
+	}
+	wg.Wait()
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger = logger.WithFields(Fields{"service": "api"})
+
+	clone := logger.Clone().WithFields(Fields{"component": "cache"})
+	clone.SetLogLevel(LogLevelError)
+	subBuf := new(bytes.Buffer)
+	clone.SetWriter(&log.IOWriter{Writer: subBuf})
+
+	logger.Info("parent")
+	if buf.Len() == 0 {
+		t.Error("Changing the clone's level should not affect the original")
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if _, ok := entry["component"]; ok {
+		t.Error("Fields added to the clone should not reach the original")
+	}
+
+	clone.Error("clone")
+	entry = nil
+	if err := json.Unmarshal(subBuf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse clone entry: %v", err)
+	}
+	if entry["service"] != "api" || entry["component"] != "cache" {
+		t.Errorf("Clone should keep inherited fields, got %v", entry)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("clone")) {
+		t.Error("Setting the clone's writer should not redirect the original")
+	}
+}
+
+func TestWithFieldsConcurrentDerive(t *testing.T) {
+	sink := new(syncBuffer)
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(log.IOWriter{Writer: sink})
+	parent := logger.WithFields(Fields{"service": "api"})
+
+	const goroutines, perGoroutine = 8, 100
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				parent.WithFields(Fields{"g": g}).WithFields(Fields{"i": i}).Info("g=%d i=%d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSpace(sink.Bytes()), []byte("\n"))
+	if len(lines) != goroutines*perGoroutine {
+		t.Fatalf("Expected %d entries, got %d", goroutines*perGoroutine, len(lines))
+	}
+	for _, line := range lines {
+		var entry struct {
+			Service string `json:"service"`
+			G       int    `json:"g"`
+			I       int    `json:"i"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("Failed to parse log entry %q: %v", line, err)
+		}
+		if entry.Service != "api" || entry.Message != fmt.Sprintf("g=%d i=%d", entry.G, entry.I) {
+			t.Fatalf("Entry carries fields of another goroutine: %s", line)
+		}
+	}
+}
+
+func TestWithFieldsDoesNotMutateArgument(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	parent := logger.WithFields(Fields{"a": 1})
+	extra := Fields{"b": 2}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				parent.WithFields(extra)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(extra) != 1 || len(parent.fields) != 1 {
+		t.Errorf("WithFields should not modify its argument or the parent, got %v and %v", extra, parent.fields)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/flyzard/go-logging/logginggrpc
+
+go 1.24.0
+
+require (
+	github.com/flyzard/go-logging v0.0.0-20261016022418-6b7e99cb8989
+	google.golang.org/grpc v1.80.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	github.com/BurntSushi/toml v1.6.0 // indirect
+	github.com/phuslu/log v1.0.113 // indirect
+	golang.org/x/net v0.50.0 // indirect
+	golang.org/x/sys v0.41.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260120221211-b8f7ae30c516 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/flyzard/go-logging => ../
@@ -0,0 +1,157 @@
+// Package logginggrpc logs gRPC calls through a logging.Logger, with
+// interceptors for unary and streaming RPCs on both servers and clients.
+// It lives in its own module so the logging package does not depend on
+// gRPC.
+//
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(logginggrpc.UnaryServerInterceptor(logger, logging.RPCOptions{})),
+//		grpc.ChainStreamInterceptor(logginggrpc.StreamServerInterceptor(logger, logging.RPCOptions{})),
+//	)
+//
+// Each RPC is logged by logging.Logger.StartRPC: its start at Debug level,
+// its messages at Debug level if RPCOptions.LogPayloads is set, and its end
+// with the status code and duration. Handlers retrieve the RPC's logger
+// with logging.FromContext.
+package logginggrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/flyzard/go-logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor logging each unary RPC
+// served, with the address of the client as peer.
+func UnaryServerInterceptor(l *logging.Logger, opts logging.RPCOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, call := l.StartRPC(ctx, "server", info.FullMethod, peerAddr(ctx), opts)
+		call.Payload("request", req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			call.Payload("response", resp)
+		}
+		finish(call, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor logging each streaming
+// RPC served, and every message it sends and receives if payloads are
+// logged.
+func StreamServerInterceptor(l *logging.Logger, opts logging.RPCOptions) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, call := l.StartRPC(ss.Context(), "server", info.FullMethod, peerAddr(ss.Context()), opts)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx, call: call})
+		finish(call, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor logging each unary RPC
+// made, with the target of the connection as peer.
+func UnaryClientInterceptor(l *logging.Logger, opts logging.RPCOptions) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx, call := l.StartRPC(ctx, "client", method, cc.Target(), opts)
+		call.Payload("request", req)
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if err == nil {
+			call.Payload("response", reply)
+		}
+		finish(call, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor logging each streaming
+// RPC made. The RPC is finished when the stream fails to open, or when
+// receiving from it returns io.EOF or an error; a stream abandoned before
+// that is not logged as finished.
+func StreamClientInterceptor(l *logging.Logger, opts logging.RPCOptions) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, call := l.StartRPC(ctx, "client", method, cc.Target(), opts)
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			finish(call, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, call: call}, nil
+	}
+}
+
+// finish ends call with the gRPC status code of err.
+func finish(call *logging.RPCCall, err error) {
+	call.Finish(status.Code(err).String(), err)
+}
+
+// peerAddr returns the address of the remote end of the RPC in ctx, or "".
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// serverStream carries the RPC's logger in its context and logs messages.
+type serverStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	call *logging.RPCCall
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.call.Payload("response", m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.Payload("request", m)
+	}
+	return err
+}
+
+// clientStream logs messages and finishes the RPC when the stream ends.
+type clientStream struct {
+	grpc.ClientStream
+	call *logging.RPCCall
+	once sync.Once
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.call.Payload("request", m)
+	} else if !errors.Is(err, io.EOF) {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.call.Payload("response", m)
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() { finish(s.call, err) })
+}
@@ -0,0 +1,239 @@
+package logginggrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flyzard/go-logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of a server
+// and a client.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// entries decodes the entries written so far.
+func (b *syncBuffer) entries(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var entries []map[string]any
+	dec := json.NewDecoder(bytes.NewReader(b.buf.Bytes()))
+	for dec.More() {
+		var e map[string]any
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("Failed to parse entry: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// finished returns the "rpc finished" entries of the given kind.
+func finished(entries []map[string]any, kind string) []map[string]any {
+	var out []map[string]any
+	for _, e := range entries {
+		if e["message"] == "rpc finished" && e["rpc_kind"] == kind {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// dial serves the health service with the server interceptors and returns
+// a client connection using the client interceptors, both logging to buf.
+func dial(t *testing.T, opts logging.RPCOptions) (healthpb.HealthClient, *health.Server, *syncBuffer) {
+	t.Helper()
+	buf := new(syncBuffer)
+	logger := logging.NewLogger(logging.LogLevelDebug, logging.WithOutput(buf), logging.WithFormat(logging.FormatJSON))
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(logger, opts)),
+		grpc.StreamInterceptor(StreamServerInterceptor(logger, opts)),
+	)
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(logger, opts)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(logger, opts)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn), hs, buf
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	client, hs, buf := dial(t, logging.RPCOptions{LogPayloads: true})
+	hs.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "users"}); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected NotFound, got %v", err)
+	}
+
+	entries := buf.entries(t)
+	for _, kind := range []string{"server", "client"} {
+		done := finished(entries, kind)
+		if len(done) != 2 {
+			t.Fatalf("Expected 2 finished %s RPCs, got %d: %v", kind, len(done), entries)
+		}
+		ok, failed := done[0], done[1]
+		if ok["level"] != "info" || ok["rpc_code"] != "OK" || ok["rpc_method"] != "/grpc.health.v1.Health/Check" {
+			t.Errorf("Unexpected %s entry for a successful RPC: %v", kind, ok)
+		}
+		if failed["level"] != "warn" || failed["rpc_code"] != "NotFound" || failed["error"] == nil {
+			t.Errorf("Unexpected %s entry for a failed RPC: %v", kind, failed)
+		}
+		if _, ok := ok["duration"]; !ok {
+			t.Errorf("Expected the duration of %s RPCs, got %v", kind, ok)
+		}
+	}
+	if peer, _ := finished(entries, "server")[0]["peer"].(string); peer == "" {
+		t.Errorf("Expected the peer of server RPCs, got %v", finished(entries, "server")[0])
+	}
+	if target := finished(entries, "client")[0]["peer"]; target != "passthrough:///bufnet" {
+		t.Errorf("Expected the target of client RPCs, got %v", target)
+	}
+	payloads := 0
+	for _, e := range entries {
+		if e["message"] == "rpc payload" {
+			payloads++
+		}
+	}
+	if payloads != 6 {
+		t.Errorf("Expected requests logged on both sides and responses of the successful RPC, got %d payloads", payloads)
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	client, hs, buf := dial(t, logging.RPCOptions{LogPayloads: true})
+	hs.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "users"})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Expected a SERVING update, got %v, %v", resp, err)
+	}
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Fatalf("Expected the stream canceled, got %v", err)
+	}
+	hs.Shutdown() // ends the server side of the stream
+
+	waitFor(t, func() bool { return len(finished(buf.entries(t), "server")) == 1 })
+	entries := buf.entries(t)
+	client1 := finished(entries, "client")
+	if len(client1) != 1 || client1[0]["rpc_code"] != "Canceled" || client1[0]["rpc_method"] != "/grpc.health.v1.Health/Watch" {
+		t.Errorf("Expected the client stream finished as canceled, got %v", client1)
+	}
+	server := finished(entries, "server")[0]
+	if server["rpc_method"] != "/grpc.health.v1.Health/Watch" {
+		t.Errorf("Unexpected server stream entry: %v", server)
+	}
+	var recv, sent int
+	for _, e := range entries {
+		if e["message"] != "rpc payload" {
+			continue
+		}
+		switch {
+		case e["rpc_kind"] == "server" && e["direction"] == "request":
+			recv++
+		case e["rpc_kind"] == "client" && e["direction"] == "response":
+			sent++
+		}
+	}
+	if recv != 1 || sent < 1 {
+		t.Errorf("Expected stream messages logged, got %d requests on the server and %d responses on the client", recv, sent)
+	}
+}
+
+func TestStreamClientInterceptorOpenFailure(t *testing.T) {
+	buf := new(syncBuffer)
+	logger := logging.NewLogger(logging.LogLevelDebug, logging.WithOutput(buf), logging.WithFormat(logging.FormatJSON))
+	refused := status.Error(codes.Unavailable, "connection refused")
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, refused
+	}
+	conn, err := grpc.NewClient("passthrough:///unused", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = StreamClientInterceptor(logger, logging.RPCOptions{})(context.Background(), &grpc.StreamDesc{}, conn, "/svc/Method", streamer)
+	if !errors.Is(err, refused) {
+		t.Fatalf("Expected the streamer's error, got %v", err)
+	}
+	done := finished(buf.entries(t), "client")
+	if len(done) != 1 || done[0]["level"] != "error" || done[0]["rpc_code"] != "Unavailable" {
+		t.Errorf("Expected a failed open logged at Error level, got %v", done)
+	}
+}
+
+func TestClientStreamFinishesOnce(t *testing.T) {
+	buf := new(syncBuffer)
+	logger := logging.NewLogger(logging.LogLevelDebug, logging.WithOutput(buf), logging.WithFormat(logging.FormatJSON))
+	_, call := logger.StartRPC(context.Background(), "client", "/svc/Method", "", logging.RPCOptions{})
+	cs := &clientStream{ClientStream: eofStream{}, call: call}
+	for i := 0; i < 3; i++ {
+		if err := cs.RecvMsg(nil); err != io.EOF {
+			t.Fatalf("Expected io.EOF, got %v", err)
+		}
+	}
+	done := finished(buf.entries(t), "client")
+	if len(done) != 1 || done[0]["rpc_code"] != "OK" {
+		t.Errorf("Expected one OK finish at the end of the stream, got %v", done)
+	}
+}
+
+// eofStream is a grpc.ClientStream at its end.
+type eofStream struct{ grpc.ClientStream }
+
+func (eofStream) RecvMsg(any) error { return io.EOF }
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the server to finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,59 @@
+package logginggrpc
+
+//go:generate protoc -I ../proto --go_out=. --go_opt=module=github.com/flyzard/go-logging/logginggrpc --go-grpc_out=. --go-grpc_opt=module=github.com/flyzard/go-logging/logginggrpc levels.proto
+
+import (
+	"context"
+
+	"github.com/flyzard/go-logging"
+	"github.com/flyzard/go-logging/logginggrpc/loggingv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterLevelService registers the logging.v1.LevelService of
+// proto/levels.proto on s, reading and changing the levels of l and its
+// named loggers through logging.LevelService:
+//
+//	srv := grpc.NewServer()
+//	logginggrpc.RegisterLevelService(srv, logger)
+//
+// An invalid level passed to SetLevel fails with codes.InvalidArgument.
+func RegisterLevelService(s grpc.ServiceRegistrar, l *logging.Logger) {
+	loggingv1.RegisterLevelServiceServer(s, &levelServer{svc: logging.NewLevelService(l)})
+}
+
+// levelServer converts the messages of loggingv1 for a logging.LevelService.
+type levelServer struct {
+	loggingv1.UnimplementedLevelServiceServer
+	svc *logging.LevelService
+}
+
+func (s *levelServer) GetLevel(ctx context.Context, req *loggingv1.GetLevelRequest) (*loggingv1.GetLevelResponse, error) {
+	level, err := s.svc.GetLevel(ctx, req.GetLogger())
+	if err != nil {
+		return nil, err
+	}
+	return &loggingv1.GetLevelResponse{Logger: level.Logger, Level: level.Level}, nil
+}
+
+func (s *levelServer) SetLevel(ctx context.Context, req *loggingv1.SetLevelRequest) (*loggingv1.SetLevelResponse, error) {
+	level, err := s.svc.SetLevel(ctx, req.GetLogger(), req.GetLevel())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &loggingv1.SetLevelResponse{Logger: level.Logger, Level: level.Level}, nil
+}
+
+func (s *levelServer) ListLoggers(ctx context.Context, req *loggingv1.ListLoggersRequest) (*loggingv1.ListLoggersResponse, error) {
+	levels, err := s.svc.ListLoggers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &loggingv1.ListLoggersResponse{Loggers: make([]*loggingv1.LoggerLevel, len(levels))}
+	for i, level := range levels {
+		resp.Loggers[i] = &loggingv1.LoggerLevel{Logger: level.Logger, Level: level.Level}
+	}
+	return resp, nil
+}
@@ -0,0 +1,65 @@
+package logginggrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/flyzard/go-logging"
+	"github.com/flyzard/go-logging/logginggrpc/loggingv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestRegisterLevelService(t *testing.T) {
+	logger := logging.NewLogger(logging.LogLevelInfo, logging.WithOutput(new(syncBuffer)))
+	db := logger.Named("db")
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterLevelService(srv, logger)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := loggingv1.NewLevelServiceClient(conn)
+	ctx := context.Background()
+
+	set, err := client.SetLevel(ctx, &loggingv1.SetLevelRequest{Logger: "db", Level: "debug"})
+	if err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	if set.GetLogger() != "db" || set.GetLevel() != "debug" || db.Level() != logging.LogLevelDebug {
+		t.Errorf("Expected db set to debug, got %v and level %v", set, db.Level())
+	}
+	got, err := client.GetLevel(ctx, &loggingv1.GetLevelRequest{Logger: "db.pool"})
+	if err != nil {
+		t.Fatalf("GetLevel failed: %v", err)
+	}
+	if got.GetLevel() != "debug" {
+		t.Errorf("Expected db.pool to inherit debug, got %v", got)
+	}
+	_, err = client.SetLevel(ctx, &loggingv1.SetLevelRequest{Level: "loud"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an invalid level, got %v", err)
+	}
+
+	list, err := client.ListLoggers(ctx, &loggingv1.ListLoggersRequest{})
+	if err != nil {
+		t.Fatalf("ListLoggers failed: %v", err)
+	}
+	loggers := list.GetLoggers()
+	if len(loggers) != 2 || loggers[0].GetLogger() != "" || loggers[0].GetLevel() != "info" ||
+		loggers[1].GetLogger() != "db" || loggers[1].GetLevel() != "debug" {
+		t.Errorf("Unexpected loggers: %v", loggers)
+	}
+}
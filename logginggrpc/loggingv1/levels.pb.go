@@ -0,0 +1,446 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: levels.proto
+
+package loggingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetLevelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dot-separated logger name; empty for the root logger.
+	Logger        string `protobuf:"bytes,1,opt,name=logger,proto3" json:"logger,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLevelRequest) Reset() {
+	*x = GetLevelRequest{}
+	mi := &file_levels_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLevelRequest) ProtoMessage() {}
+
+func (x *GetLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLevelRequest.ProtoReflect.Descriptor instead.
+func (*GetLevelRequest) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetLevelRequest) GetLogger() string {
+	if x != nil {
+		return x.Logger
+	}
+	return ""
+}
+
+type GetLevelResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Logger string                 `protobuf:"bytes,1,opt,name=logger,proto3" json:"logger,omitempty"`
+	// One of "debug", "info", "warning", "error".
+	Level         string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLevelResponse) Reset() {
+	*x = GetLevelResponse{}
+	mi := &file_levels_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLevelResponse) ProtoMessage() {}
+
+func (x *GetLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLevelResponse.ProtoReflect.Descriptor instead.
+func (*GetLevelResponse) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetLevelResponse) GetLogger() string {
+	if x != nil {
+		return x.Logger
+	}
+	return ""
+}
+
+func (x *GetLevelResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type SetLevelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logger        string                 `protobuf:"bytes,1,opt,name=logger,proto3" json:"logger,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLevelRequest) Reset() {
+	*x = SetLevelRequest{}
+	mi := &file_levels_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLevelRequest) ProtoMessage() {}
+
+func (x *SetLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLevelRequest.ProtoReflect.Descriptor instead.
+func (*SetLevelRequest) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetLevelRequest) GetLogger() string {
+	if x != nil {
+		return x.Logger
+	}
+	return ""
+}
+
+func (x *SetLevelRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type SetLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logger        string                 `protobuf:"bytes,1,opt,name=logger,proto3" json:"logger,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLevelResponse) Reset() {
+	*x = SetLevelResponse{}
+	mi := &file_levels_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLevelResponse) ProtoMessage() {}
+
+func (x *SetLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLevelResponse.ProtoReflect.Descriptor instead.
+func (*SetLevelResponse) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetLevelResponse) GetLogger() string {
+	if x != nil {
+		return x.Logger
+	}
+	return ""
+}
+
+func (x *SetLevelResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type ListLoggersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoggersRequest) Reset() {
+	*x = ListLoggersRequest{}
+	mi := &file_levels_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoggersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoggersRequest) ProtoMessage() {}
+
+func (x *ListLoggersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoggersRequest.ProtoReflect.Descriptor instead.
+func (*ListLoggersRequest) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{4}
+}
+
+type ListLoggersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The root logger has an empty name and comes first.
+	Loggers       []*LoggerLevel `protobuf:"bytes,1,rep,name=loggers,proto3" json:"loggers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoggersResponse) Reset() {
+	*x = ListLoggersResponse{}
+	mi := &file_levels_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoggersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoggersResponse) ProtoMessage() {}
+
+func (x *ListLoggersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoggersResponse.ProtoReflect.Descriptor instead.
+func (*ListLoggersResponse) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLoggersResponse) GetLoggers() []*LoggerLevel {
+	if x != nil {
+		return x.Loggers
+	}
+	return nil
+}
+
+type LoggerLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logger        string                 `protobuf:"bytes,1,opt,name=logger,proto3" json:"logger,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoggerLevel) Reset() {
+	*x = LoggerLevel{}
+	mi := &file_levels_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoggerLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoggerLevel) ProtoMessage() {}
+
+func (x *LoggerLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_levels_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoggerLevel.ProtoReflect.Descriptor instead.
+func (*LoggerLevel) Descriptor() ([]byte, []int) {
+	return file_levels_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LoggerLevel) GetLogger() string {
+	if x != nil {
+		return x.Logger
+	}
+	return ""
+}
+
+func (x *LoggerLevel) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+var File_levels_proto protoreflect.FileDescriptor
+
+const file_levels_proto_rawDesc = "" +
+	"\n" +
+	"\flevels.proto\x12\n" +
+	"logging.v1\")\n" +
+	"\x0fGetLevelRequest\x12\x16\n" +
+	"\x06logger\x18\x01 \x01(\tR\x06logger\"@\n" +
+	"\x10GetLevelResponse\x12\x16\n" +
+	"\x06logger\x18\x01 \x01(\tR\x06logger\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"?\n" +
+	"\x0fSetLevelRequest\x12\x16\n" +
+	"\x06logger\x18\x01 \x01(\tR\x06logger\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"@\n" +
+	"\x10SetLevelResponse\x12\x16\n" +
+	"\x06logger\x18\x01 \x01(\tR\x06logger\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"\x14\n" +
+	"\x12ListLoggersRequest\"H\n" +
+	"\x13ListLoggersResponse\x121\n" +
+	"\aloggers\x18\x01 \x03(\v2\x17.logging.v1.LoggerLevelR\aloggers\";\n" +
+	"\vLoggerLevel\x12\x16\n" +
+	"\x06logger\x18\x01 \x01(\tR\x06logger\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level2\xec\x01\n" +
+	"\fLevelService\x12E\n" +
+	"\bGetLevel\x12\x1b.logging.v1.GetLevelRequest\x1a\x1c.logging.v1.GetLevelResponse\x12E\n" +
+	"\bSetLevel\x12\x1b.logging.v1.SetLevelRequest\x1a\x1c.logging.v1.SetLevelResponse\x12N\n" +
+	"\vListLoggers\x12\x1e.logging.v1.ListLoggersRequest\x1a\x1f.logging.v1.ListLoggersResponseB5Z3github.com/flyzard/go-logging/logginggrpc/loggingv1b\x06proto3"
+
+var (
+	file_levels_proto_rawDescOnce sync.Once
+	file_levels_proto_rawDescData []byte
+)
+
+func file_levels_proto_rawDescGZIP() []byte {
+	file_levels_proto_rawDescOnce.Do(func() {
+		file_levels_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_levels_proto_rawDesc), len(file_levels_proto_rawDesc)))
+	})
+	return file_levels_proto_rawDescData
+}
+
+var file_levels_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_levels_proto_goTypes = []any{
+	(*GetLevelRequest)(nil),     // 0: logging.v1.GetLevelRequest
+	(*GetLevelResponse)(nil),    // 1: logging.v1.GetLevelResponse
+	(*SetLevelRequest)(nil),     // 2: logging.v1.SetLevelRequest
+	(*SetLevelResponse)(nil),    // 3: logging.v1.SetLevelResponse
+	(*ListLoggersRequest)(nil),  // 4: logging.v1.ListLoggersRequest
+	(*ListLoggersResponse)(nil), // 5: logging.v1.ListLoggersResponse
+	(*LoggerLevel)(nil),         // 6: logging.v1.LoggerLevel
+}
+var file_levels_proto_depIdxs = []int32{
+	6, // 0: logging.v1.ListLoggersResponse.loggers:type_name -> logging.v1.LoggerLevel
+	0, // 1: logging.v1.LevelService.GetLevel:input_type -> logging.v1.GetLevelRequest
+	2, // 2: logging.v1.LevelService.SetLevel:input_type -> logging.v1.SetLevelRequest
+	4, // 3: logging.v1.LevelService.ListLoggers:input_type -> logging.v1.ListLoggersRequest
+	1, // 4: logging.v1.LevelService.GetLevel:output_type -> logging.v1.GetLevelResponse
+	3, // 5: logging.v1.LevelService.SetLevel:output_type -> logging.v1.SetLevelResponse
+	5, // 6: logging.v1.LevelService.ListLoggers:output_type -> logging.v1.ListLoggersResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_levels_proto_init() }
+func file_levels_proto_init() {
+	if File_levels_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_levels_proto_rawDesc), len(file_levels_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_levels_proto_goTypes,
+		DependencyIndexes: file_levels_proto_depIdxs,
+		MessageInfos:      file_levels_proto_msgTypes,
+	}.Build()
+	File_levels_proto = out.File
+	file_levels_proto_goTypes = nil
+	file_levels_proto_depIdxs = nil
+}
@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: levels.proto
+
+package loggingv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LevelService_GetLevel_FullMethodName    = "/logging.v1.LevelService/GetLevel"
+	LevelService_SetLevel_FullMethodName    = "/logging.v1.LevelService/SetLevel"
+	LevelService_ListLoggers_FullMethodName = "/logging.v1.LevelService/ListLoggers"
+)
+
+// LevelServiceClient is the client API for LevelService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LevelService reads and changes the log levels of a running process.
+// logging.LevelService implements it; logginggrpc.RegisterLevelService
+// serves it on a grpc.Server.
+type LevelServiceClient interface {
+	// GetLevel returns the effective level of a logger.
+	GetLevel(ctx context.Context, in *GetLevelRequest, opts ...grpc.CallOption) (*GetLevelResponse, error)
+	// SetLevel sets the level of a logger and of the descendants inheriting it.
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error)
+	// ListLoggers returns the root level and the level of every named logger.
+	ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error)
+}
+
+type levelServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLevelServiceClient(cc grpc.ClientConnInterface) LevelServiceClient {
+	return &levelServiceClient{cc}
+}
+
+func (c *levelServiceClient) GetLevel(ctx context.Context, in *GetLevelRequest, opts ...grpc.CallOption) (*GetLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLevelResponse)
+	err := c.cc.Invoke(ctx, LevelService_GetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLevelResponse)
+	err := c.cc.Invoke(ctx, LevelService_SetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceClient) ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoggersResponse)
+	err := c.cc.Invoke(ctx, LevelService_ListLoggers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LevelServiceServer is the server API for LevelService service.
+// All implementations must embed UnimplementedLevelServiceServer
+// for forward compatibility.
+//
+// LevelService reads and changes the log levels of a running process.
+// logging.LevelService implements it; logginggrpc.RegisterLevelService
+// serves it on a grpc.Server.
+type LevelServiceServer interface {
+	// GetLevel returns the effective level of a logger.
+	GetLevel(context.Context, *GetLevelRequest) (*GetLevelResponse, error)
+	// SetLevel sets the level of a logger and of the descendants inheriting it.
+	SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error)
+	// ListLoggers returns the root level and the level of every named logger.
+	ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error)
+	mustEmbedUnimplementedLevelServiceServer()
+}
+
+// UnimplementedLevelServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLevelServiceServer struct{}
+
+func (UnimplementedLevelServiceServer) GetLevel(context.Context, *GetLevelRequest) (*GetLevelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLevel not implemented")
+}
+func (UnimplementedLevelServiceServer) SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetLevel not implemented")
+}
+func (UnimplementedLevelServiceServer) ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLoggers not implemented")
+}
+func (UnimplementedLevelServiceServer) mustEmbedUnimplementedLevelServiceServer() {}
+func (UnimplementedLevelServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeLevelServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LevelServiceServer will
+// result in compilation errors.
+type UnsafeLevelServiceServer interface {
+	mustEmbedUnimplementedLevelServiceServer()
+}
+
+func RegisterLevelServiceServer(s grpc.ServiceRegistrar, srv LevelServiceServer) {
+	// If the following call panics, it indicates UnimplementedLevelServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LevelService_ServiceDesc, srv)
+}
+
+func _LevelService_GetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceServer).GetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LevelService_GetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceServer).GetLevel(ctx, req.(*GetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelService_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LevelService_SetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelService_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoggersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceServer).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LevelService_ListLoggers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceServer).ListLoggers(ctx, req.(*ListLoggersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LevelService_ServiceDesc is the grpc.ServiceDesc for LevelService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LevelService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logging.v1.LevelService",
+	HandlerType: (*LevelServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevel",
+			Handler:    _LevelService_GetLevel_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LevelService_SetLevel_Handler,
+		},
+		{
+			MethodName: "ListLoggers",
+			Handler:    _LevelService_ListLoggers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "levels.proto",
+}
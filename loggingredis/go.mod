@@ -0,0 +1,19 @@
+module github.com/flyzard/go-logging/loggingredis
+
+go 1.24.0
+
+require (
+	github.com/flyzard/go-logging v0.0.0-20261016023338-e591f9fceda5
+	github.com/redis/go-redis/v9 v9.22.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.6.0 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/phuslu/log v1.0.113 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/flyzard/go-logging => ../
@@ -0,0 +1,77 @@
+// Package loggingredis logs go-redis v9 commands through a logging.Logger.
+// It lives in its own module so the logging package does not depend on a
+// Redis client.
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	rdb.AddHook(loggingredis.NewHook(logger, logging.RedisOptions{RedactKeys: true, HashKey: salt}))
+//
+// Commands are logged by logging.RedisLogger: successful ones at Debug
+// level with their duration and result size, failed ones at Warning. A
+// redis.Nil reply, such as a GET of a missing key, is not a failure.
+package loggingredis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/flyzard/go-logging"
+	"github.com/redis/go-redis/v9"
+)
+
+// Hook is a redis.Hook logging connections, commands and pipelines.
+type Hook struct {
+	log *logging.RedisLogger
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+// NewHook returns a Hook logging to l, or to the logger carried by each
+// command's context if it was derived from l.
+func NewHook(l *logging.Logger, opts logging.RedisOptions) *Hook {
+	return &Hook{log: logging.NewRedisLogger(l, opts)}
+}
+
+// DialHook logs each new connection, and failed ones at Warning level.
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := next(ctx, network, addr)
+		h.log.Dial(ctx, network, addr, start, err)
+		return conn, err
+	}
+}
+
+// ProcessHook logs each command with its key and result size.
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.log.Command(ctx, cmd.Args(), start, cmd, replyError(err))
+		return err
+	}
+}
+
+// ProcessPipelineHook logs each pipeline, and transaction, with the names
+// of its commands.
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		args := make([][]any, len(cmds))
+		for i, cmd := range cmds {
+			args[i] = cmd.Args()
+		}
+		h.log.Pipeline(ctx, args, start, replyError(err))
+		return err
+	}
+}
+
+// replyError returns err, or nil for redis.Nil.
+func replyError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
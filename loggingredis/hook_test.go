@@ -0,0 +1,129 @@
+package loggingredis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/flyzard/go-logging"
+	"github.com/redis/go-redis/v9"
+)
+
+func testLogger(t *testing.T) (*logging.Logger, func() []map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := logging.NewLogger(logging.LogLevelDebug, logging.WithOutput(&buf), logging.WithFormat(logging.FormatJSON))
+	return logger, func() []map[string]any {
+		var entries []map[string]any
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var e map[string]any
+			if err := dec.Decode(&e); err != nil {
+				t.Fatalf("Failed to parse entry: %v", err)
+			}
+			entries = append(entries, e)
+		}
+		return entries
+	}
+}
+
+func TestProcessHook(t *testing.T) {
+	logger, entries := testLogger(t)
+	h := NewHook(logger, logging.RedisOptions{RedactKeys: true, HashKey: []byte("salt")})
+	ctx := context.Background()
+
+	get := redis.NewStringCmd(ctx, "get", "session:alice")
+	process := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		cmd.(*redis.StringCmd).SetVal("0123456789")
+		return nil
+	})
+	if err := process(ctx, get); err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	miss := redis.NewStringCmd(ctx, "get", "missing")
+	if err := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return redis.Nil })(ctx, miss); err != redis.Nil {
+		t.Fatalf("Expected redis.Nil passed through, got %v", err)
+	}
+	readonly := errors.New("READONLY You can't write against a read only replica")
+	set := redis.NewStatusCmd(ctx, "set", "k", "v")
+	if err := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return readonly })(ctx, set); err != readonly {
+		t.Fatalf("Expected the command error passed through, got %v", err)
+	}
+
+	got := entries()
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %v", len(got), got)
+	}
+	if e := got[0]; e["level"] != "debug" || e["redis_cmd"] != "get" || e["result_size"] != float64(10) {
+		t.Errorf("Unexpected entry for a successful command: %v", e)
+	}
+	if key, _ := got[0]["redis_key"].(string); key == "session:alice" {
+		t.Errorf("Expected a redacted key, got %q", key)
+	}
+	if e := got[1]; e["level"] != "debug" || e["error"] != nil {
+		t.Errorf("Expected a cache miss logged as a successful command, got %v", e)
+	}
+	if e := got[2]; e["level"] != "warn" || e["redis_cmd"] != "set" || e["error"] == nil {
+		t.Errorf("Unexpected entry for a failed command: %v", e)
+	}
+}
+
+func TestProcessPipelineHook(t *testing.T) {
+	logger, entries := testLogger(t)
+	h := NewHook(logger, logging.RedisOptions{})
+	ctx := context.Background()
+	cmds := []redis.Cmder{redis.NewIntCmd(ctx, "incr", "a"), redis.NewBoolCmd(ctx, "expire", "a", 10)}
+	pipeline := h.ProcessPipelineHook(func(context.Context, []redis.Cmder) error { return nil })
+	if err := pipeline(ctx, cmds); err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	failing := h.ProcessPipelineHook(func(context.Context, []redis.Cmder) error { return errors.New("EOF") })
+	if err := failing(ctx, cmds); err == nil {
+		t.Fatal("Expected the pipeline error passed through")
+	}
+
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %v", len(got), got)
+	}
+	names, _ := got[0]["redis_pipeline"].([]any)
+	if got[0]["level"] != "debug" || len(names) != 2 || names[0] != "incr" || names[1] != "expire" {
+		t.Errorf("Unexpected pipeline entry: %v", got[0])
+	}
+	if got[1]["level"] != "warn" || got[1]["error"] != "EOF" {
+		t.Errorf("Unexpected failed pipeline entry: %v", got[1])
+	}
+}
+
+func TestHookOnClient(t *testing.T) {
+	logger, entries := testLogger(t)
+	refused := errors.New("connection refused")
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       "cache:6379",
+		MaxRetries: -1,
+		Dialer: func(context.Context, string, string) (net.Conn, error) {
+			return nil, refused
+		},
+	})
+	defer rdb.Close()
+	rdb.AddHook(NewHook(logger, logging.RedisOptions{}))
+
+	if err := rdb.Get(context.Background(), "k").Err(); !errors.Is(err, refused) {
+		t.Fatalf("Expected the dial error, got %v", err)
+	}
+	var dial, command bool
+	for _, e := range entries() {
+		switch e["message"] {
+		case "redis dial failed":
+			dial = e["addr"] == "cache:6379" && e["level"] == "warn"
+		case "redis command failed":
+			command = e["redis_cmd"] == "get" && e["redis_key"] == "k"
+		}
+	}
+	if !dial || !command {
+		t.Errorf("Expected the failed dial and command logged, got %v", entries())
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"context"
+	"time"
+)
+
+// MessageInfo describes a consumed message for HandleMessages.
+type MessageInfo struct {
+	// ID identifies the message.
+	ID string
+
+	// Topic is the queue, topic or subject it was consumed from.
+	Topic string
+
+	// PublishedAt is when the message was published; the difference to
+	// the time it is received is logged as its lag. Zero omits the lag.
+	PublishedAt time.Time
+}
+
+// HandleMessages decorates a message handler of any queue client with
+// logging: each message is logged at Debug level when received, and at
+// Info level when acked (the handler returned nil) or Warning when nacked
+// (it returned an error), with its processing duration. describe extracts
+// the MessageInfo of a message. The logger returned by FromContext inside
+// handler carries the message_id and topic fields.
+//
+//	handle := logging.HandleMessages(l, func(m *sqs.Message) logging.MessageInfo {
+//		return logging.MessageInfo{ID: *m.MessageId, Topic: "orders"}
+//	}, processOrder)
+func HandleMessages[M any](l *Logger, describe func(M) MessageInfo, handler func(ctx context.Context, msg M) error) func(ctx context.Context, msg M) error {
+	return func(ctx context.Context, msg M) error {
+		received := time.Now()
+		info := describe(msg)
+		fields := Fields{"message_id": info.ID}
+		if info.Topic != "" {
+			fields["topic"] = info.Topic
+		}
+		ml := l.scoped(ctx).WithFields(fields)
+		if ml.enabled(LogLevelDebug) {
+			rf := Fields{}
+			if !info.PublishedAt.IsZero() {
+				rf["lag"] = received.Sub(info.PublishedAt)
+			}
+			ml.WithFields(rf).Debug("message received")
+		}
+
+		err := handler(NewContext(ctx, ml), msg)
+		done := Fields{"duration": time.Since(received)}
+		if err != nil {
+			done["error"] = err
+			ml.WithFields(done).Warning("message nacked")
+			return err
+		}
+		ml.WithFields(done).Info("message acked")
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testMessage struct {
+	id   string
+	sent time.Time
+}
+
+func TestHandleMessages(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	handle := HandleMessages(logger, func(m testMessage) MessageInfo {
+		return MessageInfo{ID: m.id, Topic: "orders", PublishedAt: m.sent}
+	}, func(ctx context.Context, m testMessage) error {
+		FromContext(ctx).Info("processing")
+		if m.id == "bad" {
+			return errors.New("invalid payload")
+		}
+		return nil
+	})
+
+	if err := handle(context.Background(), testMessage{id: "m1", sent: time.Now().Add(-time.Second)}); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 3 {
+		t.Fatalf("Expected received, processing and acked entries, got %d", len(entries))
+	}
+	if _, ok := entries[0]["lag"]; !ok {
+		t.Errorf("Expected the lag on the received entry, got %v", entries[0])
+	}
+	for _, e := range entries {
+		if e["message_id"] != "m1" || e["topic"] != "orders" {
+			t.Errorf("Expected message fields on every entry, got %v", e)
+		}
+	}
+	if entries[2]["message"] != "message acked" || entries[2]["duration"] == nil {
+		t.Errorf("Unexpected ack entry: %v", entries[2])
+	}
+
+	buf.Reset()
+	if err := handle(context.Background(), testMessage{id: "bad"}); err == nil {
+		t.Fatal("Expected the handler's error")
+	}
+	entries = decodeEntries(t, buf.String())
+	last := entries[len(entries)-1]
+	if last["message"] != "message nacked" || last["level"] != "warn" || last["error"] != "invalid payload" {
+		t.Errorf("Unexpected nack entry: %v", last)
+	}
+}
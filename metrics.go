@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EntryCount is the number of entries a logger emitted at one level.
+type EntryCount struct {
+	// Logger is the full name of a named logger, or empty for the root.
+	Logger string
+	Level  LogLevel
+	Count  uint64
+}
+
+// EntryCounts returns the number of entries emitted by the root logger of
+// l and by each of its named loggers, including the loggers derived from
+// them, such as those of DebugMiddleware, at each level, sorted by logger
+// name and level. Entries are counted once they pass sampling and rate
+// limiting. Use it to feed a metrics library, or MetricsHandler to expose
+// the counts without one.
+func (l *Logger) EntryCounts() []EntryCount {
+	n := &l.state.tree.names
+	n.mu.Lock()
+	names := []string{""}
+	states := map[string]*loggerState{"": n.root}
+	for name, state := range n.states {
+		names = append(names, name)
+		states[name] = state
+	}
+	n.mu.Unlock()
+	sort.Strings(names)
+
+	counts := make([]EntryCount, 0, len(names)*len(levelNames))
+	for _, name := range names {
+		state := states[name]
+		for i := range levelNames {
+			counts = append(counts, EntryCount{
+				Logger: name,
+				Level:  LogLevel(i - 1),
+				Count:  state.entries[i].Load(),
+			})
+		}
+	}
+	return counts
+}
+
+// WriteMetrics writes the counts returned by EntryCounts and Suppressed to
+// w in the Prometheus text exposition format.
+func (l *Logger) WriteMetrics(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# HELP logging_entries_total Log entries emitted, by level and named logger.\n")
+	bw.WriteString("# TYPE logging_entries_total counter\n")
+	for _, c := range l.EntryCounts() {
+		bw.WriteString(`logging_entries_total{level="`)
+		bw.WriteString(levelNames[levelIndex(c.Level)])
+		bw.WriteString(`",logger="`)
+		bw.WriteString(labelEscaper.Replace(c.Logger))
+		bw.WriteString(`"} `)
+		bw.WriteString(strconv.FormatUint(c.Count, 10))
+		bw.WriteByte('\n')
+	}
+
+	s := l.Suppressed()
+	bw.WriteString("# HELP logging_suppressed_total Log entries dropped by sampling or rate limiting, by level.\n")
+	bw.WriteString("# TYPE logging_suppressed_total counter\n")
+	for _, reason := range []struct {
+		name   string
+		counts map[LogLevel]uint64
+	}{{"rate_limited", s.RateLimited}, {"sampled", s.Sampled}} {
+		for i, name := range levelNames {
+			bw.WriteString(`logging_suppressed_total{level="`)
+			bw.WriteString(name)
+			bw.WriteString(`",reason="`)
+			bw.WriteString(reason.name)
+			bw.WriteString(`"} `)
+			bw.WriteString(strconv.FormatUint(reason.counts[LogLevel(i-1)], 10))
+			bw.WriteByte('\n')
+		}
+	}
+	bw.WriteString("# HELP logging_dropped_total Log entries discarded by full queues, spool caps and open circuit breakers.\n")
+	bw.WriteString("# TYPE logging_dropped_total counter\n")
+	bw.WriteString("logging_dropped_total ")
+	bw.WriteString(strconv.FormatUint(s.Dropped, 10))
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// MetricsHandler returns an http.Handler serving WriteMetrics, so
+// Prometheus can scrape the log volume of l, and dashboards alert on error
+// rate spikes, without a client library:
+//
+//	http.Handle("/metrics/logging", logger.MetricsHandler())
+//
+//	# TYPE logging_entries_total counter
+//	logging_entries_total{level="error",logger=""} 3
+//	logging_entries_total{level="error",logger="db"} 12
+//
+// To add the counts to an existing registry instead, export EntryCounts
+// from a collector of your own.
+func (l *Logger) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = l.WriteMetrics(w)
+	})
+}
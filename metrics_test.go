@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEntryCounts(t *testing.T) {
+	logger := NewLogger(WithOutput(io.Discard), LogLevelDebug)
+	db := logger.Named("db")
+	logger.Info("started")
+	logger.WithFields(Fields{"k": 1}).Error("failed")
+	db.Error("query failed")
+	db.WithFields(Fields{"table": "users"}).Error("query failed")
+	db.Named("pool").Debug("acquired")
+	db.withLevel(LogLevelDebug).Debug("request traced") // as by DebugMiddleware
+	logger.Named("idle")
+
+	want := map[string]uint64{
+		"/info": 1, "/error": 1,
+		"db/error":      2,
+		"db/debug":      1,
+		"db.pool/debug": 1,
+	}
+	counts := logger.EntryCounts()
+	if len(counts) != 4*len(levelNames) {
+		t.Fatalf("Expected counts for 4 loggers, got %v", counts)
+	}
+	for i, c := range counts {
+		key := c.Logger + "/" + c.Level.String()
+		if c.Count != want[key] {
+			t.Errorf("%s: got %d entries, want %d", key, c.Count, want[key])
+		}
+		if i > 0 && counts[i-1].Logger > c.Logger {
+			t.Errorf("Expected counts sorted by logger, got %q before %q", counts[i-1].Logger, c.Logger)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	logger := NewLogger(WithOutput(io.Discard), WithRateLimiter(NewRateLimiter(1, time.Hour)))
+	logger.Named(`we"ird\name`).Warning("careful")
+	logger.Warning("careful")
+	logger.Error("boom")
+	logger.Debug("filtered")
+
+	rec := httptest.NewRecorder()
+	logger.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE logging_entries_total counter",
+		`logging_entries_total{level="error",logger=""} 1`,
+		`logging_entries_total{level="debug",logger=""} 0`,
+		`logging_entries_total{level="warning",logger="we\"ird\\name"} 1`,
+		`logging_suppressed_total{level="warning",reason="rate_limited"} 1`,
+		`logging_suppressed_total{level="debug",reason="sampled"} 0`,
+		"logging_dropped_total 0",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected line %q in:\n%s", line, body)
+		}
+	}
+}
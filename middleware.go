@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTPMiddleware returns net/http middleware that stores a request-scoped
+// logger in the request context, retrieved with FromContext, and logs one
+// entry per request with its method, path, status, response size,
+// duration, remote IP and user agent. Requests answered with a 5xx status
+// are logged at Error level, 4xx at Warning and the rest at Info.
+//
+// The request-scoped logger carries the method and path, and the
+// X-Request-ID header if present. That header and the W3C trace context
+// headers are kept in the context too, for Transport to forward to
+// outbound requests. If an outer middleware such as
+// DebugMiddleware already stored a logger derived from l in the context,
+// it is used instead of l.
+func HTTPMiddleware(l *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			fields := Fields{"method": r.Method, "path": r.URL.Path}
+			if id := r.Header.Get("X-Request-ID"); id != "" {
+				fields["request_id"] = id
+			}
+			logger := l.scoped(r.Context()).WithFields(fields)
+			ctx := withCorrelation(NewContext(r.Context(), logger), r.Header)
+
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			entry := logger.WithFields(Fields{
+				"status":     status,
+				"bytes":      rec.bytes,
+				"duration":   time.Since(start),
+				"remote_ip":  remoteIP(r),
+				"user_agent": r.UserAgent(),
+			})
+			switch {
+			case status >= 500:
+				entry.Error("http request")
+			case status >= 400:
+				entry.Warning("http request")
+			default:
+				entry.Info("http request")
+			}
+		})
+	}
+}
+
+// remoteIP returns the address of the client connection, without the port.
+// Forwarding headers are not trusted.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseRecorder records the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	// Informational responses such as 103 Early Hints precede the final one.
+	if w.status == 0 && status >= 200 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps streaming handlers such as TailHandler working.
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack keeps WebSocket and other connection upgrades working. A
+// hijacked connection is recorded as 101 Switching Protocols unless the
+// handler wrote another status first.
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("logging: hijacking %T: %w", w.ResponseWriter, http.ErrNotSupported)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	h := HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not here"))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/users/42?x=1", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.Header.Set("X-Request-ID", "abc")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	dec := json.NewDecoder(buf)
+	var inner, access map[string]any
+	if err := dec.Decode(&inner); err != nil {
+		t.Fatalf("Failed to parse handler entry: %v", err)
+	}
+	if inner["path"] != "/users/42" || inner["request_id"] != "abc" {
+		t.Errorf("Expected request fields on the handler's logger, got %v", inner)
+	}
+	if err := dec.Decode(&access); err != nil {
+		t.Fatalf("Failed to parse request entry: %v", err)
+	}
+	want := map[string]any{
+		"level":      "warn",
+		"method":     "GET",
+		"status":     float64(404),
+		"bytes":      float64(8),
+		"remote_ip":  "203.0.113.7",
+		"user_agent": "curl/8.0",
+	}
+	for k, v := range want {
+		if access[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, access[k])
+		}
+	}
+	if _, ok := access["duration"]; !ok {
+		t.Error("Expected a duration field")
+	}
+}
+
+func TestHTTPMiddlewareUsesOuterLogger(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	mw := logger.DebugMiddleware(DebugHeaderOptions{Tokens: []string{"s3cret"}})
+	h := mw(HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Debug("traced")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultDebugHeader, "s3cret")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.NewDecoder(buf).Decode(&entry); err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+	if entry["message"] != "traced" || entry["method"] != "GET" {
+		t.Errorf("Expected the debug entry with request fields, got %v", entry)
+	}
+}
+
+func TestResponseRecorderFlushes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	var w http.ResponseWriter = &responseRecorder{ResponseWriter: rec}
+	w.(http.Flusher).Flush()
+	if !rec.Flushed {
+		t.Error("Flush should reach the underlying writer")
+	}
+}
+
+func TestResponseRecorderIgnoresInformational(t *testing.T) {
+	rec := &responseRecorder{ResponseWriter: httptest.NewRecorder()}
+	rec.WriteHeader(http.StatusEarlyHints)
+	rec.WriteHeader(http.StatusCreated)
+	if rec.status != http.StatusCreated {
+		t.Errorf("Expected the final status recorded, got %d", rec.status)
+	}
+}
+
+// hijackRecorder is a ResponseWriter that can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn)), nil
+}
+
+func TestResponseRecorderHijacks(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	logger, buf := testLogger(LogLevelInfo)
+	h := HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		conn.Close()
+	}))
+	h.ServeHTTP(&hijackRecorder{httptest.NewRecorder(), server}, httptest.NewRequest(http.MethodGet, "/ws", nil))
+
+	var entry map[string]any
+	if err := json.NewDecoder(buf).Decode(&entry); err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+	if entry["status"] != float64(http.StatusSwitchingProtocols) {
+		t.Errorf("Expected a hijacked request logged as 101, got %v", entry["status"])
+	}
+
+	var w http.ResponseWriter = &responseRecorder{ResponseWriter: httptest.NewRecorder()}
+	if _, _, err := w.(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported without a Hijacker, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// namedLevels tracks the levels of the named loggers of a logger tree.
+// A named logger without an explicit level of its own inherits the level of
+// its nearest ancestor that has one, or of the root logger.
+type namedLevels struct {
+	mu       sync.Mutex
+	root     *loggerState
+	explicit map[string]LogLevel // from SetLevelSpec and SetLogLevel
+	states   map[string]*loggerState
+	loggers  map[string]*Logger      // cached by Get
+	windows  map[string]*debugWindow // from EnableDebugFor
+}
+
+// resolve returns the effective level of name: that of the longest
+// explicit entry equal to name or to one of its dot-separated prefixes, or
+// the root level. n.mu must be held.
+func (n *namedLevels) resolve(name string) LogLevel {
+	for {
+		if level, ok := n.explicit[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return LogLevel(n.root.level.Load())
+		}
+		name = name[:i]
+	}
+}
+
+// update recomputes the level of every named logger. n.mu must be held.
+func (n *namedLevels) update() {
+	for name, state := range n.states {
+		state.level.Store(int32(n.resolve(name)))
+	}
+}
+
+// setLevel sets the level of the logger called name, or of the root logger
+// if name is empty, and of the descendants inheriting it.
+func (n *namedLevels) setLevel(name string, level LogLevel) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.setLevelLocked(name, level)
+}
+
+func (n *namedLevels) setLevelLocked(name string, level LogLevel) {
+	if name == "" {
+		n.root.level.Store(int32(level))
+	} else {
+		if n.explicit == nil {
+			n.explicit = make(map[string]LogLevel)
+		}
+		n.explicit[name] = level
+	}
+	n.update()
+}
+
+// snapshot returns the root level and the effective level of every named
+// logger, including names with an explicit level but no logger yet.
+func (n *namedLevels) snapshot() (root LogLevel, named map[string]LogLevel) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	named = make(map[string]LogLevel, len(n.states)+len(n.explicit))
+	for name := range n.explicit {
+		named[name] = n.resolve(name)
+	}
+	for name := range n.states {
+		named[name] = n.resolve(name)
+	}
+	return LogLevel(n.root.level.Load()), named
+}
+
+// Named returns a derived Logger for the subsystem name, with a "logger"
+// field holding its full dot-separated name. Naming a named logger nests
+// the names, so logger.Named("server").Named("http") is "server.http".
+//
+// All loggers with the same full name share one level. Until it is set with
+// SetLogLevel or SetLevelSpec, a named logger inherits the level of its
+// nearest named ancestor with an explicit level, or of the root logger, and
+// follows later changes to it.
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	n := &l.state.tree.names
+	n.mu.Lock()
+	state, ok := n.states[name]
+	if !ok {
+		state = newLoggerState(n.resolve(name), l.state.tree)
+		if n.states == nil {
+			n.states = make(map[string]*loggerState)
+		}
+		n.states[name] = state
+	}
+	n.mu.Unlock()
+
+	c := l.WithFields(Fields{"logger": name})
+	c.name = name
+	c.state = state
+	return c
+}
+
+// ParseLevelSpec parses a level spec such as "info,http=debug,db=warning".
+// A bare level applies to every logger not matched by a name and is
+// returned as def; ok reports whether the spec contains one. A name also
+// matches its dot-separated descendants, so "server=debug" covers
+// "server.http" unless it has an entry of its own.
+func ParseLevelSpec(spec string) (def LogLevel, ok bool, levels map[string]LogLevel, err error) {
+	levels = make(map[string]LogLevel)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, named := strings.Cut(part, "=")
+		level, err := parseLevel(value)
+		if !named {
+			level, err = parseLevel(name)
+		}
+		if err != nil {
+			return 0, false, nil, fmt.Errorf("logging: level spec %q: %w", spec, err)
+		}
+		if !named {
+			def, ok = level, true
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return 0, false, nil, fmt.Errorf("logging: level spec %q: %w", spec, errors.New("empty logger name"))
+		}
+		levels[name] = level
+	}
+	return def, ok, levels, nil
+}
+
+// SetLevelSpec applies a level spec such as "info,http=debug,db=warning"
+// (see ParseLevelSpec) to the root logger of l and to every logger named
+// from it, including those named later. The bare level, if any, becomes the
+// root level. The named levels replace those set earlier by SetLevelSpec or
+// SetLogLevel; named loggers matched by no entry inherit the root level.
+func (l *Logger) SetLevelSpec(spec string) error {
+	def, hasDef, levels, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	l.applyLevelSpec(def, hasDef, levels)
+	return nil
+}
+
+func (l *Logger) applyLevelSpec(def LogLevel, hasDef bool, levels map[string]LogLevel) {
+	n := &l.state.tree.names
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if hasDef {
+		n.root.level.Store(int32(def))
+	}
+	n.explicit = levels
+	n.update()
+}
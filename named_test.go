@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNamedLoggerField(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.Named("server").Named("http").Info("request")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry["logger"] != "server.http" {
+		t.Errorf("Expected logger 'server.http', got %v", entry["logger"])
+	}
+}
+
+func TestParseLevelSpec(t *testing.T) {
+	def, ok, levels, err := ParseLevelSpec("info, http=debug,db=warn")
+	if err != nil {
+		t.Fatalf("ParseLevelSpec failed: %v", err)
+	}
+	if !ok || def != LogLevelInfo {
+		t.Errorf("Expected default info, got %v (%v)", def, ok)
+	}
+	if levels["http"] != LogLevelDebug || levels["db"] != LogLevelWarning || len(levels) != 2 {
+		t.Errorf("Unexpected levels: %v", levels)
+	}
+
+	for _, bad := range []string{"loud", "http=loud", "=debug"} {
+		if _, _, _, err := ParseLevelSpec(bad); err == nil {
+			t.Errorf("ParseLevelSpec(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestSetLevelSpec(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	http := logger.Named("http")
+	db := logger.Named("db")
+	cache := logger.Named("cache")
+
+	if err := logger.SetLevelSpec("error,http=debug,db=warning"); err != nil {
+		t.Fatalf("SetLevelSpec failed: %v", err)
+	}
+	if logger.Level() != LogLevelError || cache.Level() != LogLevelError {
+		t.Errorf("Expected the bare level for unmatched loggers, got %v and %v", logger.Level(), cache.Level())
+	}
+	if http.Level() != LogLevelDebug || db.Level() != LogLevelWarning {
+		t.Errorf("Expected per-name levels, got http=%v db=%v", http.Level(), db.Level())
+	}
+	if later := logger.Named("http").Named("client"); later.Level() != LogLevelDebug {
+		t.Errorf("Loggers named later should inherit the spec of their prefix, got %v", later.Level())
+	}
+	if again := logger.Named("db"); again.Level() != LogLevelWarning {
+		t.Errorf("Loggers with the same name should share a level, got %v", again.Level())
+	}
+}
+
+func TestNamedLevelsShared(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	a := logger.Named("db")
+	b := logger.WithFields(Fields{"x": 1}).Named("db")
+
+	a.SetLogLevel(LogLevelError)
+	if b.Level() != LogLevelError {
+		t.Errorf("Loggers with the same name should share a level, got %v", b.Level())
+	}
+	if logger.Level() != LogLevelInfo {
+		t.Errorf("Setting a named logger's level should not change its parent, got %v", logger.Level())
+	}
+}
+
+func TestConfigLevelSpec(t *testing.T) {
+	logger, err := NewLoggerFromConfig(&Config{Level: "warning,db=debug"})
+	if err != nil {
+		t.Fatalf("NewLoggerFromConfig failed: %v", err)
+	}
+	if logger.Level() != LogLevelWarning || logger.Named("db").Level() != LogLevelDebug {
+		t.Errorf("Expected the spec to apply, got %v and %v", logger.Level(), logger.Named("db").Level())
+	}
+}
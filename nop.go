@@ -0,0 +1,26 @@
+package logging
+
+// NopLogger is a LoggerInterface that discards everything, for code that
+// requires a logger where none is wanted, such as tests of unrelated
+// behavior. Its zero value is ready to use.
+type NopLogger struct{}
+
+var _ LoggerInterface = NopLogger{}
+
+// Info discards the message.
+func (NopLogger) Info(format string, v ...any) {}
+
+// Warning discards the message.
+func (NopLogger) Warning(format string, v ...any) {}
+
+// Error discards the message.
+func (NopLogger) Error(format string, v ...any) {}
+
+// SetLogLevel does nothing.
+func (NopLogger) SetLogLevel(level LogLevel) {}
+
+// Flush does nothing and returns nil.
+func (NopLogger) Flush() error { return nil }
+
+// Close does nothing and returns nil.
+func (NopLogger) Close() error { return nil }
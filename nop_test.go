@@ -0,0 +1,7 @@
+package logging
+
+import "testing"
+
+func TestNopLogger(t *testing.T) {
+	TestLoggerInterface(t, func() LoggerInterface { return NopLogger{} })
+}
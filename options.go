@@ -0,0 +1,205 @@
+package logging
+
+import (
+	"io"
+	"os"
+
+	"github.com/phuslu/log"
+)
+
+// Format selects how entries are encoded.
+type Format int
+
+// Formats.
+const (
+	// FormatConsole writes human-readable, optionally colored lines.
+	FormatConsole Format = iota
+	// FormatJSON writes one JSON object per line.
+	FormatJSON
+)
+
+// Option configures a Logger created by NewLogger.
+//
+// A LogLevel is itself an Option setting the level, so NewLogger(level)
+// keeps working.
+type Option interface {
+	apply(o *options)
+}
+
+type optionFunc func(o *options)
+
+func (f optionFunc) apply(o *options) { f(o) }
+
+func (level LogLevel) apply(o *options) { o.level = level }
+
+type options struct {
+	level      LogLevel
+	levels     map[string]LogLevel
+	writer     log.Writer
+	output     io.Writer
+	format     Format
+	timeFormat string
+	caller     bool
+	color      *bool // nil selects colorFromEnv
+
+	errorOutput io.Writer
+	buildInfo   bool
+	stack       *LogLevel // nil disables stack traces
+	sampler     Sampler
+	limiter     *RateLimiter
+	redact      *redactor
+	scrub       *scrubber
+	allow       *allowlist
+	subjectKey  []byte
+	clock       Clock
+	signKey     []byte
+	sequence    bool
+}
+
+func defaultOptions() options {
+	return options{
+		level:      LogLevelInfo,
+		output:     os.Stdout,
+		format:     FormatConsole,
+		timeFormat: "2006-01-02 15:04:05",
+	}
+}
+
+// newWriter builds the writer described by o.
+func (o *options) newWriter() log.Writer {
+	w := o.baseWriter()
+	if o.signKey != nil {
+		w = NewSigningWriter(w, o.signKey)
+	}
+	return w
+}
+
+func (o *options) baseWriter() log.Writer {
+	if o.writer != nil {
+		return o.writer
+	}
+	w := o.encoder(o.output)
+	if o.errorOutput != nil {
+		return &splitWriter{out: w, err: o.encoder(o.errorOutput)}
+	}
+	return w
+}
+
+// encoder returns a writer encoding entries to out in the format of o.
+func (o *options) encoder(out io.Writer) log.Writer {
+	if o.format == FormatJSON {
+		return &log.IOWriter{Writer: out}
+	}
+	return &log.ConsoleWriter{
+		Writer:         out,
+		ColorOutput:    o.useColor(),
+		QuoteString:    true,
+		EndWithMessage: true,
+	}
+}
+
+// splitWriter sends entries at Error level and above to err and all others
+// to out.
+type splitWriter struct {
+	out, err log.Writer
+}
+
+func (w *splitWriter) WriteEntry(e *log.Entry) (int, error) {
+	if e.Level >= log.ErrorLevel {
+		return w.err.WriteEntry(e)
+	}
+	return w.out.WriteEntry(e)
+}
+
+func (w *splitWriter) Close() error {
+	err := closeWriter(w.out)
+	if cerr := closeWriter(w.err); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WithLevel sets the initial log level. Defaults to LogLevelInfo.
+func WithLevel(level LogLevel) Option {
+	return level
+}
+
+// WithWriter sets the destination of entries, overriding WithOutput,
+// WithFormat and WithColor.
+func WithWriter(w log.Writer) Option {
+	return optionFunc(func(o *options) { o.writer = w })
+}
+
+// WithOutput sets where encoded entries are written. Defaults to os.Stdout.
+func WithOutput(w io.Writer) Option {
+	return optionFunc(func(o *options) { o.output = w })
+}
+
+// WithFormat sets the encoding of entries. Defaults to FormatConsole.
+func WithFormat(f Format) Option {
+	return optionFunc(func(o *options) { o.format = f })
+}
+
+// WithTimeFormat sets the layout of the time field, as accepted by
+// time.Format. An empty layout selects RFC 3339 with milliseconds.
+// Defaults to "2006-01-02 15:04:05".
+func WithTimeFormat(layout string) Option {
+	return optionFunc(func(o *options) { o.timeFormat = layout })
+}
+
+// WithCaller adds a caller field holding the file and line of the logging
+// call. Capturing the caller costs a stack walk per entry.
+func WithCaller(enabled bool) Option {
+	return optionFunc(func(o *options) { o.caller = enabled })
+}
+
+// WithColor enables or disables colors in console output, overriding the
+// environment. By default colors follow colorFromEnv.
+func WithColor(enabled bool) Option {
+	return optionFunc(func(o *options) { o.color = &enabled })
+}
+
+// useColor reports whether console output should be colored.
+func (o *options) useColor() bool {
+	if o.color != nil {
+		return *o.color
+	}
+	return colorFromEnv(os.Getenv)
+}
+
+// colorFromEnv applies the common terminal color conventions:
+// FORCE_COLOR or CLICOLOR_FORCE set to anything but "0" enables colors;
+// otherwise NO_COLOR set to anything, CLICOLOR=0 or TERM=dumb disables
+// them. Colors are enabled when none of these is set.
+func colorFromEnv(getenv func(string) string) bool {
+	for _, key := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+		if v := getenv(key); v != "" && v != "0" {
+			return true
+		}
+	}
+	switch {
+	case getenv("NO_COLOR") != "":
+		return false
+	case getenv("CLICOLOR") == "0":
+		return false
+	case getenv("TERM") == "dumb":
+		return false
+	}
+	return true
+}
+
+// WithErrorOutput sends entries at Error level and above to w instead of
+// the output set by WithOutput.
+func WithErrorOutput(w io.Writer) Option {
+	return optionFunc(func(o *options) { o.errorOutput = w })
+}
+
+// WithSampler installs s as by Logger.SetSampler.
+func WithSampler(s Sampler) Option {
+	return optionFunc(func(o *options) { o.sampler = s })
+}
+
+// WithRateLimiter installs r as by Logger.SetRateLimiter.
+func WithRateLimiter(r *RateLimiter) Option {
+	return optionFunc(func(o *options) { o.limiter = r })
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+func TestNewLoggerLevelShim(t *testing.T) {
+	logger := NewLogger(LogLevelWarning)
+	if logger.Level() != LogLevelWarning {
+		t.Errorf("Expected level %v, got %v", LogLevelWarning, logger.Level())
+	}
+	if NewLogger().Level() != LogLevelInfo {
+		t.Error("Expected LogLevelInfo by default")
+	}
+}
+
+func TestNewLoggerOptions(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(
+		WithLevel(LogLevelDebug),
+		WithOutput(&buf),
+		WithFormat(FormatJSON),
+		WithTimeFormat("2006"),
+		WithCaller(true),
+	)
+
+	logger.Debug("configured")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected JSON output: %v (%q)", err, buf.Bytes())
+	}
+	if entry["message"] != "configured" || entry["level"] != "debug" {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+	if time, _ := entry["time"].(string); len(time) != 4 {
+		t.Errorf("Expected the custom time format, got %v", entry["time"])
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "options_test.go:") {
+		t.Errorf("Expected the caller of Debug, got %v", entry["caller"])
+	}
+}
+
+// clearColorEnv unsets the variables read by colorFromEnv for the test.
+func clearColorEnv(t *testing.T) {
+	for _, key := range []string{"FORCE_COLOR", "CLICOLOR_FORCE", "NO_COLOR", "CLICOLOR", "TERM"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewLoggerConsoleColor(t *testing.T) {
+	clearColorEnv(t)
+	var plain, colored bytes.Buffer
+	NewLogger(WithOutput(&plain), WithColor(false)).Info("hello")
+	NewLogger(WithOutput(&colored)).Info("hello")
+
+	if bytes.Contains(plain.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected no color codes, got %q", plain.Bytes())
+	}
+	if !bytes.Contains(colored.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected color codes by default, got %q", colored.Bytes())
+	}
+}
+
+func TestNewLoggerWithWriter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithWriter(&log.IOWriter{Writer: &buf}), WithFormat(FormatConsole))
+
+	logger.Info("raw")
+	if _, err := parseLogEntry(&buf); err != nil {
+		t.Errorf("WithWriter should take precedence over WithFormat: %v", err)
+	}
+}
+
+func TestColorFromEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{nil, true},
+		{map[string]string{"NO_COLOR": "1"}, false},
+		{map[string]string{"CLICOLOR": "0"}, false},
+		{map[string]string{"TERM": "dumb"}, false},
+		{map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, true},
+		{map[string]string{"TERM": "dumb", "CLICOLOR_FORCE": "1"}, true},
+		{map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "0"}, false},
+	}
+	for _, tt := range tests {
+		if got := colorFromEnv(fakeEnv(tt.env)); got != tt.want {
+			t.Errorf("colorFromEnv(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestWithColorOverridesEnv(t *testing.T) {
+	clearColorEnv(t)
+	t.Setenv("NO_COLOR", "1")
+	var env, forced bytes.Buffer
+	NewLogger(WithOutput(&env)).Info("hello")
+	NewLogger(WithOutput(&forced), WithColor(true)).Info("hello")
+
+	if bytes.Contains(env.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected NO_COLOR to disable colors, got %q", env.Bytes())
+	}
+	if !bytes.Contains(forced.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected WithColor to override NO_COLOR, got %q", forced.Bytes())
+	}
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoveryOptions configures RecoveryMiddleware.
+type RecoveryOptions struct {
+	// Repanic panics again with the original value once the panic has
+	// been logged and answered, for outer handlers or a crash reporter.
+	Repanic bool
+}
+
+// LogPanic logs a recovered panic value r at Error level with the full
+// stack trace of the current goroutine, using the logger carried by ctx if
+// it was derived from l, so request fields are included. If r is an error
+// it is recorded in the error field. It is meant to be called from a
+// deferred recover, for example in a gRPC interceptor:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			l.LogPanic(ctx, r, logging.Fields{"rpc_method": info.FullMethod})
+//			err = status.Error(codes.Internal, "internal error")
+//		}
+//	}()
+func (l *Logger) LogPanic(ctx context.Context, r any, fields Fields) {
+	f := make(Fields, len(fields)+3)
+	for k, v := range fields {
+		f[k] = v
+	}
+	f["panic"] = fmt.Sprint(r)
+	f["stack"] = string(debug.Stack())
+	if err, ok := r.(error); ok {
+		f["error"] = err
+	}
+	l.scoped(ctx).WithFields(f).Error("recovered from panic")
+}
+
+// scoped returns the logger carried by ctx if it was derived from l, and
+// l otherwise.
+func (l *Logger) scoped(ctx context.Context) *Logger {
+	if c, ok := ctx.Value(contextKey{}).(*Logger); ok && c.state.tree == l.state.tree {
+		return c
+	}
+	return l
+}
+
+// RecoveryMiddleware returns net/http middleware that recovers panics in
+// the handler, logs them with LogPanic together with the request method,
+// path and remote IP, and answers 500 Internal Server Error unless the
+// response was already started. http.ErrAbortHandler is passed through
+// unlogged, as net/http expects. Place it inside HTTPMiddleware so the
+// access entry records the 500.
+func RecoveryMiddleware(l *Logger, opts RecoveryOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				l.LogPanic(r.Context(), v, Fields{
+					"method":    r.Method,
+					"path":      r.URL.Path,
+					"remote_ip": remoteIP(r),
+				})
+				if rec.status == 0 {
+					http.Error(rec, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+				if opts.Repanic {
+					panic(v)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	h := HTTPMiddleware(logger)(RecoveryMiddleware(logger, RecoveryOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(errors.New("nil map"))
+	})))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", rec.Code)
+	}
+	dec := json.NewDecoder(buf)
+	var panicked, access map[string]any
+	if err := dec.Decode(&panicked); err != nil {
+		t.Fatalf("Failed to parse panic entry: %v", err)
+	}
+	if panicked["level"] != "error" || panicked["error"] != "nil map" || panicked["path"] != "/orders" {
+		t.Errorf("Unexpected panic entry: %v", panicked)
+	}
+	if stack, _ := panicked["stack"].(string); !strings.Contains(stack, "panic_test.go") {
+		t.Errorf("Expected the stack of the panic, got %q", stack)
+	}
+	if err := dec.Decode(&access); err != nil {
+		t.Fatalf("Failed to parse access entry: %v", err)
+	}
+	if access["status"] != float64(500) {
+		t.Errorf("Expected the access entry to record 500, got %v", access["status"])
+	}
+}
+
+func TestRecoveryMiddlewareRepanics(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	h := RecoveryMiddleware(logger, RecoveryOptions{Repanic: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("Expected the original panic, got %v", r)
+		}
+		if !strings.Contains(buf.String(), `"panic":"boom"`) {
+			t.Errorf("Expected the panic to be logged first, got %q", buf.Bytes())
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestRecoveryMiddlewarePassesAbort(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	h := RecoveryMiddleware(logger, RecoveryOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("Expected ErrAbortHandler, got %v", r)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("ErrAbortHandler should not be logged, got %q", buf.Bytes())
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
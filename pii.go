@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// PIIDetector finds one kind of personal data in text. Matches are masked
+// as "[Name]", for example "[email]".
+type PIIDetector struct {
+	// Name labels the mask.
+	Name string
+
+	// Pattern finds candidate matches.
+	Pattern *regexp.Regexp
+
+	// Validate, if set, confirms a candidate, such as a checksum test; a
+	// candidate it rejects is left as is.
+	Validate func(match string) bool
+}
+
+// NewPIIDetector returns a detector masking matches of pattern as "[name]".
+// It panics if pattern does not compile, like regexp.MustCompile.
+func NewPIIDetector(name, pattern string) PIIDetector {
+	return PIIDetector{Name: name, Pattern: regexp.MustCompile(pattern)}
+}
+
+// EmailDetector detects email addresses.
+func EmailDetector() PIIDetector {
+	return NewPIIDetector("email", `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+}
+
+// CardDetector detects payment card numbers of 13 to 19 digits, optionally
+// grouped by spaces or dashes, that pass the Luhn check, so order numbers
+// and timestamps are left alone.
+func CardDetector() PIIDetector {
+	d := NewPIIDetector("card", `\b\d(?:[ -]?\d){12,18}\b`)
+	d.Validate = luhn
+	return d
+}
+
+// SSNDetector detects US Social Security numbers written as 123-45-6789,
+// excluding the area numbers never issued.
+func SSNDetector() PIIDetector {
+	d := NewPIIDetector("ssn", `\b\d{3}-\d{2}-\d{4}\b`)
+	d.Validate = func(m string) bool {
+		area := m[:3]
+		return area != "000" && area != "666" && area[0] != '9' && m[4:6] != "00" && m[7:] != "0000"
+	}
+	return d
+}
+
+// DefaultPIIDetectors returns the built-in detectors: email addresses,
+// payment card numbers and US Social Security numbers.
+func DefaultPIIDetectors() []PIIDetector {
+	return []PIIDetector{EmailDetector(), CardDetector(), SSNDetector()}
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
+
+// scrubber masks the matches of its detectors.
+type scrubber struct {
+	detectors []PIIDetector
+}
+
+func (s *scrubber) scrub(text string) string {
+	for _, d := range s.detectors {
+		mask := "[" + d.Name + "]"
+		text = d.Pattern.ReplaceAllStringFunc(text, func(m string) string {
+			if d.Validate != nil && !d.Validate(m) {
+				return m
+			}
+			return mask
+		})
+	}
+	return text
+}
+
+// message formats and scrubs a message.
+func (s *scrubber) message(format string, v []any) string {
+	if len(v) > 0 {
+		format = fmt.Sprintf(format, v...)
+	}
+	return s.scrub(format)
+}
+
+// scrubFields masks matches in the string values of fields, which it owns,
+// descending into nested maps without modifying them.
+func (s *scrubber) scrubFields(fields Fields) {
+	for k, v := range fields {
+		switch v := v.(type) {
+		case string:
+			fields[k] = s.scrub(v)
+		case Fields:
+			fields[k] = s.scrubMap(v)
+		case map[string]any:
+			fields[k] = map[string]any(s.scrubMap(v))
+		case map[string]string:
+			c := make(map[string]string, len(v))
+			for mk, mv := range v {
+				c[mk] = s.scrub(mv)
+			}
+			fields[k] = c
+		}
+	}
+}
+
+func (s *scrubber) scrubMap(m map[string]any) Fields {
+	c := make(Fields, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	s.scrubFields(c)
+	return c
+}
+
+// scrubbed returns the message and arguments to log: unchanged, or the
+// scrubbed message without arguments when PII scrubbing is enabled.
+func (l *Logger) scrubbed(format string, v []any) (string, []any) {
+	if s := l.state.tree.scrub.Load(); s != nil {
+		return s.message(format, v), nil
+	}
+	return format, v
+}
+
+// WithPIIScrubbing masks personal data as by Logger.SetPIIScrubbing.
+func WithPIIScrubbing(detectors ...PIIDetector) Option {
+	return optionFunc(func(o *options) { o.scrub = newScrubber(detectors) })
+}
+
+// SetPIIScrubbing masks the matches of detectors in messages and string
+// fields before they are encoded, for l and every logger derived or named
+// from the same root. Without detectors, DefaultPIIDetectors apply; add
+// your own with NewPIIDetector:
+//
+//	logger.SetPIIScrubbing(append(logging.DefaultPIIDetectors(),
+//		logging.NewPIIDetector("iban", `\b[A-Z]{2}\d{2}[A-Z0-9]{11,30}\b`))...)
+//
+// Scrubbing formats every message and runs each detector over it, which
+// costs far more than logging itself. Like SetRedaction, it only affects
+// fields attached after the call.
+func (l *Logger) SetPIIScrubbing(detectors ...PIIDetector) {
+	l.state.tree.scrub.Store(newScrubber(detectors))
+}
+
+func newScrubber(detectors []PIIDetector) *scrubber {
+	if len(detectors) == 0 {
+		detectors = DefaultPIIDetectors()
+	}
+	return &scrubber{detectors: detectors}
+}
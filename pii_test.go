@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPIIScrubbingMessagesAndFields(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetPIIScrubbing()
+	logger.WithFields(Fields{
+		"contact": "ada@example.com",
+		"order":   "4111111111111112",
+		"profile": map[string]any{"ssn": "123-45-6789"},
+		"count":   3,
+	}).Info("charged card %s for %s", "4111 1111 1111 1111", "ada@example.com")
+
+	e := decodeEntries(t, buf.String())[0]
+	if e["message"] != "charged card [card] for [email]" {
+		t.Errorf("Expected PII masked in the message, got %q", e["message"])
+	}
+	if e["contact"] != "[email]" || e["profile"].(map[string]any)["ssn"] != "[ssn]" {
+		t.Errorf("Expected PII masked in fields, got %v", e)
+	}
+	if e["order"] != "4111111111111112" {
+		t.Errorf("Expected a number failing the Luhn check to be kept, got %v", e["order"])
+	}
+}
+
+func TestPIIScrubbingCustomDetector(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetPIIScrubbing(NewPIIDetector("phone", `\+\d{6,14}`))
+	logger.Warning("call +4915112345678 or mail ada@example.com")
+
+	msg := decodeEntries(t, buf.String())[0]["message"].(string)
+	if !strings.Contains(msg, "[phone]") || !strings.Contains(msg, "ada@example.com") {
+		t.Errorf("Expected only the custom detector applied, got %q", msg)
+	}
+}
+
+func TestSSNDetectorSkipsInvalidAreas(t *testing.T) {
+	s := newScrubber([]PIIDetector{SSNDetector()})
+	if got := s.scrub("ids 000-12-3456 666-12-3456 078-05-1120"); got != "ids 000-12-3456 666-12-3456 [ssn]" {
+		t.Errorf("Unexpected scrubbing: %q", got)
+	}
+}
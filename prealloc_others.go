@@ -0,0 +1,22 @@
+//go:build !linux
+
+package logging
+
+import (
+	"errors"
+	"os"
+)
+
+var errMmapUnsupported = errors.New("logging: mmap is not supported on this platform")
+
+func mmapFile(*os.File, int64) ([]byte, error) {
+	return nil, errMmapUnsupported
+}
+
+func munmapFile([]byte) error {
+	return nil
+}
+
+func msyncFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreallocFileWriter(t *testing.T) {
+	for _, mmap := range []bool{false, true} {
+		name := "pwrite"
+		if mmap {
+			name = "mmap"
+		}
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			if err := os.WriteFile(path, []byte("existing\n"), 0644); err != nil {
+				t.Fatalf("Failed to seed log file: %v", err)
+			}
+			w := &PreallocFileWriter{Filename: path, Size: 256, Mmap: mmap}
+			logger, _ := testLogger(LogLevelInfo)
+			logger.SetWriter(w)
+
+			for i := 0; i < 10; i++ {
+				logger.Info("entry %d", i)
+			}
+			if err := logger.Flush(); err != nil {
+				t.Fatalf("Flush failed: %v", err)
+			}
+			if info, err := os.Stat(path); err != nil || info.Size() <= w.off {
+				t.Errorf("Expected the file to be preallocated beyond the written data")
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read log file: %v", err)
+			}
+			if !bytes.HasPrefix(data, []byte("existing\n")) {
+				t.Error("Existing content should be preserved")
+			}
+			if got := bytes.Count(data, []byte("\n")); got != 11 {
+				t.Errorf("Expected 11 lines, got %d", got)
+			}
+			if bytes.IndexByte(data, 0) >= 0 {
+				t.Error("Close should truncate the zero padding")
+			}
+			if _, err := w.Write([]byte("late")); err != ErrWriterClosed {
+				t.Errorf("Expected ErrWriterClosed after Close, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPreallocFileWriterAfterCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	// A writer that crashed leaves its entries followed by the padding.
+	crashed := append([]byte("before crash\n"), make([]byte, 100<<10)...)
+	if err := os.WriteFile(path, crashed, 0644); err != nil {
+		t.Fatalf("Failed to seed log file: %v", err)
+	}
+	w := &PreallocFileWriter{Filename: path, Size: 256, Mmap: true}
+	if _, err := w.Write([]byte("after restart\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if want := "before crash\nafter restart\n"; string(data) != want {
+		t.Errorf("Expected %q, got %d bytes: %q", want, len(data), bytes.TrimRight(data[:min(len(data), 64)], "\x00"))
+	}
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"os"
+	"time"
+)
+
+// NewDevelopment returns a Logger suited to local development: colored
+// console output on stdout at LogLevelDebug, with the caller of each entry.
+// opts are applied after the preset and override it.
+func NewDevelopment(opts ...Option) *Logger {
+	o := defaultOptions()
+	o.level = LogLevelDebug
+	o.caller = true
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return newLogger(&o)
+}
+
+// NewProduction returns a Logger suited to production: JSON at
+// LogLevelInfo with RFC 3339 timestamps, Error entries on stderr and all
+// others on stdout, and Debug and Info entries sampled: the first 100 of
+// each level per second are kept, then one in every 100. Warning and Error
+// entries are never sampled. opts are applied after the preset and override
+// it.
+func NewProduction(opts ...Option) *Logger {
+	o := defaultOptions()
+	o.format = FormatJSON
+	o.timeFormat = ""
+	o.color = new(bool)
+	o.errorOutput = os.Stderr
+	burst := Burst(100, 100, time.Second)
+	o.sampler = LevelSampler{LogLevelDebug: burst, LogLevelInfo: burst}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return newLogger(&o)
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewDevelopment(t *testing.T) {
+	clearColorEnv(t)
+	var buf bytes.Buffer
+	logger := NewDevelopment(WithOutput(&buf))
+
+	logger.Debug("details")
+	out := buf.String()
+	if !strings.Contains(out, "details") {
+		t.Fatalf("Expected debug entries, got %q", out)
+	}
+	if !strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected colored output, got %q", out)
+	}
+	if !strings.Contains(out, "presets_test.go:") {
+		t.Errorf("Expected caller information, got %q", out)
+	}
+}
+
+func TestNewProduction(t *testing.T) {
+	var out, errOut bytes.Buffer
+	logger := NewProduction(WithOutput(&out), WithErrorOutput(&errOut))
+
+	logger.Debug("hidden")
+	logger.Info("started")
+	logger.Error("failed")
+
+	var entry map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON entry on stdout: %v (%q)", err, out.Bytes())
+	}
+	if entry["message"] != "started" {
+		t.Errorf("Unexpected stdout entry: %v", entry)
+	}
+	entry = nil
+	if err := json.Unmarshal(errOut.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON entry on stderr: %v (%q)", err, errOut.Bytes())
+	}
+	if entry["message"] != "failed" {
+		t.Errorf("Unexpected stderr entry: %v", entry)
+	}
+}
+
+func TestNewProductionSampling(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewProduction(WithOutput(&out))
+
+	for i := 0; i < 300; i++ {
+		logger.Info("hot loop %d", i)
+		logger.Warning("warning %d", i)
+	}
+	if n := bytes.Count(out.Bytes(), []byte("hot loop")); n < 102 || n > 200 {
+		t.Errorf("Expected 100 info entries then one in 100, got %d", n)
+	}
+	if n := bytes.Count(out.Bytes(), []byte("warning")); n != 300 {
+		t.Errorf("Expected warnings never sampled, got %d", n)
+	}
+	if got, want := logger.Config().Sampler, "debug: first 100 per 1s, then 1 in 100, info: first 100 per 1s, then 1 in 100"; got != want {
+		t.Errorf("Config().Sampler = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Profile bundles a level with caller capture, sampling and stack trace
+// settings, so command-line tools can map -q, -v and -vv to one option.
+// A Profile is an Option; options given after it override its settings.
+type Profile int
+
+// Profiles, from least to most verbose.
+const (
+	// ProfileQuiet logs errors only.
+	ProfileQuiet Profile = iota - 1
+	// ProfileNormal logs at Info level and caps each message template at
+	// 100 entries per second.
+	ProfileNormal
+	// ProfileVerbose logs at Debug level with the caller of each entry and
+	// a stack trace on errors.
+	ProfileVerbose
+	// ProfileDebug is ProfileVerbose with stack traces on warnings too.
+	ProfileDebug
+)
+
+// EnvProfile is the environment variable read by ConfigFromEnv to select a
+// Profile.
+const EnvProfile = "LOG_PROFILE"
+
+// ProfileFromVerbosity maps a verbosity count to a Profile: negative for
+// -q, 0 by default, 1 for -v and 2 or more for -vv.
+func ProfileFromVerbosity(v int) Profile {
+	switch {
+	case v < 0:
+		return ProfileQuiet
+	case v == 0:
+		return ProfileNormal
+	case v == 1:
+		return ProfileVerbose
+	default:
+		return ProfileDebug
+	}
+}
+
+// WithProfile applies p. It is equivalent to passing p itself.
+func WithProfile(p Profile) Option {
+	return p
+}
+
+func (p Profile) apply(o *options) {
+	o.caller = false
+	o.sampler = nil
+	o.limiter = nil
+	o.stack = nil
+	switch p {
+	case ProfileQuiet:
+		o.level = LogLevelError
+	case ProfileNormal:
+		o.level = LogLevelInfo
+		o.limiter = NewRateLimiter(100, time.Second)
+	case ProfileVerbose:
+		o.level = LogLevelDebug
+		o.caller = true
+		o.stack = stackFrom(LogLevelError)
+	default:
+		o.level = LogLevelDebug
+		o.caller = true
+		o.stack = stackFrom(LogLevelWarning)
+	}
+}
+
+// String returns the name of the profile.
+func (p Profile) String() string {
+	switch p {
+	case ProfileQuiet:
+		return "quiet"
+	case ProfileNormal:
+		return "normal"
+	case ProfileVerbose:
+		return "verbose"
+	default:
+		return "debug"
+	}
+}
+
+// ParseProfile returns the profile named s: "quiet", "normal", "verbose"
+// or "debug".
+func ParseProfile(s string) (Profile, error) {
+	p, err := parseProfile(s)
+	if err != nil {
+		return p, fmt.Errorf("logging: %w", err)
+	}
+	return p, nil
+}
+
+func parseProfile(s string) (Profile, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "quiet":
+		return ProfileQuiet, nil
+	case "normal":
+		return ProfileNormal, nil
+	case "verbose":
+		return ProfileVerbose, nil
+	case "debug":
+		return ProfileDebug, nil
+	}
+	return ProfileNormal, fmt.Errorf("unknown profile %q (valid: quiet, normal, verbose, debug)", s)
+}
+
+// Set implements flag.Value.
+func (p *Profile) Set(s string) error {
+	profile, err := ParseProfile(s)
+	if err != nil {
+		return err
+	}
+	*p = profile
+	return nil
+}
+
+// WithStackTrace adds a stack field holding the goroutine's stack trace to
+// entries at level and above.
+func WithStackTrace(level LogLevel) Option {
+	return optionFunc(func(o *options) { o.stack = stackFrom(level) })
+}
+
+func stackFrom(level LogLevel) *LogLevel {
+	return &level
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestProfileFromVerbosity(t *testing.T) {
+	tests := map[int]Profile{-1: ProfileQuiet, 0: ProfileNormal, 1: ProfileVerbose, 2: ProfileDebug, 5: ProfileDebug}
+	for v, want := range tests {
+		if got := ProfileFromVerbosity(v); got != want {
+			t.Errorf("ProfileFromVerbosity(%d) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	tests := []struct {
+		profile Profile
+		level   LogLevel
+		caller  bool
+		limited bool
+	}{
+		{ProfileQuiet, LogLevelError, false, false},
+		{ProfileNormal, LogLevelInfo, false, true},
+		{ProfileVerbose, LogLevelDebug, true, false},
+		{ProfileDebug, LogLevelDebug, true, false},
+	}
+	for _, tt := range tests {
+		l := NewLogger(tt.profile)
+		if l.Level() != tt.level || (l.state.tree.caller.Load()) != tt.caller || (l.limiter != nil) != tt.limited {
+			t.Errorf("%v: got level=%v caller=%v limited=%v", tt.profile, l.Level(), l.state.tree.caller.Load(), l.limiter != nil)
+		}
+	}
+}
+
+func TestProfileStackTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), ProfileVerbose)
+
+	logger.Warning("no stack")
+	logger.Error("with stack")
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %q", buf.Bytes())
+	}
+	var warning, failure map[string]any
+	if err := json.Unmarshal(lines[0], &warning); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if err := json.Unmarshal(lines[1], &failure); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if _, ok := warning["stack"]; ok {
+		t.Error("ProfileVerbose should not add stacks to warnings")
+	}
+	if stack, _ := failure["stack"].(string); !strings.Contains(stack, "goroutine") {
+		t.Errorf("Expected a stack trace on errors, got %v", failure["stack"])
+	}
+}
+
+func TestProfileOverriddenByLaterOptions(t *testing.T) {
+	l := NewLogger(ProfileQuiet, WithLevel(LogLevelWarning))
+	if l.Level() != LogLevelWarning {
+		t.Errorf("Options after a profile should override it, got %v", l.Level())
+	}
+}
+
+func TestProfileFlagAndEnv(t *testing.T) {
+	p := ProfileNormal
+	fs := newFlagSet()
+	fs.Var(&p, "log-profile", "")
+	if err := fs.Parse([]string{"-log-profile", "verbose"}); err != nil || p != ProfileVerbose {
+		t.Errorf("Expected verbose, got %v (%v)", p, err)
+	}
+
+	t.Setenv(EnvProfile, "quiet")
+	logger, err := NewLoggerFromEnv()
+	if err != nil {
+		t.Fatalf("NewLoggerFromEnv failed: %v", err)
+	}
+	if logger.Level() != LogLevelError {
+		t.Errorf("Expected LOG_PROFILE=quiet to select Error level, got %v", logger.Level())
+	}
+
+	t.Setenv(EnvProfile, "chatty")
+	if _, err := NewLoggerFromEnv(); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+}
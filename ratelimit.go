@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRateLimitKeys bounds the number of tracked keys before expired
+// windows are swept.
+const maxRateLimitKeys = 4096
+
+// RateLimiter caps the number of entries emitted per key within a fixed
+// time window. When a window in which entries were suppressed ends, the
+// next emitted entry for that key reports the suppressed count.
+type RateLimiter struct {
+	limit    int
+	interval time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter returns a RateLimiter allowing limit entries per key every
+// interval. A non-positive interval defaults to one second.
+func NewRateLimiter(limit int, interval time.Duration) *RateLimiter {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &RateLimiter{
+		limit:    limit,
+		interval: interval,
+		windows:  make(map[string]*rateWindow),
+	}
+}
+
+// Allow reports whether an entry for key may be emitted. If a previous
+// window for key suppressed entries, suppressed is their number.
+func (r *RateLimiter) Allow(key string) (ok bool, suppressed int) {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, found := r.windows[key]
+	if !found {
+		if len(r.windows) >= maxRateLimitKeys {
+			r.sweep(now)
+		}
+		w = &rateWindow{start: now}
+		r.windows[key] = w
+	} else if now.Sub(w.start) >= r.interval {
+		if w.count > r.limit {
+			suppressed = w.count - r.limit
+		}
+		w.start = now
+		w.count = 0
+	}
+	w.count++
+	return w.count <= r.limit, suppressed
+}
+
+// sweep removes windows that have expired without suppressing anything.
+func (r *RateLimiter) sweep(now time.Time) {
+	for key, w := range r.windows {
+		if now.Sub(w.start) >= r.interval && w.count <= r.limit {
+			delete(r.windows, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterByTemplate(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetRateLimiter(NewRateLimiter(3, 20*time.Millisecond))
+
+	for i := 0; i < 10; i++ {
+		logger.Warning("retrying request %d", i)
+	}
+	logger.Warning("other message")
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 4 {
+		t.Errorf("Expected 3 limited entries plus 1 other, got %d", got)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	buf.Reset()
+	logger.Warning("retrying request %d", 10)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if entry["suppressed"] != float64(7) {
+		t.Errorf("Expected suppressed count 7, got %v", entry["suppressed"])
+	}
+}
+
+func TestRateLimiterExplicitKey(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetRateLimiter(NewRateLimiter(1, time.Hour))
+	limited := logger.WithRateLimitKey("cache-miss")
+
+	limited.Info("miss for %s", "a")
+	limited.Info("cache miss in a different template")
+	logger.Info("cache miss in a different template")
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 2 {
+		t.Errorf("Expected entries sharing an explicit key to be limited together, got %d entries", got)
+	}
+}
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/phuslu/log"
+)
+
+// Entries is a list of recorded entries with queries for tests.
+type Entries []Entry
+
+// FilterLevel returns the entries at level.
+func (es Entries) FilterLevel(level LogLevel) Entries {
+	return es.filter(func(e Entry) bool { return e.Level == level })
+}
+
+// Contains reports whether the message of any entry contains substr.
+func (es Entries) Contains(substr string) bool {
+	for _, e := range es {
+		if strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldEquals returns the entries whose field key deeply equals value.
+func (es Entries) FieldEquals(key string, value any) Entries {
+	return es.filter(func(e Entry) bool {
+		v, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+func (es Entries) filter(keep func(Entry) bool) Entries {
+	var out Entries
+	for _, e := range es {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Recorder is a LoggerInterface for tests that captures entries as
+// structured values, so tests can query them instead of parsing JSON from a
+// buffer:
+//
+//	rec := logging.NewRecorder()
+//	svc := NewService(rec.Logger())
+//	svc.Run()
+//	if !rec.FilterLevel(logging.LogLevelError).Contains("timeout") {
+//		t.Error("expected a timeout error")
+//	}
+//
+// Every entry that passes the level, sampling and rate limiting checks of
+// Logger, or of the loggers derived or named from it, is recorded with its
+// formatted message and fields. Entries are kept after Close.
+type Recorder struct {
+	logger  *Logger
+	cancel  func()
+	mu      sync.Mutex
+	entries Entries
+}
+
+// NewRecorder returns a Recorder at LogLevelDebug whose logger is
+// configured by opts and writes nowhere unless opts set a destination.
+func NewRecorder(opts ...Option) *Recorder {
+	all := append([]Option{LogLevelDebug, WithWriter(log.IOWriter{Writer: io.Discard})}, opts...)
+	r := &Recorder{logger: NewLogger(all...)}
+	r.cancel = r.logger.record(func(e Entry) {
+		r.mu.Lock()
+		r.entries = append(r.entries, e)
+		r.mu.Unlock()
+	})
+	return r
+}
+
+// Logger returns the logger whose entries are recorded, to pass to code
+// that needs a *Logger, for example to derive loggers with WithFields.
+func (r *Recorder) Logger() *Logger {
+	return r.logger
+}
+
+// Info logs and records an info message.
+func (r *Recorder) Info(format string, v ...any) {
+	r.logger.Info(format, v...)
+}
+
+// Warning logs and records a warning message.
+func (r *Recorder) Warning(format string, v ...any) {
+	r.logger.Warning(format, v...)
+}
+
+// Error logs and records an error message.
+func (r *Recorder) Error(format string, v ...any) {
+	r.logger.Error(format, v...)
+}
+
+// SetLogLevel sets the level of the recorded logger.
+func (r *Recorder) SetLogLevel(level LogLevel) {
+	r.logger.SetLogLevel(level)
+}
+
+// Flush flushes the recorded logger.
+func (r *Recorder) Flush() error {
+	return r.logger.Flush()
+}
+
+// Close closes the recorded logger; the entries recorded so far are kept.
+func (r *Recorder) Close() error {
+	r.cancel()
+	return r.logger.Close()
+}
+
+// Entries returns a copy of the entries recorded so far, oldest first.
+func (r *Recorder) Entries() Entries {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(Entries(nil), r.entries...)
+}
+
+// FilterLevel returns the recorded entries at level.
+func (r *Recorder) FilterLevel(level LogLevel) Entries {
+	return r.Entries().FilterLevel(level)
+}
+
+// Contains reports whether the message of any recorded entry contains
+// substr.
+func (r *Recorder) Contains(substr string) bool {
+	return r.Entries().Contains(substr)
+}
+
+// FieldEquals returns the recorded entries whose field key deeply equals
+// value.
+func (r *Recorder) FieldEquals(key string, value any) Entries {
+	return r.Entries().FieldEquals(key, value)
+}
+
+// Reset discards the entries recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.entries = nil
+	r.mu.Unlock()
+}
+
+var _ LoggerInterface = (*Recorder)(nil)
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRecorderQueries(t *testing.T) {
+	rec := NewRecorder()
+	var li LoggerInterface = rec
+	li.Info("starting %s", "worker")
+	rec.Logger().WithFields(Fields{"user": "bob", "attempt": 2}).Warning("retrying")
+	rec.Logger().Named("db").WithFields(Fields{"user": "bob"}).Error("query timeout after %dms", 500)
+	rec.Logger().Debug("tick")
+	li.SetLogLevel(LogLevelWarning)
+	li.Info("filtered")
+
+	if n := len(rec.Entries()); n != 4 {
+		t.Fatalf("Expected 4 recorded entries, got %d: %v", n, rec.Entries())
+	}
+	errs := rec.FilterLevel(LogLevelError)
+	if len(errs) != 1 || errs[0].Message != "query timeout after 500ms" || errs[0].Logger != "db" {
+		t.Errorf("Expected the formatted error of the named logger, got %v", errs)
+	}
+	if !rec.Contains("timeout") || rec.Contains("filtered") {
+		t.Error("Expected Contains to match recorded messages only")
+	}
+	if got := rec.FieldEquals("user", "bob"); len(got) != 2 {
+		t.Errorf("Expected 2 entries for user bob, got %v", got)
+	}
+	if got := rec.FieldEquals("attempt", 2).FilterLevel(LogLevelWarning); len(got) != 1 || got[0].Message != "retrying" {
+		t.Errorf("Expected chained queries with raw field values, got %v", got)
+	}
+
+	rec.Reset()
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	rec.Error("after close")
+	if len(rec.Entries()) != 0 {
+		t.Errorf("Expected nothing recorded after Reset and Close, got %v", rec.Entries())
+	}
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	rec := NewRecorder()
+	sub, cancel := rec.Logger().Subscribe(nil)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rec.Info("entry")
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(rec.Entries()); n != 800 {
+		t.Errorf("Expected every entry recorded, got %d", n)
+	}
+	if len(sub) == 0 {
+		t.Error("Expected subscribers to keep receiving entries")
+	}
+}
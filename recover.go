@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/phuslu/log"
+)
+
+// appendField encodes key and value onto dst. A value whose String, Error or
+// MarshalJSON method panics is encoded as a string describing the panic, so
+// a buggy field never takes down the caller.
+func appendField(dst []byte, key string, value any) (out []byte) {
+	n := len(dst)
+	defer func() {
+		if r := recover(); r != nil {
+			out = log.NewContext(dst[:n]).Str(key, fmt.Sprintf("!PANIC(%v)", r)).Value()
+		}
+	}()
+	if s, ok := value.(string); ok {
+		value = sanitizeText(s)
+	}
+	return log.NewContext(dst).Any(fieldKey(key), value).Value()
+}
+
+// recoverEntry is deferred by the logging path. If building or writing an
+// entry panicked, it emits an error entry describing the panic instead and
+// lets the caller carry on. If that fails too, the panic is reported on
+// stderr.
+func (l *Logger) recoverEntry(format string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	defer func() {
+		if recover() != nil {
+			fmt.Fprintf(os.Stderr, "logging: panic while logging %q: %v\n", format, r)
+		}
+	}()
+	l.logger.Error().
+		Str("panic", fmt.Sprint(r)).
+		Str("format", format).
+		Msg("logging: recovered from panic while logging")
+}
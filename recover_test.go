@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+type panickyStringer struct{}
+
+func (panickyStringer) String() string { panic("stringer exploded") }
+
+type panickyMarshaler struct{}
+
+func (panickyMarshaler) MarshalJSON() ([]byte, error) { panic("marshal exploded") }
+
+// panickyWriter panics on its first write and succeeds afterwards.
+type panickyWriter struct {
+	buf      bytes.Buffer
+	panicked bool
+}
+
+func (w *panickyWriter) WriteEntry(e *log.Entry) (int, error) {
+	if !w.panicked {
+		w.panicked = true
+		panic("sink exploded")
+	}
+	return log.IOWriter{Writer: &w.buf}.WriteEntry(e)
+}
+
+func TestWithFieldsRecoversEncodingPanic(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.WithFields(Fields{
+		"a": panickyStringer{},
+		"b": panickyMarshaler{},
+		"c": "fine",
+	}).Info("still logged")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Entry should stay valid JSON: %v (%q)", err, buf.Bytes())
+	}
+	if s, _ := entry["a"].(string); !strings.Contains(s, "stringer exploded") {
+		t.Errorf("Expected panic description for field a, got %v", entry["a"])
+	}
+	if s, _ := entry["b"].(string); !strings.Contains(s, "marshal exploded") {
+		t.Errorf("Expected panic description for field b, got %v", entry["b"])
+	}
+	if entry["c"] != "fine" || entry["message"] != "still logged" {
+		t.Errorf("Other fields should be unaffected, got %v", entry)
+	}
+}
+
+func TestLogRecoversWriterPanic(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	w := &panickyWriter{}
+	logger.SetWriter(w)
+
+	logger.Info("boom %d", 1)
+
+	var entry map[string]any
+	if err := json.Unmarshal(w.buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a fallback entry: %v (%q)", err, w.buf.Bytes())
+	}
+	if entry["level"] != "error" || entry["panic"] != "sink exploded" || entry["format"] != "boom %d" {
+		t.Errorf("Unexpected fallback entry: %v", entry)
+	}
+}
+
+func TestFormatRecoversStringerPanic(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+
+	logger.Info("value: %v", panickyStringer{})
+	entry, err := parseLogEntry(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse log entry: %v", err)
+	}
+	if !strings.Contains(entry.Message, "PANIC") {
+		t.Errorf("Expected the panic to be described in the message, got %q", entry.Message)
+	}
+}
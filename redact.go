@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Redacted replaces the values of redacted fields.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are the key patterns redacted by WithRedaction and
+// SetRedaction when called without patterns.
+var DefaultRedactKeys = []string{
+	"password", "passwd", "*_password",
+	"secret", "*_secret",
+	"token", "*_token",
+	"api_key", "apikey",
+	"authorization", "cookie", "set-cookie",
+	"private_key",
+}
+
+// redactor replaces the values of fields whose key matches a pattern.
+type redactor struct {
+	patterns []string
+}
+
+// newRedactor validates patterns, which are matched case-insensitively
+// with path.Match against each key.
+func newRedactor(patterns []string) (*redactor, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactKeys
+	}
+	r := &redactor{patterns: make([]string, len(patterns))}
+	for i, p := range patterns {
+		p = strings.ToLower(p)
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("logging: redaction pattern %q: %w", p, err)
+		}
+		r.patterns[i] = p
+	}
+	return r, nil
+}
+
+func (r *redactor) match(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range r.patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redact replaces matching values in fields, which it owns, descending into
+// nested maps without modifying them.
+func (r *redactor) redact(fields Fields) {
+	for k, v := range fields {
+		if r.match(k) {
+			fields[k] = Redacted
+			continue
+		}
+		switch m := v.(type) {
+		case Fields:
+			fields[k] = r.redactMap(m)
+		case map[string]any:
+			fields[k] = map[string]any(r.redactMap(m))
+		case map[string]string:
+			fields[k] = r.redactStrings(m)
+		}
+	}
+}
+
+func (r *redactor) redactMap(m map[string]any) Fields {
+	c := make(Fields, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	r.redact(c)
+	return c
+}
+
+func (r *redactor) redactStrings(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		if r.match(k) {
+			v = Redacted
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// WithRedaction redacts fields as by Logger.SetRedaction, panicking on an
+// invalid pattern.
+func WithRedaction(patterns ...string) Option {
+	r, err := newRedactor(patterns)
+	if err != nil {
+		panic(err)
+	}
+	return optionFunc(func(o *options) { o.redact = r })
+}
+
+// SetRedaction replaces the values of fields whose key matches one of
+// patterns with "[REDACTED]" before they are encoded, for l and every
+// logger derived or named from the same root, so no sink ever sees them.
+// Patterns are matched case-insensitively with path.Match, so "*_secret"
+// covers "client_secret"; without patterns, DefaultRedactKeys apply.
+// Nested maps are redacted too.
+//
+// Fields are encoded when they are attached, so SetRedaction only affects
+// loggers derived with WithFields after the call; set it up before sharing
+// the logger, or use WithRedaction.
+func (l *Logger) SetRedaction(patterns ...string) error {
+	r, err := newRedactor(patterns)
+	if err != nil {
+		return err
+	}
+	l.state.tree.redact.Store(r)
+	return nil
+}
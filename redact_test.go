@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"testing"
+)
+
+func TestRedactionByKey(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	if err := logger.SetRedaction("password", "token", "authorization", "*_secret"); err != nil {
+		t.Fatalf("SetRedaction failed: %v", err)
+	}
+	headers := map[string]string{"Authorization": "Bearer abc", "Accept": "*/*"}
+	user := Fields{"name": "ada", "Password": "hunter2"}
+	logger.WithFields(Fields{
+		"token":         "t0k3n",
+		"client_secret": "s3cr3t",
+		"user_id":       42,
+		"headers":       headers,
+		"user":          user,
+	}).Named("api").Info("login")
+
+	entries := decodeEntries(t, buf.String())
+	e := entries[0]
+	for _, key := range []string{"token", "client_secret"} {
+		if e[key] != Redacted {
+			t.Errorf("Expected %s redacted, got %v", key, e[key])
+		}
+	}
+	if e["user_id"] != float64(42) {
+		t.Errorf("Unmatched fields should be kept, got %v", e["user_id"])
+	}
+	h := e["headers"].(map[string]any)
+	u := e["user"].(map[string]any)
+	if h["Authorization"] != Redacted || h["Accept"] != "*/*" || u["Password"] != Redacted || u["name"] != "ada" {
+		t.Errorf("Expected nested keys redacted case-insensitively, got %v and %v", h, u)
+	}
+	if headers["Authorization"] != "Bearer abc" || user["Password"] != "hunter2" {
+		t.Error("Redaction should not modify the caller's maps")
+	}
+}
+
+func TestWithRedactionDefaults(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := NewLogger(WithOutput(buf), WithFormat(FormatJSON), WithRedaction())
+	logger.WithFields(Fields{"api_key": "k", "refresh_token": "r", "path": "/"}).Info("call")
+
+	e := decodeEntries(t, string(buf.Bytes()))[0]
+	if e["api_key"] != Redacted || e["refresh_token"] != Redacted || e["path"] != "/" {
+		t.Errorf("Expected DefaultRedactKeys applied, got %v", e)
+	}
+	if err := logger.SetRedaction("[bad"); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
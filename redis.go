@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedisOptions configures Redis command logging.
+type RedisOptions struct {
+	// LogArgs includes the arguments following the key in each entry.
+	LogArgs bool
+
+	// RedactKeys keeps keys out of entries. With HashKey set, they are
+	// replaced by their HMAC-SHA256, so entries for the same key still
+	// correlate without revealing it; without it, they are dropped, since
+	// a plain hash of a short key is easily reversed by hashing guesses.
+	RedactKeys bool
+
+	// HashKey is the secret keying the hashes of redacted keys. Keep it
+	// stable across restarts for the hashes to correlate.
+	HashKey []byte
+}
+
+// RedisLogger logs Redis commands: successful ones at Debug level with
+// their duration and result size, failed ones at Warning. It carries no
+// dependency on a Redis client; the loggingredis module,
+// github.com/flyzard/go-logging/loggingredis, provides a go-redis v9 hook
+// built on it.
+type RedisLogger struct {
+	logger *Logger
+	opts   RedisOptions
+}
+
+// NewRedisLogger returns a RedisLogger logging to l, or to the logger
+// carried by each command's context if it was derived from l.
+func NewRedisLogger(l *Logger, opts RedisOptions) *RedisLogger {
+	return &RedisLogger{logger: l, opts: opts}
+}
+
+// Command logs a command started at start. args are the command name
+// followed by its arguments, the first of which is taken as the key.
+// result is the reply, whose size is logged: its length for strings,
+// slices and maps, or that of the result of its Val method for go-redis
+// commands.
+func (r *RedisLogger) Command(ctx context.Context, args []any, start time.Time, result any, err error) {
+	l := r.logger.scoped(ctx)
+	if err == nil && !l.enabled(LogLevelDebug) {
+		return
+	}
+	fields := r.fields(args)
+	fields["duration"] = time.Since(start)
+	if err != nil {
+		fields["error"] = err
+		l.WithFields(fields).Warning("redis command failed")
+		return
+	}
+	fields["result_size"] = resultSize(result)
+	l.WithFields(fields).Debug("redis command")
+}
+
+// Dial logs a connection attempt to addr started at start: a failed one
+// at Warning level, a successful one at Debug.
+func (r *RedisLogger) Dial(ctx context.Context, network, addr string, start time.Time, err error) {
+	l := r.logger.scoped(ctx)
+	if err == nil && !l.enabled(LogLevelDebug) {
+		return
+	}
+	fields := Fields{"network": network, "addr": addr, "duration": time.Since(start)}
+	if err != nil {
+		fields["error"] = err
+		l.WithFields(fields).Warning("redis dial failed")
+		return
+	}
+	l.WithFields(fields).Debug("redis dial")
+}
+
+// Pipeline logs a pipeline of commands started at start, with the names
+// of its commands.
+func (r *RedisLogger) Pipeline(ctx context.Context, cmds [][]any, start time.Time, err error) {
+	l := r.logger.scoped(ctx)
+	if err == nil && !l.enabled(LogLevelDebug) {
+		return
+	}
+	names := make([]string, len(cmds))
+	for i, args := range cmds {
+		if len(args) > 0 {
+			names[i] = strings.ToLower(fmt.Sprint(args[0]))
+		}
+	}
+	fields := Fields{"redis_pipeline": names, "duration": time.Since(start)}
+	if err != nil {
+		fields["error"] = err
+		l.WithFields(fields).Warning("redis pipeline failed")
+		return
+	}
+	l.WithFields(fields).Debug("redis pipeline")
+}
+
+func (r *RedisLogger) fields(args []any) Fields {
+	fields := Fields{}
+	if len(args) == 0 {
+		return fields
+	}
+	fields["redis_cmd"] = strings.ToLower(fmt.Sprint(args[0]))
+	switch {
+	case len(args) < 2:
+	case !r.opts.RedactKeys:
+		fields["redis_key"] = fmt.Sprint(args[1])
+	case r.opts.HashKey != nil:
+		fields["redis_key"] = hashValue(r.opts.HashKey, args[1])
+	}
+	if r.opts.LogArgs && len(args) > 2 {
+		fields["redis_args"] = args[2:]
+	}
+	return fields
+}
+
+// resultSize returns the number of elements or bytes of a command reply.
+func resultSize(result any) int {
+	if result == nil {
+		return 0
+	}
+	v := reflect.ValueOf(result)
+	if m := v.MethodByName("Val"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() > 0 {
+		v = m.Call(nil)[0]
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len()
+	}
+	return 1
+}
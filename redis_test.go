@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedisLoggerCommand(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	r := NewRedisLogger(logger, RedisOptions{RedactKeys: true, HashKey: []byte("salt")})
+	r.Command(context.Background(), []any{"GET", "session:alice"}, time.Now(), "0123456789", nil)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+	if entry["level"] != "debug" || entry["redis_cmd"] != "get" || entry["result_size"] != float64(10) {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+	if key, _ := entry["redis_key"].(string); !strings.HasPrefix(key, "sha256:") || strings.Contains(buf.String(), "alice") {
+		t.Errorf("Expected a redacted key, got %q", buf.Bytes())
+	}
+	if _, ok := entry["redis_args"]; ok {
+		t.Error("Arguments should not be logged without LogArgs")
+	}
+}
+
+func TestRedisLoggerRedactsKeys(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	args := []any{"GET", "session:alice"}
+	NewRedisLogger(logger, RedisOptions{RedactKeys: true, HashKey: []byte("a")}).Command(context.Background(), args, time.Now(), nil, nil)
+	NewRedisLogger(logger, RedisOptions{RedactKeys: true, HashKey: []byte("b")}).Command(context.Background(), args, time.Now(), nil, nil)
+	NewRedisLogger(logger, RedisOptions{RedactKeys: true}).Command(context.Background(), args, time.Now(), nil, nil)
+
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if a, b := entries[0]["redis_key"], entries[1]["redis_key"]; a == b || a == nil {
+		t.Errorf("Expected hashes keyed by HashKey, got %v and %v", a, b)
+	}
+	if key, ok := entries[2]["redis_key"]; ok {
+		t.Errorf("Expected the key dropped without HashKey, got %v", key)
+	}
+}
+
+func TestRedisLoggerDebugOff(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	r := NewRedisLogger(logger, RedisOptions{RedactKeys: true, HashKey: []byte("salt")})
+	ctx, args, start := context.Background(), []any{"get", "k"}, time.Now()
+	if allocs := testing.AllocsPerRun(100, func() { r.Command(ctx, args, start, "v", nil) }); allocs != 0 {
+		t.Errorf("Expected no allocations with Debug off, got %v", allocs)
+	}
+}
+
+func TestRedisLoggerErrors(t *testing.T) {
+	logger, buf := testLogger(LogLevelWarning)
+	r := NewRedisLogger(logger, RedisOptions{LogArgs: true})
+	r.Command(context.Background(), []any{"set", "k", "v"}, time.Now(), nil, nil)
+	if buf.Len() != 0 {
+		t.Errorf("Successful commands should be logged at Debug only, got %q", buf.Bytes())
+	}
+	r.Command(context.Background(), []any{"set", "k", "v"}, time.Now(), nil, errors.New("READONLY"))
+	r.Pipeline(context.Background(), [][]any{{"INCR", "a"}, {"EXPIRE", "a", 10}}, time.Now(), errors.New("EOF"))
+	out := buf.String()
+	for _, want := range []string{`"level":"warn"`, `"redis_key":"k"`, `"redis_args":["v"]`, `"redis_pipeline":["incr","expire"]`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in %q", want, out)
+		}
+	}
+}
+
+func TestRedisLoggerDial(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	r := NewRedisLogger(logger, RedisOptions{})
+	r.Dial(context.Background(), "tcp", "cache:6379", time.Now(), nil)
+	r.Dial(context.Background(), "tcp", "cache:6379", time.Now(), errors.New("connection refused"))
+
+	dec := json.NewDecoder(buf)
+	for _, want := range []string{"debug", "warn"} {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Failed to parse entry: %v", err)
+		}
+		if entry["level"] != want || entry["addr"] != "cache:6379" || entry["network"] != "tcp" {
+			t.Errorf("Unexpected %s dial entry: %v", want, entry)
+		}
+	}
+}
+
+type stringCmd struct{ val string }
+
+func (c *stringCmd) Val() string { return c.val }
+
+func TestResultSize(t *testing.T) {
+	for _, tt := range []struct {
+		result any
+		want   int
+	}{
+		{nil, 0},
+		{"abc", 3},
+		{[]string{"a", "b"}, 2},
+		{int64(7), 1},
+		{&stringCmd{"hello"}, 5},
+	} {
+		if got := resultSize(tt.result); got != tt.want {
+			t.Errorf("resultSize(%#v) = %d, want %d", tt.result, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// WatchConfig creates a Logger from the config file at path, in any format
+// LoadConfig reads, and polls the file every interval (default 1s) until
+// ctx is done. When the file changes, its level, sinks and sampling are
+// applied without a restart. A config that fails to load or validate is
+// rejected with an error entry and the previous one stays in effect. Other
+// settings, such as the time format, are read once at startup.
+func WatchConfig(ctx context.Context, path string, interval time.Duration) (*Logger, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	o, sampler, err := cfg.build()
+	if err != nil {
+		return nil, err
+	}
+	sw := &swapWriter{w: o.writer}
+	ss := new(swapSampler)
+	ss.set(sampler)
+	o.writer = sw
+	logger := newLogger(o)
+	logger.SetSampler(ss)
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+	r := &reloader{path: path, last: data, logger: logger, writer: sw, sampler: ss}
+	go r.run(ctx, interval)
+	return logger, nil
+}
+
+type reloader struct {
+	path    string
+	last    []byte
+	logger  *Logger
+	writer  *swapWriter
+	sampler *swapSampler
+}
+
+func (r *reloader) run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			r.poll()
+		}
+	}
+}
+
+// poll applies the config file if it changed since the last poll.
+func (r *reloader) poll() {
+	data, err := os.ReadFile(r.path)
+	if err != nil || bytes.Equal(data, r.last) {
+		return
+	}
+	r.last = data
+	cfg, err := parseConfigFile(r.path, data)
+	if err != nil {
+		r.reject(err)
+		return
+	}
+	o, sampler, err := cfg.build()
+	if err != nil {
+		r.reject(err)
+		return
+	}
+	old := r.writer.swap(o.writer)
+	_ = flushWriter(old)
+	_ = closeWriter(old)
+	r.sampler.set(sampler)
+	r.logger.applyLevelSpec(o.level, true, o.levels)
+	r.logger.WithFields(Fields{"path": r.path}).Info("logging: config reloaded")
+}
+
+func (r *reloader) reject(err error) {
+	r.logger.WithFields(Fields{"path": r.path, "error": err}).Error("logging: config reload rejected, keeping the previous config")
+}
+
+// swapWriter is a log.Writer whose destination can be replaced while
+// entries are being written.
+type swapWriter struct {
+	mu sync.RWMutex
+	w  log.Writer
+}
+
+func (w *swapWriter) WriteEntry(e *log.Entry) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.w.WriteEntry(e)
+}
+
+// swap installs next and returns the previous writer, which no write is
+// using any more once swap returns.
+func (w *swapWriter) swap(next log.Writer) log.Writer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	old := w.w
+	w.w = next
+	return old
+}
+
+func (w *swapWriter) unwrap() any {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.w
+}
+
+func (w *swapWriter) Flush() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return flushWriter(w.w)
+}
+
+func (w *swapWriter) Close() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return closeWriter(w.w)
+}
+
+// swapSampler is a Sampler that can be replaced while in use. A nil sampler
+// keeps every entry.
+type swapSampler struct {
+	p atomic.Pointer[samplerBox]
+}
+
+type samplerBox struct {
+	Sampler
+}
+
+func (s *swapSampler) set(sampler Sampler) {
+	s.p.Store(&samplerBox{sampler})
+}
+
+func (s *swapSampler) Sample(level LogLevel) bool {
+	b := s.p.Load()
+	return b == nil || b.Sampler == nil || b.Sampler.Sample(level)
+}
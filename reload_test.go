@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchConfigReloads(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "logging.json")
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "second.log")
+	writeJSON := func(level, sink string) {
+		data := fmt.Sprintf(`{"level": %q, "format": "json", "sinks": [{"type": "file", "path": %q}]}`, level, sink)
+		if err := os.WriteFile(cfgPath, []byte(data), 0o644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+	writeJSON("error", first)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := WatchConfig(ctx, cfgPath, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig failed: %v", err)
+	}
+	defer logger.Close()
+	if logger.Level() != LogLevelError {
+		t.Fatalf("Expected initial level error, got %v", logger.Level())
+	}
+
+	writeJSON("debug", second)
+	waitFor(t, "the level change", func() bool { return logger.Level() == LogLevelDebug })
+	logger.Debug("after reload")
+	data, _ := os.ReadFile(second)
+	if !bytes.Contains(data, []byte("after reload")) {
+		t.Errorf("Expected entries in the new sink, got %q", data)
+	}
+}
+
+func TestWatchConfigReloadsYAML(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "logging.yaml")
+	writeYAML := func(level string) {
+		data := fmt.Sprintf("level: %s\nsinks:\n  - {type: file, path: %q}\n", level, filepath.Join(dir, "app.log"))
+		if err := os.WriteFile(cfgPath, []byte(data), 0o644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+	writeYAML("error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := WatchConfig(ctx, cfgPath, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig failed: %v", err)
+	}
+	defer logger.Close()
+
+	writeYAML("debug")
+	waitFor(t, "the level change", func() bool { return logger.Level() == LogLevelDebug })
+}
+
+func TestWatchConfigRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "logging.json")
+	sink := filepath.Join(dir, "app.log")
+	valid := fmt.Sprintf(`{"level": "warning", "format": "json", "sinks": [{"type": "file", "path": %q}]}`, sink)
+	if err := os.WriteFile(cfgPath, []byte(valid), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, err := WatchConfig(ctx, cfgPath, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig failed: %v", err)
+	}
+	defer logger.Close()
+
+	if err := os.WriteFile(cfgPath, []byte(`{"level": "loud"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	waitFor(t, "the rejection", func() bool {
+		data, _ := os.ReadFile(sink)
+		return bytes.Contains(data, []byte("reload rejected"))
+	})
+	if logger.Level() != LogLevelWarning {
+		t.Errorf("A rejected config should keep the previous level, got %v", logger.Level())
+	}
+}
+
+func TestWatchConfigInvalidAtStartup(t *testing.T) {
+	path := writeConfig(t, "logging.json", `{"format": "xml"}`)
+	if _, err := WatchConfig(context.Background(), path, time.Second); err == nil {
+		t.Error("Expected an error for an invalid initial config")
+	}
+}
@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LevelSource provides the desired levels of a service as a level spec
+// such as "info,db=debug" (see ParseLevelSpec). An empty spec means the
+// source has no levels for the service.
+type LevelSource interface {
+	LevelSpec(ctx context.Context) (string, error)
+}
+
+// LevelSourceFunc adapts an ordinary function to a LevelSource, for example
+// to read a key with an etcd or Consul client.
+type LevelSourceFunc func(ctx context.Context) (string, error)
+
+// LevelSpec calls f(ctx).
+func (f LevelSourceFunc) LevelSpec(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// HTTPLevelSource is a LevelSource reading the level spec from the body of
+// a GET request to URL, such as a Consul KV key read with ?raw. A 404
+// response is an empty spec.
+type HTTPLevelSource struct {
+	// URL is the endpoint returning the spec.
+	URL string
+
+	// Header is added to each request, for example for an access token.
+	Header http.Header
+
+	// Client performs the requests. Defaults to http.DefaultClient; use
+	// TLSOptions.Client for custom CAs or client certificates.
+	Client *http.Client
+}
+
+// LevelSpec implements LevelSource.
+func (s *HTTPLevelSource) LevelSpec(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	maps.Copy(req.Header, s.Header)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", nil
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("logging: level source %s: %s", s.URL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return strings.TrimSpace(string(body)), err
+}
+
+// WatchLevels polls src every interval (default 30s) until ctx is done and
+// applies the spec it returns to l and its named loggers. While the source
+// is unreachable, returns an invalid spec or has no levels for the service,
+// the levels in effect when WatchLevels was called are restored, so a
+// failing source never leaves the service stuck at a remote override. Each
+// switch between remote and local levels is logged.
+//
+// WatchLevels returns immediately; the first poll happens at once.
+func (l *Logger) WatchLevels(ctx context.Context, src LevelSource, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	n := &l.state.tree.names
+	n.mu.Lock()
+	w := &levelWatcher{
+		logger:   l,
+		src:      src,
+		interval: interval,
+		root:     LogLevel(n.root.level.Load()),
+		local:    maps.Clone(n.explicit),
+	}
+	n.mu.Unlock()
+	go w.run(ctx)
+}
+
+type levelWatcher struct {
+	logger   *Logger
+	src      LevelSource
+	interval time.Duration
+	root     LogLevel
+	local    map[string]LogLevel
+	applied  string // the remote spec in effect, empty when local
+}
+
+func (w *levelWatcher) run(ctx context.Context) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (w *levelWatcher) poll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.interval)
+	defer cancel()
+	spec, err := w.src.LevelSpec(ctx)
+	if err != nil {
+		if ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded {
+			return // the watch is stopping
+		}
+		w.fallback(err)
+		return
+	}
+	if spec == "" {
+		w.fallback(nil)
+		return
+	}
+	if spec == w.applied {
+		return
+	}
+	def, hasDef, levels, err := ParseLevelSpec(spec)
+	if err != nil {
+		w.fallback(err)
+		return
+	}
+	if !hasDef {
+		def, hasDef = w.root, true
+	}
+	w.logger.applyLevelSpec(def, hasDef, levels)
+	w.applied = spec
+	w.logger.WithFields(Fields{"spec": spec}).Info("logging: applied levels from level source")
+}
+
+// fallback restores the local levels if remote ones are in effect.
+func (w *levelWatcher) fallback(err error) {
+	if w.applied == "" {
+		return
+	}
+	w.applied = ""
+	w.logger.applyLevelSpec(w.root, true, maps.Clone(w.local))
+	fields := Fields{}
+	if err != nil {
+		fields["error"] = err
+	}
+	w.logger.WithFields(fields).Warning("logging: level source unavailable, restored local levels")
+}
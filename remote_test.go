@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchLevelsAppliesAndFallsBack(t *testing.T) {
+	var mu sync.Mutex
+	spec, fail := "", false
+	src := LevelSourceFunc(func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			return "", errors.New("connection refused")
+		}
+		return spec, nil
+	})
+	set := func(s string, f bool) {
+		mu.Lock()
+		spec, fail = s, f
+		mu.Unlock()
+	}
+
+	logger := NewLogger(LogLevelWarning, WithOutput(new(syncBuffer)))
+	db := logger.Named("db")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger.WatchLevels(ctx, src, 5*time.Millisecond)
+
+	set("info,db=debug", false)
+	waitFor(t, "the remote levels", func() bool {
+		return logger.Level() == LogLevelInfo && db.Level() == LogLevelDebug
+	})
+	set("", true)
+	waitFor(t, "the local levels", func() bool {
+		return logger.Level() == LogLevelWarning && db.Level() == LogLevelWarning
+	})
+	set("db=error", false)
+	waitFor(t, "the remote levels", func() bool { return db.Level() == LogLevelError })
+	if logger.Level() != LogLevelWarning {
+		t.Errorf("A spec without a bare level should keep the local root level, got %v", logger.Level())
+	}
+	set("db=loud", false)
+	waitFor(t, "the local levels", func() bool { return db.Level() == LogLevelWarning })
+}
+
+func TestHTTPLevelSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("debug,db=error\n"))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	header := http.Header{"X-Token": {"secret"}}
+
+	src := &HTTPLevelSource{URL: srv.URL + "/levels", Header: header}
+	if spec, err := src.LevelSpec(ctx); err != nil || spec != "debug,db=error" {
+		t.Errorf("Expected the spec, got %q (%v)", spec, err)
+	}
+	src.URL = srv.URL + "/missing"
+	if spec, err := src.LevelSpec(ctx); err != nil || spec != "" {
+		t.Errorf("A missing key should be an empty spec, got %q (%v)", spec, err)
+	}
+	src.Header = nil
+	if _, err := src.LevelSpec(ctx); err == nil {
+		t.Error("Expected an error for a non-200 response")
+	}
+}
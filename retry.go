@@ -0,0 +1,302 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// RetryPolicy configures how a RetrySink or RetryWriter retries failed
+// deliveries: the delay before attempt n+1 is InitialBackoff *
+// Multiplier^(n-1), capped at MaxBackoff and randomized by Jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first, before a
+	// delivery is given up. Defaults to 5.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Defaults to 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+
+	// Multiplier is the growth factor of the delay. Defaults to 2.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction of it in either
+	// direction, so many processes do not retry in lockstep. Zero disables it.
+	Jitter float64
+
+	// Retryable, if set, decides which errors are retried. By default every
+	// error except RejectedError and those wrapped with Permanent is.
+	Retryable func(err error) bool
+}
+
+// RetryAfterError is returned by a sink asked by its collector to wait
+// before retrying, as with HTTP 429 and 503 responses. The wait, capped at
+// MaxBackoff, replaces the computed backoff for the next attempt.
+type RetryAfterError struct {
+	// Err is the delivery error.
+	Err error
+	// After is the delay requested by the collector.
+	After time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %v)", e.Err, e.After)
+}
+
+// Unwrap returns the delivery error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the default retry policy gives up on it at once,
+// for failures such as rejected credentials or malformed payloads.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// CheckHTTPResponse returns the error a sink posting to an HTTP collector
+// should report for resp: nil for 2xx, a RetryAfterError honoring the
+// Retry-After header for 429 and 503, a permanent error for other 4xx
+// except 408, and a retryable one otherwise.
+func CheckHTTPResponse(resp *http.Response) error {
+	code := resp.StatusCode
+	if code >= 200 && code < 300 {
+		return nil
+	}
+	err := fmt.Errorf("logging: collector returned %s", resp.Status)
+	switch {
+	case code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable:
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return &RetryAfterError{Err: err, After: after}
+		}
+	case code >= 400 && code < 500 && code != http.StatusRequestTimeout:
+		return Permanent(err)
+	}
+	return err
+}
+
+// parseRetryAfter parses a Retry-After header holding either seconds or an
+// HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// retrier runs deliveries under a RetryPolicy and counts their outcome.
+type retrier struct {
+	counters deliveryCounters
+	recent   failureWindow
+
+	once sync.Once
+	done chan struct{}
+}
+
+func (r *retrier) closed() chan struct{} {
+	r.once.Do(func() { r.done = make(chan struct{}) })
+	return r.done
+}
+
+// stop interrupts pending backoffs; deliveries in progress give up after
+// their current attempt.
+func (r *retrier) stop() {
+	done := r.closed()
+	select {
+	case <-done:
+	default:
+		close(done)
+	}
+}
+
+// do calls deliver until it succeeds, fails permanently, runs out of
+// attempts or the retrier is stopped, and counts n entries of size bytes
+// accordingly.
+func (r *retrier) do(p *RetryPolicy, n, size int, deliver func() error) error {
+	done := r.closed()
+	backoff := p.initialBackoff()
+	for attempt := 1; ; attempt++ {
+		err := deliver()
+		if err == nil {
+			r.counters.deliver(n, size)
+			r.recent.record(false)
+			return nil
+		}
+		if attempt >= p.maxAttempts() || !p.retryable(err) {
+			r.counters.failed.Add(uint64(n))
+			r.recent.record(true)
+			return err
+		}
+		delay := p.jitter(backoff)
+		var ra *RetryAfterError
+		if errors.As(err, &ra) {
+			delay = min(ra.After, p.maxBackoff())
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-done:
+			t.Stop()
+			r.counters.failed.Add(uint64(n))
+			r.recent.record(true)
+			return err
+		}
+		r.counters.retries.Add(1)
+		backoff = min(time.Duration(float64(backoff)*p.multiplier()), p.maxBackoff())
+	}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 5
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) initialBackoff() time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 100 * time.Millisecond
+	}
+	return p.InitialBackoff
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return 30 * time.Second
+	}
+	return p.MaxBackoff
+}
+
+func (p *RetryPolicy) multiplier() float64 {
+	if p.Multiplier <= 0 {
+		return 2
+	}
+	return p.Multiplier
+}
+
+func (p *RetryPolicy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+	return d + time.Duration((rand.Float64()*2-1)*p.Jitter*float64(d))
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	var perm permanentError
+	var rejected *RejectedError
+	return !errors.As(err, &perm) && !errors.As(err, &rejected)
+}
+
+// RetrySink is a BatchSink retrying failed batches of Sink under Policy,
+// for use between a BatchWriter and a network collector:
+//
+//	sink := logging.NewRetrySink(collector, logging.RetryPolicy{Jitter: 0.2})
+//	logger.SetWriter(logging.NewBatchWriter(sink, 500, time.Second))
+//
+// Retries run on the goroutine flushing the batch, so put an AsyncWriter
+// in front when callers must not wait for them.
+type RetrySink struct {
+	// Sink receives the batches.
+	Sink BatchSink
+
+	// Policy configures the retries.
+	Policy RetryPolicy
+
+	retrier
+}
+
+// NewRetrySink returns a RetrySink delivering to sink under policy.
+func NewRetrySink(sink BatchSink, policy RetryPolicy) *RetrySink {
+	return &RetrySink{Sink: sink, Policy: policy}
+}
+
+// WriteBatch implements BatchSink. It returns the last error if the batch
+// is given up on.
+func (s *RetrySink) WriteBatch(entries [][]byte) error {
+	return s.do(&s.Policy, len(entries), batchBytes(entries), func() error { return s.Sink.WriteBatch(entries) })
+}
+
+// Stats returns the delivery counters of s.
+func (s *RetrySink) Stats() DeliveryStats {
+	return s.counters.snapshot()
+}
+
+// Close stops pending retries and closes Sink if it is closable.
+func (s *RetrySink) Close() error {
+	s.stop()
+	return closeWriter(s.Sink)
+}
+
+// RetryWriter is a log.Writer retrying failed writes of Writer under
+// Policy, for sinks that send each entry on its own.
+type RetryWriter struct {
+	// Writer is the destination of entries.
+	Writer log.Writer
+
+	// Policy configures the retries.
+	Policy RetryPolicy
+
+	retrier
+}
+
+// NewRetryWriter returns a RetryWriter writing to w under policy.
+func NewRetryWriter(w log.Writer, policy RetryPolicy) *RetryWriter {
+	return &RetryWriter{Writer: w, Policy: policy}
+}
+
+// WriteEntry implements log.Writer.
+func (w *RetryWriter) WriteEntry(e *log.Entry) (n int, err error) {
+	err = w.do(&w.Policy, 1, 0, func() error {
+		n, err = w.Writer.WriteEntry(e)
+		return err
+	})
+	if err == nil {
+		w.counters.bytes.Add(uint64(n))
+	}
+	return n, err
+}
+
+// Stats returns the delivery counters of w.
+func (w *RetryWriter) Stats() DeliveryStats {
+	return w.counters.snapshot()
+}
+
+// Flush flushes Writer if it buffers entries.
+func (w *RetryWriter) Flush() error {
+	return flushWriter(w.Writer)
+}
+
+// Close stops pending retries and closes Writer if it is closable.
+func (w *RetryWriter) Close() error {
+	w.stop()
+	return closeWriter(w.Writer)
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// flakySink fails the first failures batches with err.
+type flakySink struct {
+	failures int
+	err      error
+	calls    int
+	times    []time.Time
+}
+
+func (s *flakySink) WriteBatch(entries [][]byte) error {
+	s.calls++
+	s.times = append(s.times, time.Now())
+	if s.calls <= s.failures {
+		return s.err
+	}
+	return nil
+}
+
+func TestRetrySinkBacksOff(t *testing.T) {
+	sink := &flakySink{failures: 2, err: errors.New("connection reset")}
+	rs := NewRetrySink(sink, RetryPolicy{InitialBackoff: 5 * time.Millisecond, Multiplier: 2})
+
+	if err := rs.WriteBatch(make([][]byte, 3)); err != nil {
+		t.Fatalf("Expected the batch to be delivered after retries, got %v", err)
+	}
+	if sink.calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", sink.calls)
+	}
+	if gap := sink.times[2].Sub(sink.times[1]); gap < 10*time.Millisecond {
+		t.Errorf("Expected the second delay to double, got %v", gap)
+	}
+	if got := rs.Stats(); got != (DeliveryStats{Delivered: 3, Retries: 2}) {
+		t.Errorf("Unexpected stats: %+v", got)
+	}
+}
+
+func TestRetrySinkGivesUp(t *testing.T) {
+	sink := &flakySink{failures: 10, err: errors.New("connection refused")}
+	rs := NewRetrySink(sink, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	if err := rs.WriteBatch(make([][]byte, 2)); err == nil {
+		t.Fatal("Expected the last error once attempts run out")
+	}
+	if sink.calls != 3 || rs.Stats().Failed != 2 {
+		t.Errorf("Expected 3 attempts and 2 failed entries, got %d and %+v", sink.calls, rs.Stats())
+	}
+
+	sink = &flakySink{failures: 10, err: Permanent(errors.New("unauthorized"))}
+	rs = NewRetrySink(sink, RetryPolicy{InitialBackoff: time.Millisecond})
+	_ = rs.WriteBatch(make([][]byte, 1))
+	if sink.calls != 1 {
+		t.Errorf("Expected permanent errors not to be retried, got %d attempts", sink.calls)
+	}
+}
+
+func TestRetrySinkHonorsRetryAfter(t *testing.T) {
+	sink := &flakySink{failures: 1, err: &RetryAfterError{Err: errors.New("throttled"), After: 30 * time.Millisecond}}
+	rs := NewRetrySink(sink, RetryPolicy{InitialBackoff: time.Millisecond})
+	if err := rs.WriteBatch(make([][]byte, 1)); err != nil {
+		t.Fatalf("WriteBatch failed: %v", err)
+	}
+	if gap := sink.times[1].Sub(sink.times[0]); gap < 30*time.Millisecond {
+		t.Errorf("Expected the collector's Retry-After to be honored, got %v", gap)
+	}
+
+	sink = &flakySink{failures: 1, err: &RetryAfterError{Err: errors.New("throttled"), After: time.Hour}}
+	rs = NewRetrySink(sink, RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	done := make(chan error)
+	go func() { done <- rs.WriteBatch(make([][]byte, 1)) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WriteBatch failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Retry-After to be capped at MaxBackoff")
+	}
+}
+
+func TestRetrySinkCloseStopsRetries(t *testing.T) {
+	sink := &flakySink{failures: 10, err: errors.New("down")}
+	rs := NewRetrySink(sink, RetryPolicy{InitialBackoff: time.Hour})
+	done := make(chan error)
+	go func() { done <- rs.WriteBatch(make([][]byte, 1)) }()
+	time.Sleep(10 * time.Millisecond)
+	_ = rs.Close()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected the delivery error after Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close should interrupt a pending backoff")
+	}
+}
+
+func TestCheckHTTPResponse(t *testing.T) {
+	resp := func(code int, retryAfter string) *http.Response {
+		r := &http.Response{StatusCode: code, Status: http.StatusText(code), Header: http.Header{}}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		return r
+	}
+	if err := CheckHTTPResponse(resp(204, "")); err != nil {
+		t.Errorf("Expected nil for 2xx, got %v", err)
+	}
+	var ra *RetryAfterError
+	if err := CheckHTTPResponse(resp(429, "7")); !errors.As(err, &ra) || ra.After != 7*time.Second {
+		t.Errorf("Expected a 7s RetryAfterError, got %v", err)
+	}
+	var p RetryPolicy
+	if p.retryable(CheckHTTPResponse(resp(400, ""))) {
+		t.Error("Expected 400 to be permanent")
+	}
+	if !p.retryable(CheckHTTPResponse(resp(502, ""))) {
+		t.Error("Expected 502 to be retried")
+	}
+}
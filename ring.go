@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"sync"
+
+	"github.com/phuslu/log"
+)
+
+// RingWriter is a log.Writer that keeps the most recent Size entries in
+// memory, JSON-encoded, and passes every entry on to Writer. It backs
+// in-process views of the log such as TailHandler.
+type RingWriter struct {
+	// Writer, if set, receives every entry.
+	Writer log.Writer
+
+	// Size is the number of entries kept. Defaults to 1000.
+	Size int
+
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	closed  bool
+	subs    map[chan []byte]struct{}
+}
+
+// NewRingWriter returns a RingWriter keeping size entries and writing to w.
+func NewRingWriter(w log.Writer, size int) *RingWriter {
+	return &RingWriter{Writer: w, Size: size}
+}
+
+// WriteEntry implements log.Writer.
+func (w *RingWriter) WriteEntry(e *log.Entry) (int, error) {
+	var b entryBuffer
+	_, _ = log.IOWriter{Writer: &b}.WriteEntry(e)
+
+	w.mu.Lock()
+	size := w.Size
+	if size <= 0 {
+		size = 1000
+	}
+	if len(w.entries) < size {
+		w.entries = append(w.entries, b)
+	} else {
+		w.entries[w.next] = b
+		w.next = (w.next + 1) % len(w.entries)
+	}
+	for ch := range w.subs {
+		select {
+		case ch <- b:
+		default: // a slow subscriber misses entries rather than block logging
+		}
+	}
+	w.mu.Unlock()
+
+	if w.Writer == nil {
+		return len(b), nil
+	}
+	return w.Writer.WriteEntry(e)
+}
+
+// Entries returns the kept entries, oldest first, one JSON object per
+// element.
+func (w *RingWriter) Entries() [][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.recentLocked()
+}
+
+func (w *RingWriter) recentLocked() [][]byte {
+	recent := make([][]byte, 0, len(w.entries))
+	recent = append(recent, w.entries[w.next:]...)
+	return append(recent, w.entries[:w.next]...)
+}
+
+// subscribe returns the kept entries and a channel receiving every later
+// entry, with no gap between the two. Entries are shared and must not be
+// modified. The channel is closed by cancel or when w is closed.
+func (w *RingWriter) subscribe(buffer int) (recent [][]byte, ch <-chan []byte, cancel func()) {
+	c := make(chan []byte, buffer)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		close(c)
+		return w.recentLocked(), c, func() {}
+	}
+	if w.subs == nil {
+		w.subs = make(map[chan []byte]struct{})
+	}
+	w.subs[c] = struct{}{}
+	return w.recentLocked(), c, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if _, ok := w.subs[c]; ok {
+			delete(w.subs, c)
+			close(c)
+		}
+	}
+}
+
+// Flush flushes Writer if it buffers entries.
+func (w *RingWriter) Flush() error {
+	return flushWriter(w.Writer)
+}
+
+// Close ends all subscriptions and closes Writer if it is closable. The
+// kept entries remain readable.
+func (w *RingWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	for ch := range w.subs {
+		close(ch)
+	}
+	w.subs = nil
+	w.mu.Unlock()
+	return closeWriter(w.Writer)
+}
+
+// SetRingBuffer keeps the last size entries in memory, in addition to
+// writing them to the current destination, and returns the RingWriter
+// holding them, for example to serve its TailHandler. It should be called
+// before the logger is shared between goroutines.
+func (l *Logger) SetRingBuffer(size int) *RingWriter {
+	ring := NewRingWriter(l.writer(), size)
+	l.logger.Writer = ring
+	return ring
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRingWriterKeepsRecent(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	ring := logger.SetRingBuffer(3)
+	for i := 1; i <= 5; i++ {
+		logger.Info("entry %d", i)
+	}
+
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []string{"entry 3", "entry 4", "entry 5"} {
+		if !bytes.Contains(entries[i], []byte(want)) {
+			t.Errorf("Expected %q at %d, got %q", want, i, entries[i])
+		}
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("entry 1")) {
+		t.Error("Entries should still reach the previous writer")
+	}
+}
+
+func TestRingWriterSubscribe(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	ring := logger.SetRingBuffer(10)
+	logger.Info("before")
+
+	recent, entries, cancel := ring.subscribe(10)
+	defer cancel()
+	logger.Info("after")
+	if len(recent) != 1 || !bytes.Contains(recent[0], []byte("before")) {
+		t.Errorf("Expected the kept entry, got %q", recent)
+	}
+	if e := <-entries; !bytes.Contains(e, []byte("after")) {
+		t.Errorf("Expected the live entry, got %q", e)
+	}
+
+	if err := ring.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, ok := <-entries; ok {
+		t.Error("Close should end subscriptions")
+	}
+	cancel()
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RPCOptions configures RPC logging.
+type RPCOptions struct {
+	// LogPayloads logs request and response messages at Debug level.
+	LogPayloads bool
+
+	// MaxPayloadSize truncates logged payloads to this many bytes.
+	// Defaults to 1024.
+	MaxPayloadSize int
+}
+
+// RPCCall logs one RPC. It is created by StartRPC and ended by Finish.
+//
+// It carries no dependency on an RPC framework. The logginggrpc module,
+// github.com/flyzard/go-logging/logginggrpc, provides gRPC unary and stream
+// interceptors for servers and clients built on it.
+type RPCCall struct {
+	logger *Logger
+	opts   RPCOptions
+	start  time.Time
+}
+
+// StartRPC logs the start of an RPC at Debug level and returns a context
+// carrying the RPC's logger, retrieved with FromContext. kind is "server"
+// or "client", method the full method name and peer the remote address,
+// which may be empty.
+func (l *Logger) StartRPC(ctx context.Context, kind, method, peer string, opts RPCOptions) (context.Context, *RPCCall) {
+	fields := Fields{"rpc_kind": kind, "rpc_method": method}
+	if peer != "" {
+		fields["peer"] = peer
+	}
+	c := &RPCCall{logger: l.WithFields(fields), opts: opts, start: time.Now()}
+	c.logger.Debug("rpc started")
+	return NewContext(ctx, c.logger), c
+}
+
+// Payload logs msg at Debug level if RPCOptions.LogPayloads is set.
+// direction names the message, such as "request" or "response". The
+// message is JSON-encoded if possible and truncated to MaxPayloadSize.
+func (c *RPCCall) Payload(direction string, msg any) {
+	if !c.opts.LogPayloads || !c.logger.enabled(LogLevelDebug) || msg == nil {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%+v", msg))
+	}
+	limit := c.opts.MaxPayloadSize
+	if limit <= 0 {
+		limit = 1024
+	}
+	fields := Fields{"direction": direction, "payload_size": len(data)}
+	if len(data) > limit {
+		data = data[:limit]
+		fields["payload_truncated"] = true
+	}
+	fields["payload"] = string(data)
+	c.logger.WithFields(fields).Debug("rpc payload")
+}
+
+// Finish logs the end of the RPC with its status code and duration. Codes
+// indicating a server-side failure (Unknown, DeadlineExceeded,
+// Unimplemented, Internal, Unavailable, DataLoss) are logged at Error
+// level, other failures at Warning and "OK" at Info.
+func (c *RPCCall) Finish(code string, err error) {
+	fields := Fields{"rpc_code": code, "duration": time.Since(c.start)}
+	if err != nil {
+		fields["error"] = err
+	}
+	l := c.logger.WithFields(fields)
+	switch code {
+	case "OK":
+		l.Info("rpc finished")
+	case "Unknown", "DeadlineExceeded", "Unimplemented", "Internal", "Unavailable", "DataLoss":
+		l.Error("rpc finished")
+	default:
+		l.Warning("rpc finished")
+	}
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRPCCall(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	ctx, call := logger.StartRPC(context.Background(), "server", "/users.v1.Users/Get", "10.0.0.1:5000", RPCOptions{LogPayloads: true, MaxPayloadSize: 10})
+	if FromContext(ctx) != call.logger {
+		t.Error("Expected the RPC logger in the context")
+	}
+	call.Payload("request", map[string]string{"id": "1234567890"})
+	call.Finish("NotFound", errors.New("no such user"))
+
+	var entries []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var e map[string]any
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("Failed to parse entry: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected start, payload and finish entries, got %d", len(entries))
+	}
+	payload, finish := entries[1], entries[2]
+	if payload["payload_truncated"] != true || len(payload["payload"].(string)) != 10 {
+		t.Errorf("Expected a truncated payload, got %v", payload)
+	}
+	if finish["level"] != "warn" || finish["rpc_code"] != "NotFound" || finish["peer"] != "10.0.0.1:5000" {
+		t.Errorf("Unexpected finish entry: %v", finish)
+	}
+}
+
+func TestRPCCallLevels(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	for code, level := range map[string]string{"OK": `"level":"info"`, "Internal": `"level":"error"`} {
+		buf.Reset()
+		_, call := logger.StartRPC(context.Background(), "client", "/svc/M", "", RPCOptions{LogPayloads: true})
+		call.Payload("request", "ignored at info")
+		call.Finish(code, nil)
+		if !strings.Contains(buf.String(), level) || strings.Contains(buf.String(), "payload") {
+			t.Errorf("%s: expected only a finish entry with %s, got %q", code, level, buf.Bytes())
+		}
+	}
+}
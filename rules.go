@@ -0,0 +1,62 @@
+package logging
+
+import "regexp"
+
+// LineRule rewrites the level of lines captured by a LineWriter whose text
+// matches Pattern, or drops them when Suppress is set.
+type LineRule struct {
+	// Pattern selects the lines the rule applies to.
+	Pattern *regexp.Regexp
+
+	// Level is the level matching lines are logged at.
+	Level LogLevel
+
+	// Suppress drops matching lines instead of logging them.
+	Suppress bool
+}
+
+// LevelRule returns a rule logging lines matching pattern at level. It
+// panics if pattern does not compile, like regexp.MustCompile.
+func LevelRule(pattern string, level LogLevel) LineRule {
+	return LineRule{Pattern: regexp.MustCompile(pattern), Level: level}
+}
+
+// SuppressRule returns a rule dropping lines matching pattern, such as
+// banners or progress output. It panics if pattern does not compile.
+func SuppressRule(pattern string) LineRule {
+	return LineRule{Pattern: regexp.MustCompile(pattern), Suppress: true}
+}
+
+// CommonLevelRules returns rules recognizing the level words most libraries
+// print: ERROR, FATAL, PANIC and CRIT at Error level, WARN and WARNING at
+// Warning, and DEBUG and TRACE at Debug. Lines with none of them keep the
+// writer's level.
+func CommonLevelRules() []LineRule {
+	return []LineRule{
+		LevelRule(`\b(ERROR|ERR|FATAL|PANIC|CRIT(ICAL)?)\b`, LogLevelError),
+		LevelRule(`\bWARN(ING)?\b`, LogLevelWarning),
+		LevelRule(`\b(DEBUG|TRACE)\b`, LogLevelDebug),
+	}
+}
+
+// WithLineRules makes a LineWriter apply rules to each plain-text line, the
+// first matching rule deciding its level or suppressing it. Legacy
+// libraries and child processes often write everything to one stream;
+// rules recover the level from the text:
+//
+//	stdlog.SetOutput(logging.NewLineWriter(logger, logging.LogLevelInfo,
+//		logging.WithLineRules(logging.CommonLevelRules()...)))
+func WithLineRules(rules ...LineRule) LineOption {
+	return func(w *LineWriter) { w.rules = append(w.rules, rules...) }
+}
+
+// applyRules returns the level line is logged at, or false if a rule
+// suppresses it.
+func (w *LineWriter) applyRules(line []byte) (LogLevel, bool) {
+	for _, r := range w.rules {
+		if r.Pattern.Match(line) {
+			return r.Level, !r.Suppress
+		}
+	}
+	return w.level, true
+}
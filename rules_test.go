@@ -0,0 +1,47 @@
+package logging
+
+import (
+	stdlog "log"
+	"testing"
+)
+
+func TestLineRulesRelevelAndSuppress(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	rules := append([]LineRule{SuppressRule(`^progress: `)}, CommonLevelRules()...)
+	std := stdlog.New(NewLineWriter(logger, LogLevelInfo, WithLineRules(rules...)), "", 0)
+
+	std.Print("ERROR: connection refused")
+	std.Print("progress: 40%")
+	std.Print("WARNING disk almost full")
+	std.Print("DEBUG cache miss")
+	std.Print("started")
+
+	entries := decodeEntries(t, buf.String())
+	want := []struct{ msg, level string }{
+		{"ERROR: connection refused", "error"},
+		{"WARNING disk almost full", "warn"},
+		{"started", "info"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %v", len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i]["message"] != w.msg || entries[i]["level"] != w.level {
+			t.Errorf("Entry %d: expected %q at %s, got %v", i, w.msg, w.level, entries[i])
+		}
+	}
+}
+
+func TestLineRulesFirstMatchWins(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	w := NewLineWriter(logger, LogLevelInfo, WithLineRules(
+		LevelRule(`timeout`, LogLevelWarning),
+		LevelRule(`ERROR`, LogLevelError),
+	))
+	_, _ = w.Write([]byte("ERROR timeout talking to db\n"))
+
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 1 || entries[0]["level"] != "warn" {
+		t.Errorf("Expected the first matching rule to decide the level, got %v", entries)
+	}
+}
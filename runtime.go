@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"os"
+	"strings"
+)
+
+// Runtime identifies the environment a process runs in.
+type Runtime int
+
+// Runtimes detected by DetectRuntime.
+const (
+	// RuntimeOther is any environment not recognized below, such as a
+	// container or service manager capturing stdout.
+	RuntimeOther Runtime = iota
+	// RuntimeTerminal means stdout is an interactive terminal.
+	RuntimeTerminal
+	// RuntimeKubernetes means the process runs in a Kubernetes pod.
+	RuntimeKubernetes
+	// RuntimeLambda means the process runs in AWS Lambda.
+	RuntimeLambda
+)
+
+// String returns the name of the runtime.
+func (r Runtime) String() string {
+	switch r {
+	case RuntimeTerminal:
+		return "terminal"
+	case RuntimeKubernetes:
+		return "kubernetes"
+	case RuntimeLambda:
+		return "lambda"
+	default:
+		return "other"
+	}
+}
+
+// DetectRuntime inspects the environment to tell where the process runs.
+func DetectRuntime() Runtime {
+	return detectRuntime(os.Getenv, isTerminal(os.Stdout))
+}
+
+func detectRuntime(getenv func(string) string, tty bool) Runtime {
+	switch {
+	case getenv("AWS_LAMBDA_FUNCTION_NAME") != "":
+		return RuntimeLambda
+	case getenv("KUBERNETES_SERVICE_HOST") != "":
+		return RuntimeKubernetes
+	case tty:
+		return RuntimeTerminal
+	default:
+		return RuntimeOther
+	}
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// serviceAccountNamespace is where Kubernetes mounts the pod's namespace.
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// runtimeFields returns the fields describing where the process runs.
+func runtimeFields(r Runtime, getenv func(string) string) Fields {
+	fields := Fields{}
+	set := func(key, value string) {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	switch r {
+	case RuntimeKubernetes:
+		pod := getenv("POD_NAME")
+		if pod == "" {
+			pod = getenv("HOSTNAME")
+		}
+		set("k8s_pod", pod)
+		ns := getenv("POD_NAMESPACE")
+		if ns == "" {
+			if data, err := os.ReadFile(serviceAccountNamespace); err == nil {
+				ns = strings.TrimSpace(string(data))
+			}
+		}
+		set("k8s_namespace", ns)
+		set("k8s_node", getenv("NODE_NAME"))
+	case RuntimeLambda:
+		set("lambda_function", getenv("AWS_LAMBDA_FUNCTION_NAME"))
+		set("lambda_version", getenv("AWS_LAMBDA_FUNCTION_VERSION"))
+		set("aws_region", getenv("AWS_REGION"))
+	}
+	return fields
+}
+
+// NewAuto returns a Logger configured for the detected runtime:
+//
+//   - terminal: colored console output
+//   - Kubernetes: JSON with the pod name, namespace and node as fields
+//   - Lambda: uncolored single-line JSON with the function name, version
+//     and region as fields
+//   - otherwise: JSON
+//
+// opts are applied after the detected defaults and override them.
+func NewAuto(opts ...Option) *Logger {
+	return newAuto(DetectRuntime(), os.Getenv, opts)
+}
+
+func newAuto(r Runtime, getenv func(string) string, opts []Option) *Logger {
+	o := defaultOptions()
+	if r != RuntimeTerminal {
+		o.format = FormatJSON
+		o.timeFormat = ""
+		o.color = new(bool)
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	logger := newLogger(&o)
+	if fields := runtimeFields(r, getenv); len(fields) > 0 {
+		logger = logger.WithFields(fields)
+	}
+	return logger
+}
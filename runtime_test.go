@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func fakeEnv(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestDetectRuntime(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		tty  bool
+		want Runtime
+	}{
+		{map[string]string{"AWS_LAMBDA_FUNCTION_NAME": "fn"}, true, RuntimeLambda},
+		{map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"}, true, RuntimeKubernetes},
+		{nil, true, RuntimeTerminal},
+		{nil, false, RuntimeOther},
+	}
+	for _, tt := range tests {
+		if got := detectRuntime(fakeEnv(tt.env), tt.tty); got != tt.want {
+			t.Errorf("detectRuntime(%v, %v) = %v, want %v", tt.env, tt.tty, got, tt.want)
+		}
+	}
+}
+
+func TestNewAutoKubernetes(t *testing.T) {
+	var buf bytes.Buffer
+	env := fakeEnv(map[string]string{
+		"KUBERNETES_SERVICE_HOST": "10.0.0.1",
+		"HOSTNAME":                "api-7d9f-xk2p",
+		"POD_NAMESPACE":           "payments",
+	})
+	logger := newAuto(RuntimeKubernetes, env, []Option{WithOutput(&buf)})
+
+	logger.Info("ready")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected JSON output: %v (%q)", err, buf.Bytes())
+	}
+	if entry["k8s_pod"] != "api-7d9f-xk2p" || entry["k8s_namespace"] != "payments" {
+		t.Errorf("Expected pod metadata, got %v", entry)
+	}
+	if _, ok := entry["k8s_node"]; ok {
+		t.Error("Unset metadata should be omitted")
+	}
+}
+
+func TestNewAutoLambda(t *testing.T) {
+	var buf bytes.Buffer
+	env := fakeEnv(map[string]string{
+		"AWS_LAMBDA_FUNCTION_NAME":    "checkout",
+		"AWS_LAMBDA_FUNCTION_VERSION": "$LATEST",
+	})
+	logger := newAuto(RuntimeLambda, env, []Option{WithOutput(&buf)})
+
+	logger.Info("invoked")
+	if bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("Expected a single line, got %q", buf.Bytes())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	if entry["lambda_function"] != "checkout" || entry["lambda_version"] != "$LATEST" {
+		t.Errorf("Expected function metadata, got %v", entry)
+	}
+}
+
+func TestNewAutoTerminal(t *testing.T) {
+	clearColorEnv(t)
+	var buf bytes.Buffer
+	logger := newAuto(RuntimeTerminal, fakeEnv(nil), []Option{WithOutput(&buf)})
+
+	logger.Info("hello")
+	if !bytes.Contains(buf.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected colored console output, got %q", buf.Bytes())
+	}
+}
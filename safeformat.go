@@ -0,0 +1,214 @@
+package logging
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxFormatWidth bounds the widths and precisions of format verbs. fmt
+// accepts up to a million, so a message such as "%999999d" taken from user
+// input would pad every entry to a megabyte.
+const maxFormatWidth = 4096
+
+// clampFormat returns format and v with every width and precision above
+// maxFormatWidth, written in format or passed as an argument for '*',
+// lowered to it. It returns them unchanged, without allocating, if there is
+// nothing to clamp.
+func clampFormat(format string, v []any) (string, []any) {
+	if len(v) == 0 {
+		return format, v // not formatted at all
+	}
+	var out strings.Builder
+	copied := false // whether v is a private copy
+	last := 0       // end of format already written to out
+	argNum := 0
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		// Argument indexes, a width and a precision may precede the verb,
+		// as in "%[2]*[1].*[3]d".
+	spec:
+		for i < len(format) {
+			switch c := format[i]; {
+			case c == '[':
+				end := strings.IndexByte(format[i:], ']')
+				if end < 0 {
+					break spec
+				}
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+					argNum = n - 1
+				}
+				i += end + 1
+			case c == '*':
+				if argNum >= 0 && argNum < len(v) {
+					if n, ok := formatInt(v[argNum]); ok && (n > maxFormatWidth || n < -maxFormatWidth) {
+						if !copied {
+							v = append([]any(nil), v...)
+							copied = true
+						}
+						v[argNum] = maxFormatWidth
+						if n < 0 {
+							v[argNum] = -maxFormatWidth
+						}
+					}
+				}
+				argNum++
+				i++
+			case c == '.':
+				i++
+			case c >= '0' && c <= '9':
+				start := i
+				for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+					i++
+				}
+				if formatNum(format[start:i]) > maxFormatWidth {
+					if last == 0 {
+						out.Grow(len(format))
+					}
+					out.WriteString(format[last:start])
+					out.WriteString(strconv.Itoa(maxFormatWidth))
+					last = i
+				}
+			default:
+				break spec
+			}
+		}
+		if i < len(format) {
+			if format[i] != '%' {
+				argNum++
+			}
+			_, size := utf8.DecodeRuneInString(format[i:])
+			i += size
+		}
+	}
+	return clampedResult(&out, format, last), v
+}
+
+// clampedResult returns the rewritten format: out followed by the rest of
+// format from last, or format itself if nothing was rewritten.
+func clampedResult(out *strings.Builder, format string, last int) string {
+	if last == 0 {
+		return format
+	}
+	out.WriteString(format[last:])
+	return out.String()
+}
+
+// formatNum parses a run of digits, stopping above maxFormatWidth.
+func formatNum(s string) int {
+	n := 0
+	for _, c := range []byte(s) {
+		if n = n*10 + int(c-'0'); n > maxFormatWidth {
+			break
+		}
+	}
+	return n
+}
+
+// formatInt returns the value of an integer argument used as a width or
+// precision, as fmt would read it.
+func formatInt(a any) (int64, bool) {
+	rv := reflect.ValueOf(a)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= maxFormatWidth {
+			return int64(u), true
+		}
+		return maxFormatWidth + 1, true
+	}
+	return 0, false
+}
+
+// cleanText reports whether s can be encoded as is: it is valid UTF-8 and
+// has no control characters that the encoder would write unescaped, which
+// would make the entry invalid JSON.
+func cleanText(s string) bool {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= utf8.RuneSelf {
+			ascii = false
+		} else if c < 0x20 && !escapedControl(c) {
+			return false
+		}
+	}
+	return ascii || utf8.ValidString(s)
+}
+
+// cleanBytes is cleanText for a byte slice.
+func cleanBytes(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 && !escapedControl(c) {
+			return false
+		}
+	}
+	return utf8.Valid(b)
+}
+
+// escapedControl reports whether the encoder escapes the control character
+// c itself.
+func escapedControl(c byte) bool {
+	switch c {
+	case '\b', '\t', '\n', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// appendCleanText appends b to dst with invalid UTF-8 and the control
+// characters the encoder would not escape replaced by U+FFFD.
+func appendCleanText(dst, b []byte) []byte {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		switch {
+		case r == utf8.RuneError && size == 1, r < 0x20 && !escapedControl(byte(r)):
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+		default:
+			dst = append(dst, b[:size]...)
+		}
+		b = b[size:]
+	}
+	return dst
+}
+
+// sanitizeText returns s, or a copy with the characters appendCleanText
+// replaces replaced, so user input cannot corrupt an entry.
+func sanitizeText(s string) string {
+	if cleanText(s) {
+		return s
+	}
+	return string(appendCleanText(make([]byte, 0, len(s)+8), []byte(s)))
+}
+
+// fieldKey returns key escaped for JSON, since the encoder writes keys as
+// they are.
+func fieldKey(key string) string {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c < 0x20 || c == '"' || c == '\\' || c >= utf8.RuneSelf {
+			b, _ := json.Marshal(key)
+			return string(b[1 : len(b)-1])
+		}
+	}
+	return key
+}
+
+// msgBuffers holds the buffers messages with arguments are formatted into.
+var msgBuffers = sync.Pool{New: func() any { return new(msgBuffer) }}
+
+type msgBuffer struct {
+	formatted, clean []byte
+}
+
+// maxPooledMessage bounds the buffers kept in msgBuffers.
+const maxPooledMessage = 64 << 10
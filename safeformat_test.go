@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestClampFormat(t *testing.T) {
+	tests := []struct {
+		format     string
+		args       []any
+		wantFormat string
+		wantArgs   []any
+	}{
+		{"%d items", []any{3}, "%d items", []any{3}},
+		{"%999999d", []any{1}, "%4096d", []any{1}},
+		{"%-0999999.999999f|%5d", []any{1.0, 2}, "%-04096.4096f|%5d", []any{1.0, 2}},
+		{"%.00001f", []any{1.0}, "%.00001f", []any{1.0}},
+		{"%*d %s", []any{999999, 1, "x"}, "%*d %s", []any{4096, 1, "x"}},
+		{"%-*d", []any{-999999, 1}, "%-*d", []any{-4096, 1}},
+		{"%.*f", []any{uint64(1 << 40), 1.0}, "%.*f", []any{4096, 1.0}},
+		{"%[2]*[1]d", []any{1, 50000}, "%[2]*[1]d", []any{1, 4096}},
+		{"%d%%%*d", []any{1, 99999, 2}, "%d%%%*d", []any{1, 4096, 2}},
+		{"%99999d", nil, "%99999d", nil},
+		{"%[1", []any{1}, "%[1", []any{1}},
+		{"%é%99999x", []any{1, 2}, "%é%4096x", []any{1, 2}},
+	}
+	for _, tt := range tests {
+		format, args := clampFormat(tt.format, tt.args)
+		if format != tt.wantFormat || fmt.Sprint(args) != fmt.Sprint(tt.wantArgs) {
+			t.Errorf("clampFormat(%q, %v) = %q, %v, want %q, %v", tt.format, tt.args, format, args, tt.wantFormat, tt.wantArgs)
+		}
+	}
+
+	args := []any{999999, 1}
+	clampFormat("%*d", args)
+	if args[0] != 999999 {
+		t.Error("Expected the caller's arguments left untouched")
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := map[string]string{
+		"plain":             "plain",
+		"tab\tnew\nline":    "tab\tnew\nline",
+		"caf\xc3\xa9":       "café",
+		"bad\xffbyte":       "bad�byte",
+		"nul\x00esc\x1b[0m": "nul�esc�[0m",
+		"cut \xe2\x82":      "cut ��",
+	}
+	for in, want := range tests {
+		if got := sanitizeText(in); got != want {
+			t.Errorf("sanitizeText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestHostileMessages(t *testing.T) {
+	var nilStringer *addrStringer
+	logger, buf := testLogger(LogLevelInfo)
+	logger.Info("nul\x00 %s", "bad\xff")
+	logger.Info("static \x01\xfe")
+	logger.Info("%999999d|%*s", 1, 999999, "x")
+	logger.Info("%s %v %d %!", nil, nilStringer, nil)
+	logger.WithFields(Fields{"k\x00": "v\x7f\xff"}).Info("fields")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 entries, got %d: %q", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !validEntry([]byte(line)) {
+			t.Errorf("Expected a valid JSON entry, got %q", line)
+		}
+		if strings.Contains(line, "recovered from panic") {
+			t.Errorf("Expected no panic, got %q", line)
+		}
+	}
+	if len(lines[2]) > 2*maxFormatWidth+200 {
+		t.Errorf("Expected widths clamped, got an entry of %d bytes", len(lines[2]))
+	}
+}
+
+// addrStringer has a String method that panics on a nil receiver.
+type addrStringer struct{ addr string }
+
+func (n *addrStringer) String() string { return n.addr }
+
+// validEntry reports whether line is a JSON object of valid UTF-8.
+func validEntry(line []byte) bool {
+	var m map[string]any
+	return utf8.Valid(line) && json.Unmarshal(line, &m) == nil
+}
+
+func FuzzLogMessage(f *testing.F) {
+	for _, seed := range []struct {
+		format, arg string
+		n           int
+	}{
+		{"user %s logged in", "alice", 1},
+		{"%999999d%v", "x", 3},
+		{"%*.*[1]s%[3]q", "\xff\x00", 1 << 30},
+		{"%!%-+# 0[9]%T%p", "<'\"\\>", -1},
+		{"\x00\x1b[31m\xc3", " ", 0},
+	} {
+		f.Add(seed.format, seed.arg, seed.n)
+	}
+	f.Fuzz(func(t *testing.T, format, arg string, n int) {
+		var buf bytes.Buffer
+		logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON))
+		logger.Info(format)
+		logger.Info(format, arg, n, nil, []byte(arg), (*addrStringer)(nil))
+		logger.WithFields(Fields{arg: arg}).Info(format, n)
+
+		lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 entries, got %d: %q", len(lines), buf.Bytes())
+		}
+		limit := (len(format) + 1) * (maxFormatWidth + 4*len(arg) + 64)
+		for _, line := range lines {
+			if !validEntry(line) {
+				t.Fatalf("Invalid entry %q", line)
+			}
+			if bytes.Contains(line, []byte("recovered from panic")) {
+				t.Fatalf("Logging panicked: %q", line)
+			}
+			if len(line) > limit {
+				t.Fatalf("Entry of %d bytes exceeds %d", len(line), limit)
+			}
+		}
+	})
+}
+
+func FuzzClampFormat(f *testing.F) {
+	f.Add("%5000d", 1)
+	f.Add("%[2]*[1].*[3]d", 1<<20)
+	f.Add("%-0.99999e%%", -1<<40)
+	f.Fuzz(func(t *testing.T, format string, n int) {
+		args := []any{n, n, n}
+		clamped, clampedArgs := clampFormat(format, args)
+		limit := (len(format) + 1) * (maxFormatWidth + 64)
+		if got := len(fmt.Sprintf(clamped, clampedArgs...)); got > limit {
+			t.Fatalf("clampFormat(%q) = %q, formatting to %d bytes", format, clamped, got)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether an entry at the given level should be emitted.
+// Implementations must be safe for concurrent use.
+type Sampler interface {
+	Sample(level LogLevel) bool
+}
+
+// SamplerFunc adapts an ordinary function to a Sampler.
+type SamplerFunc func(level LogLevel) bool
+
+// Sample calls f(level).
+func (f SamplerFunc) Sample(level LogLevel) bool {
+	return f(level)
+}
+
+// LevelSampler applies a different Sampler per level. Levels without an
+// entry are always emitted.
+type LevelSampler map[LogLevel]Sampler
+
+// Sample implements Sampler.
+func (s LevelSampler) Sample(level LogLevel) bool {
+	if sampler, ok := s[level]; ok {
+		return sampler.Sample(level)
+	}
+	return true
+}
+
+// EveryN returns a Sampler that keeps the first of every n entries.
+func EveryN(n uint64) Sampler {
+	return &everyN{n: n}
+}
+
+type everyN struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+func (s *everyN) Sample(LogLevel) bool {
+	if s.n <= 1 {
+		return true
+	}
+	return (s.count.Add(1)-1)%s.n == 0
+}
+
+// Probability returns a Sampler that keeps each entry with probability p,
+// where p is between 0 and 1.
+func Probability(p float64) Sampler {
+	return SamplerFunc(func(LogLevel) bool {
+		return rand.Float64() < p
+	})
+}
+
+// Burst returns a Sampler that keeps the first entries of each level in
+// every tick, then one in every thereafter, so bursts are thinned while
+// quiet periods are logged in full. A zero thereafter drops everything past
+// first until the next tick.
+func Burst(first, thereafter uint64, tick time.Duration) Sampler {
+	return &burst{first: first, thereafter: thereafter, tick: int64(max(tick, time.Nanosecond))}
+}
+
+type burst struct {
+	first, thereafter uint64
+	tick              int64
+	levels            [4]burstWindow
+}
+
+// burstWindow counts the entries of one level in the current tick.
+type burstWindow struct {
+	tick  atomic.Int64
+	count atomic.Uint64
+}
+
+func (s *burst) Sample(level LogLevel) bool {
+	w := &s.levels[levelIndex(level)]
+	if now := time.Now().UnixNano() / s.tick; w.tick.Load() != now && w.tick.Swap(now) != now {
+		w.count.Store(0)
+	}
+	n := w.count.Add(1)
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
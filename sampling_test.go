@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestEveryNSampling(t *testing.T) {
+	logger, buf := testLogger(LogLevelDebug)
+	logger.SetSampler(LevelSampler{
+		LogLevelDebug: EveryN(100),
+		LogLevelInfo:  EveryN(10),
+	})
+
+	for i := 0; i < 200; i++ {
+		logger.Debug("debug %d", i)
+		logger.Info("info %d", i)
+		logger.Error("error %d", i)
+	}
+
+	counts := map[string]int{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		entry, err := parseLogEntry(bytes.NewBuffer(line))
+		if err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		counts[entry.Level]++
+	}
+	if counts["debug"] != 2 {
+		t.Errorf("Expected 2 debug entries, got %d", counts["debug"])
+	}
+	if counts["info"] != 20 {
+		t.Errorf("Expected 20 info entries, got %d", counts["info"])
+	}
+	if counts["error"] != 200 {
+		t.Errorf("Expected all 200 error entries, got %d", counts["error"])
+	}
+}
+
+func TestProbabilitySampling(t *testing.T) {
+	testCases := []struct {
+		p        float64
+		min, max int
+	}{
+		{0, 0, 0},
+		{1, 1000, 1000},
+		{0.5, 350, 650},
+	}
+
+	for _, tc := range testCases {
+		s := Probability(tc.p)
+		kept := 0
+		for i := 0; i < 1000; i++ {
+			if s.Sample(LogLevelInfo) {
+				kept++
+			}
+		}
+		if kept < tc.min || kept > tc.max {
+			t.Errorf("Probability(%v) kept %d of 1000, expected between %d and %d", tc.p, kept, tc.min, tc.max)
+		}
+	}
+}
+
+func TestSampledDerivedLogger(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetSampler(SamplerFunc(func(LogLevel) bool { return false }))
+
+	logger.WithFields(Fields{"k": "v"}).Info("dropped")
+	if buf.Len() > 0 {
+		t.Error("Derived loggers should inherit the sampler")
+	}
+}
+
+func TestBurstSampling(t *testing.T) {
+	s := Burst(3, 5, time.Hour)
+	kept := 0
+	for i := 0; i < 23; i++ {
+		if s.Sample(LogLevelInfo) {
+			kept++
+		}
+	}
+	if kept != 3+4 {
+		t.Errorf("Expected the first 3 entries then one in 5, got %d", kept)
+	}
+	if !s.Sample(LogLevelDebug) {
+		t.Error("Expected each level to have its own burst")
+	}
+
+	s = Burst(1, 0, 10*time.Millisecond)
+	if !s.Sample(LogLevelInfo) || s.Sample(LogLevelInfo) {
+		t.Error("Expected one entry per tick")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !s.Sample(LogLevelInfo) {
+		t.Error("Expected the burst to reset on the next tick")
+	}
+}
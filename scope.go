@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Scope is a span-like unit of work, such as a database transaction,
+// started by BeginScope and ended by End.
+type Scope struct {
+	logger     *Logger
+	start      time.Time
+	statements atomic.Int64
+	ended      atomic.Bool
+}
+
+type scopeKey struct{}
+
+// BeginScope starts a scope named name, such as "transfer", and returns a
+// context carrying it. The scope's logger, also returned by FromContext on
+// that context, carries the scope name and a tx_id unique to this scope.
+// Statements run through a driver wrapped with WrapDriver or WrapConnector
+// with that context are counted and logged with the scope's fields.
+func BeginScope(ctx context.Context, l *Logger, name string) (context.Context, *Scope) {
+	s := &Scope{
+		logger: l.scoped(ctx).WithFields(Fields{"scope": name, "tx_id": newRunID()}),
+		start:  time.Now(),
+	}
+	s.logger.Debug("scope begun")
+	ctx = context.WithValue(ctx, scopeKey{}, s)
+	return NewContext(ctx, s.logger), s
+}
+
+func scopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// Logger returns the scope's logger.
+func (s *Scope) Logger() *Logger {
+	return s.logger
+}
+
+// AddStatement counts a statement run in the scope, for work not going
+// through a wrapped database/sql driver.
+func (s *Scope) AddStatement() {
+	s.statements.Add(1)
+}
+
+// Statements returns the number of statements run in the scope so far.
+func (s *Scope) Statements() int64 {
+	return s.statements.Load()
+}
+
+// End logs the outcome of the scope with its duration and statement
+// count: committed at Info level if err is nil, rolled back at Warning
+// level with err otherwise. It is meant to be deferred with the function's
+// named error result. Only the first call is logged.
+func (s *Scope) End(err error) {
+	if !s.ended.CompareAndSwap(false, true) {
+		return
+	}
+	fields := Fields{"duration": time.Since(s.start), "statements": s.statements.Load()}
+	if err != nil {
+		fields["error"] = err
+		s.logger.WithFields(fields).Warning("scope rolled back")
+		return
+	}
+	s.logger.WithFields(fields).Info("scope committed")
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestScopeCountsStatements(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	db := sql.OpenDB(WrapConnector(logger, fakeConnector{d: &fakeDriver{}}, SQLOptions{}))
+	defer db.Close()
+
+	ctx, scope := BeginScope(context.Background(), logger, "transfer")
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + ?", 10); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	scope.End(nil)
+	scope.End(errors.New("ignored"))
+
+	entries := decodeEntries(t, buf.String())
+	txID := entries[0]["tx_id"]
+	if txID == nil {
+		t.Fatalf("Expected statements logged with the scope's tx_id, got %v", entries[0])
+	}
+	for _, e := range entries {
+		if e["tx_id"] != txID || e["scope"] != "transfer" {
+			t.Errorf("Expected every entry tagged with the scope, got %v", e)
+		}
+	}
+	end := entries[len(entries)-1]
+	if end["message"] != "scope committed" || end["statements"] != float64(2) {
+		t.Errorf("Unexpected end entry: %v", end)
+	}
+	if scope.Statements() != 2 {
+		t.Errorf("Expected 2 statements, got %d", scope.Statements())
+	}
+}
+
+func TestScopeRollback(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	_, scope := BeginScope(context.Background(), logger, "import")
+	scope.AddStatement()
+	scope.End(errors.New("constraint violation"))
+
+	entries := decodeEntries(t, buf.String())
+	end := entries[len(entries)-1]
+	if end["message"] != "scope rolled back" || end["level"] != "warn" || end["error"] != "constraint violation" {
+		t.Errorf("Unexpected end entry: %v", end)
+	}
+}
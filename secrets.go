@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/phuslu/log"
+)
+
+// minSecretLength is the length below which registered secrets are
+// ignored, since masking every occurrence of a short string would mangle
+// unrelated text.
+const minSecretLength = 4
+
+// secretSet holds the registered secrets and a replacer masking them.
+type secretSet struct {
+	mu       sync.Mutex
+	secrets  map[string]bool
+	replacer atomic.Pointer[strings.Replacer]
+}
+
+// add registers secrets and rebuilds the replacer.
+func (s *secretSet) add(secrets []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.secrets == nil {
+		s.secrets = make(map[string]bool)
+	}
+	for _, secret := range secrets {
+		if len(secret) < minSecretLength {
+			continue
+		}
+		s.secrets[secret] = true
+		// Entries are JSON, so the secret may appear escaped.
+		if quoted, err := json.Marshal(secret); err == nil {
+			s.secrets[string(quoted[1:len(quoted)-1])] = true
+		}
+	}
+	pairs := make([]string, 0, 2*len(s.secrets))
+	for secret := range s.secrets {
+		pairs = append(pairs, secret, Redacted)
+	}
+	s.replacer.Store(strings.NewReplacer(pairs...))
+}
+
+// mask returns s with the secrets in s masked. A nil set masks nothing.
+func (s *secretSet) mask(str string) string {
+	if s == nil {
+		return str
+	}
+	if r := s.replacer.Load(); r != nil {
+		return r.Replace(str)
+	}
+	return str
+}
+
+// secretWriter masks registered secrets in the encoded entries it passes
+// to Writer.
+type secretWriter struct {
+	Writer  log.Writer
+	secrets *secretSet
+}
+
+func (w *secretWriter) WriteEntry(e *log.Entry) (int, error) {
+	r := w.secrets.replacer.Load()
+	if r == nil {
+		return w.Writer.WriteEntry(e)
+	}
+	b := getEntryBuffer()
+	defer putEntryBuffer(b)
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+	masked := r.Replace(string(*b))
+	if masked == string(*b) {
+		return w.Writer.WriteEntry(e)
+	}
+	c := log.NewContext([]byte(masked))
+	c.Level = e.Level
+	return w.Writer.WriteEntry(c)
+}
+
+func (w *secretWriter) Flush() error { return flushWriter(w.Writer) }
+func (w *secretWriter) Close() error { return closeWriter(w.Writer) }
+
+// RegisterSecrets masks every occurrence of secrets, such as API keys
+// loaded at startup, in the entries of l and every logger sharing its
+// writer, wherever they appear: in messages, in fields or deep inside a
+// config struct logged with %v. Values shorter than four bytes are
+// ignored.
+//
+// The first call wraps the writer and should happen before the logger is
+// shared between goroutines; later calls, for example after rotating a
+// key, are safe at any time. Scanning copies every entry.
+func (l *Logger) RegisterSecrets(secrets ...string) {
+	sw, ok := l.logger.Writer.(*secretWriter)
+	if !ok {
+		sw = &secretWriter{Writer: l.writer(), secrets: new(secretSet)}
+		l.logger.Writer = sw
+	}
+	sw.secrets.add(secrets)
+}
+
+// secrets returns the secrets registered on the writer of l, or nil.
+func (l *Logger) secrets() *secretSet {
+	var set *secretSet
+	walkWriters(l.logger.Writer, func(w any) {
+		if sw, ok := w.(*secretWriter); ok && set == nil {
+			set = sw.secrets
+		}
+	})
+	return set
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegisterSecretsMasksLeaks(t *testing.T) {
+	type config struct {
+		Endpoint string
+		APIKey   string
+	}
+	logger, buf := testLogger(LogLevelInfo)
+	logger.RegisterSecrets("sk_live_51Hx", "ab")
+	cfg := config{Endpoint: "https://api.example.com", APIKey: "sk_live_51Hx"}
+
+	logger.WithFields(Fields{"header": "Bearer sk_live_51Hx"}).Info("loaded config %+v", cfg)
+	logger.RegisterSecrets(`pa"ss\word`)
+	logger.Warning("retrying with %s", `pa"ss\word`)
+
+	out := buf.String()
+	if strings.Contains(out, "sk_live_51Hx") || strings.Contains(out, `ss\\word`) {
+		t.Errorf("Expected registered secrets masked, got %q", out)
+	}
+	entries := decodeEntries(t, out)
+	if entries[0]["header"] != "Bearer "+Redacted || !strings.Contains(entries[0]["message"].(string), "APIKey:"+Redacted) {
+		t.Errorf("Expected the secret masked in fields and messages, got %v", entries[0])
+	}
+	if entries[1]["message"] != "retrying with "+Redacted || entries[1]["level"] != "warn" {
+		t.Errorf("Expected a secret needing JSON escapes masked, got %v", entries[1])
+	}
+	if !strings.Contains(fmt.Sprint(entries[0]["message"]), "api.example.com") {
+		t.Error("Short registered values should be ignored")
+	}
+}
@@ -0,0 +1,51 @@
+package logging
+
+import "context"
+
+// Security event types, logged in the "event" field of every security
+// event so they can be matched across services.
+const (
+	EventAuthSuccess     = "auth_success"
+	EventAuthFailure     = "auth_failure"
+	EventPrivilegeChange = "privilege_change"
+	EventDataExport      = "data_export"
+)
+
+// securityEvent logs a security event of type event at level, with the
+// fields common to all of them: "category" is "security", "event" is the
+// type, and the message is "security: " followed by the type.
+func securityEvent(ctx context.Context, l *Logger, level LogLevel, event string, fields Fields) {
+	fields["category"] = "security"
+	fields["event"] = event
+	sl := l.scoped(ctx).WithFields(fields)
+	switch level {
+	case LogLevelWarning:
+		sl.Warning("security: %s", event)
+	default:
+		sl.Info("security: %s", event)
+	}
+}
+
+// AuthSuccess logs at Info level that user authenticated with method, such
+// as "password", "oidc" or "api_key".
+func AuthSuccess(ctx context.Context, l *Logger, user, method string) {
+	securityEvent(ctx, l, LogLevelInfo, EventAuthSuccess, Fields{"user": user, "auth_method": method})
+}
+
+// AuthFailure logs at Warning level that user failed to authenticate with
+// method, and why, such as "bad_password" or "expired_token".
+func AuthFailure(ctx context.Context, l *Logger, user, method, reason string) {
+	securityEvent(ctx, l, LogLevelWarning, EventAuthFailure, Fields{"user": user, "auth_method": method, "reason": reason})
+}
+
+// PrivilegeChange logs at Warning level that actor changed the role of
+// target from one role to another; an empty role means none.
+func PrivilegeChange(ctx context.Context, l *Logger, actor, target, from, to string) {
+	securityEvent(ctx, l, LogLevelWarning, EventPrivilegeChange, Fields{"actor": actor, "target_user": target, "old_role": from, "new_role": to})
+}
+
+// DataExport logs at Warning level that actor exported records entries of
+// dataset to destination, such as a file name or a remote system.
+func DataExport(ctx context.Context, l *Logger, actor, dataset string, records int, destination string) {
+	securityEvent(ctx, l, LogLevelWarning, EventDataExport, Fields{"actor": actor, "dataset": dataset, "records": records, "destination": destination})
+}
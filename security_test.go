@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSecurityEvents(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	ctx := NewContext(context.Background(), logger.WithFields(Fields{"request_id": "r1"}))
+	AuthSuccess(ctx, logger, "alice", "oidc")
+	AuthFailure(ctx, logger, "bob", "password", "bad_password")
+	PrivilegeChange(ctx, logger, "alice", "bob", "viewer", "admin")
+	DataExport(ctx, logger, "alice", "customers", 1200, "s3://exports/customers.csv")
+
+	tests := []struct {
+		level, event string
+		fields       Fields
+	}{
+		{"info", EventAuthSuccess, Fields{"user": "alice", "auth_method": "oidc"}},
+		{"warn", EventAuthFailure, Fields{"user": "bob", "reason": "bad_password"}},
+		{"warn", EventPrivilegeChange, Fields{"actor": "alice", "target_user": "bob", "old_role": "viewer", "new_role": "admin"}},
+		{"warn", EventDataExport, Fields{"dataset": "customers", "records": float64(1200), "destination": "s3://exports/customers.csv"}},
+	}
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != len(tests) {
+		t.Fatalf("Expected %d entries, got %d", len(tests), len(entries))
+	}
+	for i, tt := range tests {
+		e := entries[i]
+		if e["level"] != tt.level || e["event"] != tt.event || e["category"] != "security" || e["message"] != "security: "+tt.event {
+			t.Errorf("%s: unexpected entry %v", tt.event, e)
+		}
+		if e["request_id"] != "r1" {
+			t.Errorf("%s: expected the context logger's fields, got %v", tt.event, e)
+		}
+		for k, v := range tt.fields {
+			if e[k] != v {
+				t.Errorf("%s: expected %s=%v, got %v", tt.event, k, v, e[k])
+			}
+		}
+	}
+}
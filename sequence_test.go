@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestSequenceField(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetSequence(true)
+	child := logger.WithFields(Fields{"component": "db"})
+
+	logger.Info("first")
+	child.Info("second")
+
+	var prev float64
+	for i, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		seq, ok := entry["seq"].(float64)
+		if !ok {
+			t.Fatalf("Entry %d has no seq field: %v", i, entry)
+		}
+		if seq <= prev {
+			t.Errorf("Expected increasing seq, got %v after %v", seq, prev)
+		}
+		prev = seq
+	}
+}
+
+// TestPerGoroutineOrder checks that every writer chain in the package keeps
+// the entries of each goroutine in order.
+func TestPerGoroutineOrder(t *testing.T) {
+	chains := map[string]func(sink *syncBuffer) log.Writer{
+		"async": func(sink *syncBuffer) log.Writer {
+			return NewAsyncWriter(log.IOWriter{Writer: sink}, 8)
+		},
+		"batch": func(sink *syncBuffer) log.Writer {
+			return NewBatchWriter(IOBatchSink{sink}, 7, time.Millisecond)
+		},
+		"dedup": func(sink *syncBuffer) log.Writer {
+			return NewDedupWriter(log.IOWriter{Writer: sink}, time.Millisecond)
+		},
+		"coalescing": func(sink *syncBuffer) log.Writer {
+			return NewCoalescingWriter(sink)
+		},
+		"sharded": func(sink *syncBuffer) log.Writer {
+			return NewShardedWriter(log.IOWriter{Writer: sink}, 4)
+		},
+		"async+dedup+batch": func(sink *syncBuffer) log.Writer {
+			return NewAsyncWriter(NewDedupWriter(NewBatchWriter(IOBatchSink{sink}, 5, time.Millisecond), time.Millisecond), 8)
+		},
+	}
+	for name, chain := range chains {
+		t.Run(name, func(t *testing.T) {
+			sink := new(syncBuffer)
+			logger, _ := testLogger(LogLevelInfo)
+			logger.SetWriter(chain(sink))
+			logger.SetSequence(true)
+
+			const goroutines, perGoroutine = 4, 100
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					child := logger.WithFields(Fields{"g": g})
+					for i := 0; i < perGoroutine; i++ {
+						if i%10 == 0 {
+							child.Error("entry %d", i)
+						} else {
+							child.Info("entry %d", i)
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			lastSeq := make([]float64, goroutines)
+			lines := bytes.Split(bytes.TrimSpace(sink.Bytes()), []byte("\n"))
+			if len(lines) != goroutines*perGoroutine {
+				t.Fatalf("Expected %d entries, got %d", goroutines*perGoroutine, len(lines))
+			}
+			for _, line := range lines {
+				var entry struct {
+					G   int     `json:"g"`
+					Seq float64 `json:"seq"`
+				}
+				if err := json.Unmarshal(line, &entry); err != nil {
+					t.Fatalf("Failed to parse log entry: %v", err)
+				}
+				if entry.Seq <= lastSeq[entry.G] {
+					t.Fatalf("Goroutine %d entries out of order: seq %v after %v", entry.G, entry.Seq, lastSeq[entry.G])
+				}
+				lastSeq[entry.G] = entry.Seq
+			}
+		})
+	}
+}
+
+func TestWithSequence(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), WithSequence(true))
+	for range 5 {
+		logger.Info("tick")
+	}
+	logger.WithFields(Fields{"n": 1}).Info("tock")
+
+	report, err := CheckSequence(&buf)
+	if err != nil {
+		t.Fatalf("CheckSequence failed: %v", err)
+	}
+	if report.Entries != 6 || report.Last-report.First != 5 || !report.OK() {
+		t.Errorf("Expected 6 consecutive entries, got %+v", report)
+	}
+}
+
+func TestCheckSequence(t *testing.T) {
+	input := strings.Join([]string{
+		`{"seq":10,"message":"a"}`,
+		`{"seq":11,"message":"b"}`,
+		`not json`,
+		`{"message":"unsequenced"}`,
+		`{"seq":14,"message":"c"}`,
+		`{"seq":13,"message":"d"}`,
+		`{"seq":14,"message":"c again"}`,
+		`{"seq":20,"message":"e"}`,
+	}, "\n")
+	report, err := CheckSequence(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("CheckSequence failed: %v", err)
+	}
+	want := &SequenceReport{
+		Entries:    6,
+		First:      10,
+		Last:       20,
+		Gaps:       []SequenceGap{{After: 11, Before: 13}, {After: 14, Before: 20}},
+		Reordered:  []int{6},
+		Duplicates: []int{7},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Expected %+v, got %+v", want, report)
+	}
+	if report.OK() {
+		t.Error("Expected the report not OK")
+	}
+	if n := report.Gaps[1].Missing(); n != 5 {
+		t.Errorf("Expected 5 entries missing, got %d", n)
+	}
+
+	empty, err := CheckSequence(strings.NewReader(""))
+	if err != nil || empty.Entries != 0 || !empty.OK() {
+		t.Errorf("Expected an empty OK report, got %+v, %v", empty, err)
+	}
+}
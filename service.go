@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"sort"
+)
+
+// LevelService implements the GetLevel, SetLevel and ListLoggers RPCs of
+// the logging.v1.LevelService defined in proto/levels.proto. It does not
+// depend on gRPC: RegisterLevelService in the logginggrpc module serves it
+// on a grpc.Server, with the messages generated from the proto file.
+type LevelService struct {
+	logger *Logger
+}
+
+// NewLevelService returns a LevelService managing the levels of l and its
+// named loggers.
+func NewLevelService(l *Logger) *LevelService {
+	return &LevelService{logger: l}
+}
+
+// LoggerLevel is the level of one logger. An empty Logger is the root
+// logger.
+type LoggerLevel struct {
+	Logger string `json:"logger"`
+	Level  string `json:"level"`
+}
+
+// GetLevel returns the effective level of the named logger, or of the root
+// logger if name is empty.
+func (s *LevelService) GetLevel(ctx context.Context, name string) (LoggerLevel, error) {
+	n := &s.logger.state.tree.names
+	n.mu.Lock()
+	level := LogLevel(n.root.level.Load())
+	if name != "" {
+		level = n.resolve(name)
+	}
+	n.mu.Unlock()
+	return LoggerLevel{Logger: name, Level: level.String()}, nil
+}
+
+// SetLevel sets the level of the named logger, or of the root logger if
+// name is empty, and returns the result.
+func (s *LevelService) SetLevel(ctx context.Context, name, level string) (LoggerLevel, error) {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return LoggerLevel{}, err
+	}
+	s.logger.state.tree.names.setLevel(name, l)
+	return LoggerLevel{Logger: name, Level: l.String()}, nil
+}
+
+// ListLoggers returns the root level followed by the levels of all named
+// loggers, sorted by name.
+func (s *LevelService) ListLoggers(ctx context.Context) ([]LoggerLevel, error) {
+	root, named := s.logger.state.tree.names.snapshot()
+	levels := make([]LoggerLevel, 0, len(named)+1)
+	levels = append(levels, LoggerLevel{Level: root.String()})
+	for name, level := range named {
+		levels = append(levels, LoggerLevel{Logger: name, Level: level.String()})
+	}
+	sort.Slice(levels[1:], func(i, j int) bool { return levels[i+1].Logger < levels[j+1].Logger })
+	return levels, nil
+}
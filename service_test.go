@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestLevelService(t *testing.T) {
+	ctx := context.Background()
+	logger, _ := testLogger(LogLevelInfo)
+	http := logger.Named("server").Named("http")
+	svc := NewLevelService(logger)
+
+	if _, err := svc.SetLevel(ctx, "server", "debug"); err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	if http.Level() != LogLevelDebug {
+		t.Errorf("Descendants should inherit the new level, got %v", http.Level())
+	}
+	got, err := svc.GetLevel(ctx, "server.http")
+	if err != nil || got.Level != "debug" {
+		t.Errorf("Expected debug, got %+v (%v)", got, err)
+	}
+	if _, err := svc.SetLevel(ctx, "", "loud"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+
+	list, err := svc.ListLoggers(ctx)
+	if err != nil {
+		t.Fatalf("ListLoggers failed: %v", err)
+	}
+	want := []LoggerLevel{
+		{Logger: "", Level: "info"},
+		{Logger: "server", Level: "debug"},
+		{Logger: "server.http", Level: "debug"},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("Expected %+v, got %+v", want, list)
+	}
+}
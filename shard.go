@@ -0,0 +1,173 @@
+package logging
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// ShardedWriter spreads entries across several independently locked
+// buffers to reduce lock contention under heavy concurrency. A background
+// flusher merges the shards in sequence order and writes them to Writer
+// every Interval.
+//
+// Ordering: each goroutine always writes to the same shard, so entries from
+// one goroutine reach Writer in the order they were logged. Entries from
+// different goroutines are ordered by a global sequence number within a
+// flush, but an entry that loses a race with the flusher may be written in
+// the next flush, after entries that were logged slightly later. Entries
+// also reach Writer up to Interval after they are logged.
+type ShardedWriter struct {
+	// Writer is the destination of merged entries.
+	Writer log.Writer
+
+	// Shards is the number of buffers. Defaults to GOMAXPROCS.
+	Shards int
+
+	// Interval is how often the shards are merged and flushed. Defaults to 10ms.
+	Interval time.Duration
+
+	once    sync.Once
+	shards  []writerShard
+	seq     atomic.Uint64
+	flushMu sync.Mutex
+	merged  []shardEntry
+	closed  atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type writerShard struct {
+	mu      sync.Mutex
+	entries []shardEntry
+	_       [64]byte // keep shards on separate cache lines
+}
+
+type shardEntry struct {
+	seq   uint64
+	level log.Level
+	buf   *entryBuffer
+}
+
+// NewShardedWriter returns a ShardedWriter writing to w through n shards.
+func NewShardedWriter(w log.Writer, n int) *ShardedWriter {
+	return &ShardedWriter{Writer: w, Shards: n}
+}
+
+func (w *ShardedWriter) start() {
+	n := w.Shards
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	w.shards = make([]writerShard, n)
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(interval)
+}
+
+func (w *ShardedWriter) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = w.flush()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// WriteEntry implements log.Writer.
+func (w *ShardedWriter) WriteEntry(e *log.Entry) (int, error) {
+	w.once.Do(w.start)
+	if w.closed.Load() {
+		return 0, ErrWriterClosed
+	}
+
+	b := getEntryBuffer()
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+	n := len(*b)
+
+	s := &w.shards[uint64(log.Goid())%uint64(len(w.shards))]
+	s.mu.Lock()
+	s.entries = append(s.entries, shardEntry{seq: w.seq.Add(1), level: e.Level, buf: b})
+	s.mu.Unlock()
+	return n, nil
+}
+
+func (w *ShardedWriter) buffered() int {
+	n := 0
+	for i := range w.shards {
+		s := &w.shards[i]
+		s.mu.Lock()
+		n += len(s.entries)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// Flush merges and writes all buffered entries, then flushes Writer if it
+// buffers entries itself.
+func (w *ShardedWriter) Flush() error {
+	w.once.Do(w.start)
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return flushWriter(w.Writer)
+}
+
+func (w *ShardedWriter) flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	merged := w.merged[:0]
+	for i := range w.shards {
+		s := &w.shards[i]
+		s.mu.Lock()
+		merged = append(merged, s.entries...)
+		clear(s.entries)
+		s.entries = s.entries[:0]
+		s.mu.Unlock()
+	}
+	slices.SortFunc(merged, func(a, b shardEntry) int { return cmp.Compare(a.seq, b.seq) })
+
+	var err error
+	for i, se := range merged {
+		e := log.NewContext(*se.buf)
+		e.Level = se.level
+		if _, werr := w.Writer.WriteEntry(e); werr != nil && err == nil {
+			err = werr
+		}
+		putEntryBuffer(se.buf)
+		merged[i] = shardEntry{}
+	}
+	w.merged = merged[:0]
+	return err
+}
+
+// Close stops the flusher, writes remaining entries and closes Writer if it is
+// closable.
+func (w *ShardedWriter) Close() error {
+	w.once.Do(w.start)
+	if w.closed.Swap(true) {
+		return nil
+	}
+	close(w.stop)
+	<-w.done
+	err := w.flush()
+	if cerr := closeWriter(w.Writer); err == nil {
+		err = cerr
+	}
+	return err
+}
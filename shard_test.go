@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestShardedWriterPerGoroutineOrder(t *testing.T) {
+	sink := new(syncBuffer)
+	w := &ShardedWriter{Writer: log.IOWriter{Writer: sink}, Shards: 4, Interval: time.Millisecond}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(w)
+
+	const goroutines, perGoroutine = 8, 200
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				logger.Info("g%d-%d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	next := make([]int, goroutines)
+	lines := bytes.Split(bytes.TrimSpace(sink.Bytes()), []byte("\n"))
+	if len(lines) != goroutines*perGoroutine {
+		t.Fatalf("Expected %d entries, got %d", goroutines*perGoroutine, len(lines))
+	}
+	for _, line := range lines {
+		entry, err := parseLogEntry(bytes.NewBuffer(line))
+		if err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		var g, i int
+		if _, err := fmt.Sscanf(strings.Replace(entry.Message, "-", " ", 1), "g%d %d", &g, &i); err != nil {
+			t.Fatalf("Unexpected message %q", entry.Message)
+		}
+		if i != next[g] {
+			t.Fatalf("Goroutine %d entries out of order: expected %d, got %d", g, next[g], i)
+		}
+		next[g]++
+	}
+}
+
+func TestShardedWriterFlush(t *testing.T) {
+	sink := new(syncBuffer)
+	w := NewShardedWriter(log.IOWriter{Writer: sink}, 2)
+	w.Interval = time.Hour
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(w)
+
+	logger.Info("first")
+	logger.Info("second")
+	if sink.Len() > 0 {
+		t.Error("Entries should stay buffered until the flusher runs")
+	}
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	out := sink.Bytes()
+	if i, j := bytes.Index(out, []byte("first")), bytes.Index(out, []byte("second")); i < 0 || j < i {
+		t.Errorf("Expected entries in sequence order, got %q", out)
+	}
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+)
+
+// ErrSignalsUnsupported is returned by HandleSignals on platforms without
+// SIGUSR1 and SIGUSR2.
+var ErrSignalsUnsupported = errors.New("logging: verbosity signals are not supported on this platform")
+
+// HandleSignals adjusts the level of l from process signals until ctx is
+// done: SIGUSR1 makes the logger one level more verbose (error, warning,
+// info, debug) and SIGUSR2 one level less, so a live process can be
+// debugged with kill -USR1 <pid>. Each change is logged at Warning level.
+// Named loggers without a level of their own follow the change.
+//
+// HandleSignals returns immediately; it reports ErrSignalsUnsupported on
+// platforms without these signals, such as Windows.
+func (l *Logger) HandleSignals(ctx context.Context) error {
+	if verbositySignals == nil {
+		return ErrSignalsUnsupported
+	}
+	ch := make(chan os.Signal, 4)
+	signal.Notify(ch, verbositySignals[0], verbositySignals[1])
+	handled := signalHandled
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				if sig == verbositySignals[0] {
+					l.stepLevel(-1)
+				} else {
+					l.stepLevel(1)
+				}
+				handled()
+			}
+		}
+	}()
+	return nil
+}
+
+// signalHandled is called after each verbosity signal is handled. Tests
+// replace it to wait for signals that leave the level unchanged.
+var signalHandled = func() {}
+
+// stepLevel moves the level of l by delta, staying within Debug and Error.
+// The step is a compare-and-swap, so signals handled at once each take
+// effect.
+func (l *Logger) stepLevel(delta int) {
+	var from, to LogLevel
+	for {
+		from = l.Level()
+		to = min(max(from+LogLevel(delta), LogLevelDebug), LogLevelError)
+		if to == from {
+			return
+		}
+		if l.state.level.CompareAndSwap(int32(from), int32(to)) {
+			break
+		}
+	}
+	// Record the level for named loggers inheriting it, reading it again
+	// since a concurrent step may have moved it on.
+	n := &l.state.tree.names
+	n.mu.Lock()
+	n.setLevelLocked(l.name, l.Level())
+	n.mu.Unlock()
+	l.WithFields(Fields{"from": from.String(), "to": to.String()}).Warning("logging: level changed by signal")
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package logging
+
+import "os"
+
+var verbositySignals []os.Signal
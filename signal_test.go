@@ -0,0 +1,89 @@
+//go:build unix
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHandleSignals(t *testing.T) {
+	handled := make(chan struct{}, 8)
+	signalHandled = func() { handled <- struct{}{} }
+	defer func() { signalHandled = func() {} }()
+
+	buf := new(syncBuffer)
+	logger := NewLogger(LogLevelWarning, WithOutput(buf), WithFormat(FormatJSON))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := logger.HandleSignals(ctx); err != nil {
+		t.Fatalf("HandleSignals failed: %v", err)
+	}
+	// send waits for each signal to be handled, since signals of different
+	// numbers are not delivered in order and a clamped one changes nothing
+	// to wait for.
+	send := func(sig syscall.Signal) {
+		t.Helper()
+		if err := syscall.Kill(syscall.Getpid(), sig); err != nil {
+			t.Fatalf("Failed to signal: %v", err)
+		}
+		select {
+		case <-handled:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for %v to be handled", sig)
+		}
+	}
+
+	for _, want := range []LogLevel{LogLevelInfo, LogLevelDebug, LogLevelDebug} {
+		send(syscall.SIGUSR1)
+		if logger.Level() != want {
+			t.Fatalf("Expected SIGUSR1 to set the level to %v, got %v", want, logger.Level())
+		}
+	}
+	send(syscall.SIGUSR2)
+	if logger.Level() != LogLevelInfo {
+		t.Errorf("Expected SIGUSR2 to lower verbosity, got %v", logger.Level())
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("changed by signal")); n != 3 {
+		t.Errorf("Expected 3 level changes logged, got %d: %s", n, buf.Bytes())
+	}
+}
+
+func TestStepLevelClamps(t *testing.T) {
+	logger, buf := testLogger(LogLevelError)
+	logger.stepLevel(1)
+	if logger.Level() != LogLevelError {
+		t.Errorf("Expected the level to stay at error, got %v", logger.Level())
+	}
+	if buf.Len() != 0 {
+		t.Errorf("No change should be logged, got %q", buf.Bytes())
+	}
+	named := logger.Named("db")
+	logger.stepLevel(-1)
+	if named.Level() != LogLevelWarning {
+		t.Errorf("Named loggers should follow the change, got %v", named.Level())
+	}
+}
+
+func TestStepLevelConcurrent(t *testing.T) {
+	for range 100 {
+		logger := NewLogger(LogLevelError, WithOutput(new(syncBuffer)))
+		named := logger.Named("db")
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				logger.stepLevel(-1)
+			}()
+		}
+		wg.Wait()
+		if logger.Level() != LogLevelDebug || named.Level() != LogLevelDebug {
+			t.Fatalf("Expected three steps to reach debug, got %v (db %v)", logger.Level(), named.Level())
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// verbositySignals holds the signals raising and lowering verbosity.
+var verbositySignals = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}
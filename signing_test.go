@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSigningWriterSignsEntries(t *testing.T) {
+	key := []byte("deployment-key")
+	var buf bytes.Buffer
+	logger := NewLogger(WithOutput(&buf), WithFormat(FormatJSON), WithSigning(key))
+	logger.WithFields(Fields{"user": "alice"}).Info("payment accepted")
+	logger.Warning("disk at %d%%", 91)
+
+	lines := strings.SplitAfter(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %q", buf.String())
+	}
+	entries := decodeEntries(t, buf.String())
+	if entries[1]["level"] != "warn" || len(entries[0][SignatureField].(string)) != 64 {
+		t.Errorf("Expected signed entries keeping their level, got %v", entries)
+	}
+	for _, line := range lines {
+		if err := VerifySignature(key, []byte(line)); err != nil {
+			t.Errorf("Expected a valid signature on %q, got %v", line, err)
+		}
+	}
+
+	forged := strings.Replace(lines[0], "alice", "mallory", 1)
+	if err := VerifySignature(key, []byte(forged)); err != ErrBadSignature {
+		t.Errorf("Expected a modified entry to fail verification, got %v", err)
+	}
+	if err := VerifySignature([]byte("other-key"), []byte(lines[0])); err != ErrBadSignature {
+		t.Errorf("Expected the wrong key to fail verification, got %v", err)
+	}
+	if err := VerifySignature(key, []byte(`{"level":"info","message":"unsigned"}`)); err != ErrBadSignature {
+		t.Errorf("Expected an unsigned entry to fail verification, got %v", err)
+	}
+}
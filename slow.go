@@ -0,0 +1,36 @@
+package logging
+
+import "time"
+
+// Slow runs fn and logs a warning with its duration if it takes longer
+// than threshold, naming the operation op. The error of fn, if any, is
+// included in the entry and returned unchanged:
+//
+//	err := logging.Slow(l, "db.query", 200*time.Millisecond, func() error {
+//		return db.QueryRowContext(ctx, q, id).Scan(&user)
+//	})
+func Slow(l *Logger, op string, threshold time.Duration, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	logSlow(l, op, threshold, time.Since(start), err)
+	return err
+}
+
+// SlowValue is Slow for operations returning a value.
+func SlowValue[T any](l *Logger, op string, threshold time.Duration, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	v, err := fn()
+	logSlow(l, op, threshold, time.Since(start), err)
+	return v, err
+}
+
+func logSlow(l *Logger, op string, threshold, d time.Duration, err error) {
+	if d <= threshold || !l.enabled(LogLevelWarning) {
+		return
+	}
+	fields := Fields{"op": op, "duration": d, "threshold": threshold}
+	if err != nil {
+		fields["error"] = err
+	}
+	l.WithFields(fields).Warning("slow operation %s", op)
+}
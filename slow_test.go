@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSlowLogsOverThreshold(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	wantErr := errors.New("timeout")
+	err := Slow(logger, "db.query", time.Millisecond, func() error {
+		time.Sleep(5 * time.Millisecond)
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("Expected the error of fn, got %v", err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+	if entry["level"] != "warn" || entry["op"] != "db.query" || entry["error"] != "timeout" {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+}
+
+func TestSlowValueQuiet(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	v, err := SlowValue(logger, "cache.get", time.Hour, func() (int, error) { return 42, nil })
+	if v != 42 || err != nil {
+		t.Errorf("Expected the result of fn, got %v, %v", v, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Fast operations should not be logged, got %q", buf.Bytes())
+	}
+}
@@ -0,0 +1,500 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// spoolHeader is the size of a record header: payload length and CRC-32.
+const spoolHeader = 8
+
+// SpoolWriter is a log.Writer that appends entries to segment files in Dir
+// and delivers them to Sink from a background goroutine, so entries survive
+// process restarts and collector outages. Entries left in the spool are
+// delivered by the next SpoolWriter opened on the same directory:
+//
+//	spool := logging.NewSpoolWriter("/var/spool/app-logs", logging.NewRetrySink(collector, policy))
+//	logger.SetWriter(spool)
+//
+// Delivery is at least once: entries sent just before a crash may be sent
+// again. Records carry a checksum; a torn or corrupted record is counted by
+// Corrupted and the rest of its segment skipped.
+type SpoolWriter struct {
+	// Dir holds the segment files. It is created if missing.
+	Dir string
+
+	// Sink receives the spooled entries in batches.
+	Sink BatchSink
+
+	// MaxBytes caps the size of the spool. When it is exceeded the oldest
+	// segments are deleted and their entries counted by Dropped. Defaults
+	// to 256MiB.
+	MaxBytes int64
+
+	// SegmentSize is the size at which a new segment file is started.
+	// Defaults to 8MiB.
+	SegmentSize int64
+
+	// BatchSize is the maximum number of entries per batch. Defaults to 100.
+	BatchSize int
+
+	// RetryInterval is the wait after a failed delivery. Defaults to 1s.
+	RetryInterval time.Duration
+
+	once      sync.Once
+	err       error
+	mu        sync.Mutex
+	segments  []spoolSegment
+	cur       *os.File
+	total     int64
+	nextSeq   uint64
+	deliverMu sync.Mutex
+	cursor    spoolCursor
+	notify    chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closed    bool
+	counters  deliveryCounters
+	recent    failureWindow
+	corrupted atomic.Uint64
+}
+
+// spoolSegment is a segment file and the size of its complete records.
+type spoolSegment struct {
+	seq  uint64
+	size int64
+}
+
+// spoolCursor is the position of the next record to deliver.
+type spoolCursor struct {
+	seq    uint64
+	offset int64
+}
+
+// NewSpoolWriter returns a SpoolWriter spooling to dir and delivering to sink.
+func NewSpoolWriter(dir string, sink BatchSink) *SpoolWriter {
+	return &SpoolWriter{Dir: dir, Sink: sink}
+}
+
+func (w *SpoolWriter) start() {
+	w.notify = make(chan struct{}, 1)
+	w.done = make(chan struct{})
+	w.stopped = make(chan struct{})
+	if w.err = w.open(); w.err != nil {
+		close(w.stopped)
+		return
+	}
+	go w.run()
+}
+
+// open loads the existing segments and the cursor and starts a new segment;
+// the last segment of a previous process may end in a torn record, so it
+// is never appended to.
+func (w *SpoolWriter) open() error {
+	if err := os.MkdirAll(w.Dir, 0o755); err != nil {
+		return fmt.Errorf("logging: spool: %w", err)
+	}
+	names, err := filepath.Glob(filepath.Join(w.Dir, "*.seg"))
+	if err != nil {
+		return fmt.Errorf("logging: spool: %w", err)
+	}
+	for _, name := range names {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(name), ".seg"), 10, 64)
+		if err != nil {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+		w.segments = append(w.segments, spoolSegment{seq: seq, size: fi.Size()})
+		w.total += fi.Size()
+	}
+	sort.Slice(w.segments, func(i, j int) bool { return w.segments[i].seq < w.segments[j].seq })
+	if n := len(w.segments); n > 0 {
+		w.nextSeq = w.segments[n-1].seq + 1
+	}
+	w.cursor = w.loadCursor()
+	// Segments before the cursor were delivered before a crash kept them
+	// from being deleted.
+	for len(w.segments) > 0 && w.segments[0].seq < w.cursor.seq {
+		_ = os.Remove(w.segmentPath(w.segments[0].seq))
+		w.total -= w.segments[0].size
+		w.segments = w.segments[1:]
+	}
+	return w.rotate()
+}
+
+// rotate closes the current segment and starts the next one.
+func (w *SpoolWriter) rotate() error {
+	if w.cur != nil {
+		_ = w.cur.Close()
+	}
+	seq := w.nextSeq
+	f, err := os.OpenFile(w.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		w.cur = nil
+		return fmt.Errorf("logging: spool: %w", err)
+	}
+	w.cur = f
+	w.nextSeq++
+	w.segments = append(w.segments, spoolSegment{seq: seq})
+	return nil
+}
+
+func (w *SpoolWriter) segmentPath(seq uint64) string {
+	return filepath.Join(w.Dir, fmt.Sprintf("%020d.seg", seq))
+}
+
+func (w *SpoolWriter) cursorPath() string {
+	return filepath.Join(w.Dir, "cursor")
+}
+
+// loadCursor reads the persisted cursor; a missing or unreadable cursor
+// restarts delivery at the oldest segment.
+func (w *SpoolWriter) loadCursor() spoolCursor {
+	var c spoolCursor
+	data, err := os.ReadFile(w.cursorPath())
+	if err != nil {
+		return c
+	}
+	if _, err := fmt.Sscanf(string(data), "%d %d", &c.seq, &c.offset); err != nil {
+		return spoolCursor{}
+	}
+	return c
+}
+
+func (w *SpoolWriter) saveCursor(c spoolCursor) {
+	tmp := w.cursorPath() + ".tmp"
+	if os.WriteFile(tmp, []byte(fmt.Sprintf("%d %d\n", c.seq, c.offset)), 0o644) == nil {
+		_ = os.Rename(tmp, w.cursorPath())
+	}
+}
+
+// WriteEntry implements log.Writer. It appends the entry to the current
+// segment and returns once it is in the operating system's page cache;
+// call Sync to commit it to stable storage.
+func (w *SpoolWriter) WriteEntry(e *log.Entry) (int, error) {
+	w.once.Do(w.start)
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	b := getEntryBuffer()
+	defer putEntryBuffer(b)
+	*b = append(*b, make([]byte, spoolHeader)...)
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+	payload := (*b)[spoolHeader:]
+	binary.LittleEndian.PutUint32((*b)[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32((*b)[4:8], crc32.ChecksumIEEE(payload))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	last := &w.segments[len(w.segments)-1]
+	if w.cur == nil || (last.size > 0 && last.size+int64(len(*b)) > w.segmentSize()) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+		last = &w.segments[len(w.segments)-1]
+	}
+	n, err := w.cur.Write(*b)
+	last.size += int64(n)
+	w.total += int64(n)
+	if err != nil {
+		return 0, fmt.Errorf("logging: spool: %w", err)
+	}
+	w.enforceCap()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+	return len(payload), nil
+}
+
+// enforceCap deletes the oldest segments, other than the current one, while
+// the spool is larger than MaxBytes.
+func (w *SpoolWriter) enforceCap() {
+	for w.total > w.maxBytes() && len(w.segments) > 1 {
+		old := w.segments[0]
+		if n, err := countRecords(w.segmentPath(old.seq), w.offsetIn(old.seq), old.size); err == nil {
+			w.counters.dropped.Add(uint64(n))
+		}
+		_ = os.Remove(w.segmentPath(old.seq))
+		w.segments = w.segments[1:]
+		w.total -= old.size
+	}
+}
+
+// offsetIn returns the delivery offset within segment seq.
+func (w *SpoolWriter) offsetIn(seq uint64) int64 {
+	if w.cursor.seq == seq {
+		return w.cursor.offset
+	}
+	return 0
+}
+
+func (w *SpoolWriter) run() {
+	defer close(w.stopped)
+	for {
+		n, err := w.deliver()
+		switch {
+		case err != nil:
+			select {
+			case <-time.After(w.retryInterval()):
+			case <-w.done:
+				return
+			}
+		case n == 0:
+			select {
+			case <-w.notify:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// deliver sends the next batch to Sink and advances the cursor. It returns
+// the number of entries delivered, zero when the spool is empty.
+func (w *SpoolWriter) deliver() (int, error) {
+	w.deliverMu.Lock()
+	defer w.deliverMu.Unlock()
+
+	for {
+		w.mu.Lock()
+		var seg spoolSegment
+		found, current := false, false
+		for i, s := range w.segments {
+			if s.seq >= w.cursor.seq {
+				seg, found, current = s, true, i == len(w.segments)-1
+				break
+			}
+		}
+		if found && seg.seq != w.cursor.seq {
+			w.cursor = spoolCursor{seq: seg.seq}
+		}
+		cursor := w.cursor
+		w.mu.Unlock()
+		if !found {
+			return 0, nil
+		}
+
+		batch, next, err := readRecords(w.segmentPath(seg.seq), cursor.offset, seg.size, w.batchSize())
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			// Deleted by enforceCap; move on to the next segment.
+			w.advance(seg, spoolCursor{seq: seg.seq + 1})
+			continue
+		case err != nil:
+			// Skip the unreadable rest of the segment.
+			w.corrupted.Add(1)
+			next = seg.size
+		}
+		if len(batch) == 0 {
+			if current && next == cursor.offset {
+				return 0, nil
+			}
+			w.advance(seg, nextCursor(seg, next, current))
+			continue
+		}
+		if err := w.Sink.WriteBatch(batch); err != nil {
+			w.recent.record(true)
+			return 0, err
+		}
+		w.recent.record(false)
+		w.counters.deliver(len(batch), batchBytes(batch))
+		w.advance(seg, nextCursor(seg, next, current))
+		return len(batch), nil
+	}
+}
+
+// nextCursor returns the cursor following offset in seg, which moves to
+// the next segment once a segment other than the current one is done.
+func nextCursor(seg spoolSegment, offset int64, current bool) spoolCursor {
+	if offset >= seg.size && !current {
+		return spoolCursor{seq: seg.seq + 1}
+	}
+	return spoolCursor{seq: seg.seq, offset: offset}
+}
+
+// advance moves the cursor to c, deleting seg once it is fully delivered.
+func (w *SpoolWriter) advance(seg spoolSegment, c spoolCursor) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if c.seq > seg.seq {
+		for i, s := range w.segments {
+			if s.seq == seg.seq {
+				_ = os.Remove(w.segmentPath(s.seq))
+				w.segments = append(w.segments[:i], w.segments[i+1:]...)
+				w.total -= s.size
+				break
+			}
+		}
+	}
+	if c.seq >= w.cursor.seq {
+		w.cursor = c
+	}
+	w.saveCursor(w.cursor)
+}
+
+// errSpoolCorrupt reports a record that is torn or fails its checksum.
+var errSpoolCorrupt = errors.New("logging: spool: corrupted record")
+
+// readRecords reads up to limit records of the segment at path between
+// offset and size. It returns the records, the offset following them and
+// errSpoolCorrupt if it stopped at a bad record.
+func readRecords(path string, offset, size int64, limit int) ([][]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(io.NewSectionReader(f, offset, size-offset))
+	var records [][]byte
+	var header [spoolHeader]byte
+	for len(records) < limit && offset < size {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return records, offset, errSpoolCorrupt
+		}
+		n := int64(binary.LittleEndian.Uint32(header[0:4]))
+		if n > size-offset-spoolHeader {
+			return records, offset, errSpoolCorrupt
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return records, offset, errSpoolCorrupt
+		}
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:8]) {
+			return records, offset, errSpoolCorrupt
+		}
+		records = append(records, payload)
+		offset += spoolHeader + n
+	}
+	return records, offset, nil
+}
+
+// countRecords counts the records of the segment at path between offset
+// and size.
+func countRecords(path string, offset, size int64) (int, error) {
+	records, _, err := readRecords(path, offset, size, int(^uint(0)>>1))
+	return len(records), err
+}
+
+// Dropped returns the number of entries deleted because the spool exceeded
+// MaxBytes.
+func (w *SpoolWriter) Dropped() uint64 {
+	return w.counters.dropped.Load()
+}
+
+// Stats returns the delivery counters of w.
+func (w *SpoolWriter) Stats() DeliveryStats {
+	return w.counters.snapshot()
+}
+
+// Corrupted returns the number of corrupted records found. The rest of the
+// segment holding one is skipped.
+func (w *SpoolWriter) Corrupted() uint64 {
+	return w.corrupted.Load()
+}
+
+// Flush delivers the spooled entries to Sink. It returns the delivery
+// error if Sink fails; the entries stay in the spool.
+func (w *SpoolWriter) Flush() error {
+	w.once.Do(w.start)
+	if w.err != nil {
+		return w.err
+	}
+	for {
+		n, err := w.deliver()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return flushWriter(w.Sink)
+		}
+	}
+}
+
+// Sync commits the current segment to stable storage.
+func (w *SpoolWriter) Sync() error {
+	w.once.Do(w.start)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cur == nil {
+		return w.err
+	}
+	return w.cur.Sync()
+}
+
+// Close makes a last delivery attempt, stops the background goroutine and
+// closes Sink if it is closable. Undelivered entries stay in Dir.
+func (w *SpoolWriter) Close() error {
+	w.once.Do(w.start)
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	if w.err == nil {
+		_ = w.Flush()
+	}
+	close(w.done)
+	<-w.stopped
+	w.mu.Lock()
+	if w.cur != nil {
+		_ = w.cur.Close()
+		w.cur = nil
+	}
+	w.mu.Unlock()
+	return closeWriter(w.Sink)
+}
+
+func (w *SpoolWriter) maxBytes() int64 {
+	if w.MaxBytes <= 0 {
+		return 256 << 20
+	}
+	return w.MaxBytes
+}
+
+func (w *SpoolWriter) segmentSize() int64 {
+	if w.SegmentSize <= 0 {
+		return 8 << 20
+	}
+	return w.SegmentSize
+}
+
+func (w *SpoolWriter) batchSize() int {
+	if w.BatchSize <= 0 {
+		return 100
+	}
+	return w.BatchSize
+}
+
+func (w *SpoolWriter) retryInterval() time.Duration {
+	if w.RetryInterval <= 0 {
+		return time.Second
+	}
+	return w.RetryInterval
+}
@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collectSink keeps a copy of every entry it receives.
+type collectSink struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (s *collectSink) WriteBatch(entries [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		s.entries = append(s.entries, string(e))
+	}
+	return nil
+}
+
+func (s *collectSink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.entries...)
+}
+
+var errCollectorDown = errors.New("collector down")
+
+func failingSink() BatchSink {
+	return BatchFunc(func([][]byte) error { return errCollectorDown })
+}
+
+func TestSpoolWriterDelivers(t *testing.T) {
+	sink := &collectSink{}
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewSpoolWriter(t.TempDir(), sink))
+	logger.Info("one")
+	logger.Info("two")
+	if err := logger.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	got := sink.messages()
+	if len(got) != 2 || !strings.Contains(got[0], `"one"`) || !strings.Contains(got[1], `"two"`) {
+		t.Errorf("Expected both entries delivered in order, got %q", got)
+	}
+}
+
+// closingSink is a sink that is down, counting the batches it receives
+// after Close.
+type closingSink struct {
+	closes     int
+	afterClose int
+}
+
+func (s *closingSink) WriteBatch([][]byte) error {
+	if s.closes > 0 {
+		s.afterClose++
+	}
+	return errCollectorDown
+}
+
+func (s *closingSink) Close() error {
+	s.closes++
+	return nil
+}
+
+func TestSpoolWriterDoubleClose(t *testing.T) {
+	sink := &closingSink{}
+	w := NewSpoolWriter(t.TempDir(), sink)
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(w)
+	logger.Info("undelivered")
+	for range 2 {
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+	if sink.closes != 1 || sink.afterClose != 0 {
+		t.Errorf("Expected the sink closed once and not written after, got %d closes and %d late batches", sink.closes, sink.afterClose)
+	}
+}
+
+func TestSpoolWriterSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	logger, _ := testLogger(LogLevelInfo)
+	spool := &SpoolWriter{Dir: dir, Sink: failingSink(), RetryInterval: time.Hour}
+	logger.SetWriter(spool)
+	for _, msg := range []string{"a", "b", "c"} {
+		logger.Info(msg)
+	}
+	if err := spool.Flush(); !errors.Is(err, errCollectorDown) {
+		t.Errorf("Expected the delivery error from Flush, got %v", err)
+	}
+	_ = spool.Close()
+
+	sink := &collectSink{}
+	next := NewSpoolWriter(dir, sink)
+	if err := next.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	defer next.Close()
+	if got := sink.messages(); len(got) != 3 {
+		t.Errorf("Expected the spooled entries delivered after a restart, got %q", got)
+	}
+
+	third := &collectSink{}
+	_ = next.Close()
+	again := NewSpoolWriter(dir, third)
+	_ = again.Flush()
+	_ = again.Close()
+	if got := third.messages(); len(got) != 0 {
+		t.Errorf("Delivered entries should not be delivered again, got %q", got)
+	}
+}
+
+func TestSpoolWriterSkipsCorruptedRecords(t *testing.T) {
+	dir := t.TempDir()
+	var seg []byte
+	record := func(payload string) {
+		var h [spoolHeader]byte
+		binary.LittleEndian.PutUint32(h[0:4], uint32(len(payload)))
+		binary.LittleEndian.PutUint32(h[4:8], crc32.ChecksumIEEE([]byte(payload)))
+		seg = append(append(seg, h[:]...), payload...)
+	}
+	record(`{"message":"intact"}` + "\n")
+	record(`{"message":"flipped"}` + "\n")
+	seg[len(seg)-3] ^= 0xff
+	record(`{"message":"after"}` + "\n")
+	seg = append(seg, 0x10, 0x00) // torn header
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000001.seg"), seg, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &collectSink{}
+	spool := NewSpoolWriter(dir, sink)
+	if err := spool.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	_ = spool.Close()
+	if got := sink.messages(); len(got) != 1 || !strings.Contains(got[0], "intact") {
+		t.Errorf("Expected only the record before the corruption, got %q", got)
+	}
+	if spool.Corrupted() != 1 {
+		t.Errorf("Expected 1 corrupted record, got %d", spool.Corrupted())
+	}
+}
+
+func TestSpoolWriterCapsSize(t *testing.T) {
+	dir := t.TempDir()
+	logger, _ := testLogger(LogLevelInfo)
+	spool := &SpoolWriter{Dir: dir, Sink: failingSink(), MaxBytes: 4096, SegmentSize: 1024, RetryInterval: time.Hour}
+	logger.SetWriter(spool)
+	for i := 0; i < 200; i++ {
+		logger.Info("entry %d", i)
+	}
+	_ = spool.Close()
+
+	var total int64
+	names, _ := filepath.Glob(filepath.Join(dir, "*.seg"))
+	for _, name := range names {
+		fi, _ := os.Stat(name)
+		total += fi.Size()
+	}
+	if total > 4096+1024 {
+		t.Errorf("Expected the spool to stay near MaxBytes, got %d bytes", total)
+	}
+	if spool.Dropped() == 0 {
+		t.Error("Expected entries dropped by the size cap to be counted")
+	}
+}
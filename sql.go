@@ -0,0 +1,340 @@
+package logging
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+)
+
+// SQLOptions configures query logging for wrapped database/sql drivers.
+type SQLOptions struct {
+	// SlowThreshold logs statements taking at least this long at Warning
+	// level. Zero disables slow-query detection.
+	SlowThreshold time.Duration
+
+	// LogArgs includes statement arguments in each entry.
+	LogArgs bool
+
+	// RedactArgs replaces argument values with "[REDACTED]" when LogArgs is set.
+	RedactArgs bool
+}
+
+// WrapDriver returns a driver.Driver that logs every statement executed
+// through d to l. Register the result with sql.Register to use it.
+func WrapDriver(l *Logger, d driver.Driver, opts SQLOptions) driver.Driver {
+	return &sqlDriver{driver: d, log: &sqlLogger{logger: l, opts: opts}}
+}
+
+// WrapConnector returns a driver.Connector that logs every statement executed
+// through c to l. Pass the result to sql.OpenDB.
+func WrapConnector(l *Logger, c driver.Connector, opts SQLOptions) driver.Connector {
+	s := &sqlLogger{logger: l, opts: opts}
+	return &sqlConnector{connector: c, driver: &sqlDriver{driver: c.Driver(), log: s}, log: s}
+}
+
+type sqlLogger struct {
+	logger *Logger
+	opts   SQLOptions
+}
+
+func (s *sqlLogger) log(ctx context.Context, op, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	d := time.Since(start)
+	if sc := scopeFromContext(ctx); sc != nil && (op == "exec" || op == "query") {
+		sc.AddStatement()
+	}
+	logger := s.logger.scoped(ctx)
+	fields := Fields{"sql_op": op, "duration": d}
+	if query != "" {
+		fields["query"] = query
+	}
+	if s.opts.LogArgs && len(args) > 0 {
+		fields["args"] = s.args(args)
+	}
+	switch {
+	case err != nil:
+		fields["error"] = err
+		logger.WithFields(fields).Error("sql %s failed", op)
+	case s.opts.SlowThreshold > 0 && d >= s.opts.SlowThreshold:
+		logger.WithFields(fields).Warning("slow sql %s", op)
+	default:
+		logger.WithFields(fields).Info("sql %s", op)
+	}
+}
+
+func (s *sqlLogger) args(args []driver.NamedValue) []any {
+	out := make([]any, len(args))
+	for i, a := range args {
+		if s.opts.RedactArgs {
+			out[i] = "[REDACTED]"
+		} else {
+			out[i] = a.Value
+		}
+	}
+	return out
+}
+
+type sqlDriver struct {
+	driver driver.Driver
+	log    *sqlLogger
+}
+
+func (d *sqlDriver) Open(name string) (driver.Conn, error) {
+	start := time.Now()
+	c, err := d.driver.Open(name)
+	if err != nil {
+		d.log.log(context.Background(), "connect", "", nil, start, err)
+		return nil, err
+	}
+	return &sqlConn{conn: c, log: d.log}, nil
+}
+
+func (d *sqlDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &sqlConnector{connector: c, driver: d, log: d.log}, nil
+	}
+	return &sqlConnector{connector: dsnConnector{name: name, driver: d.driver}, driver: d, log: d.log}, nil
+}
+
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.name) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+type sqlConnector struct {
+	connector driver.Connector
+	driver    *sqlDriver
+	log       *sqlLogger
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	start := time.Now()
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		c.log.log(ctx, "connect", "", nil, start, err)
+		return nil, err
+	}
+	return &sqlConn{conn: conn, log: c.log}, nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver { return c.driver }
+
+// Close closes the wrapped connector if it is closable, as sql.DB.Close
+// does with the connectors it is opened with.
+func (c *sqlConnector) Close() error {
+	if cl, ok := c.connector.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+type sqlConn struct {
+	conn driver.Conn
+	log  *sqlLogger
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if p, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		c.log.log(ctx, "prepare", query, nil, start, err)
+		return nil, err
+	}
+	return &sqlStmt{stmt: stmt, query: query, log: c.log}, nil
+}
+
+func (c *sqlConn) Close() error { return c.conn.Close() }
+
+func (c *sqlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var (
+		tx  driver.Tx
+		err error
+	)
+	if b, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.conn.Begin()
+	}
+	c.log.log(ctx, "begin", "", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{tx: tx, ctx: ctx, log: c.log}, nil
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	c.log.log(ctx, "exec", query, args, start, err)
+	return res, err
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	c.log.log(ctx, "query", query, args, start, err)
+	return rows, err
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *sqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type sqlStmt struct {
+	stmt  driver.Stmt
+	query string
+	log   *sqlLogger
+}
+
+func (s *sqlStmt) Close() error  { return s.stmt.Close() }
+func (s *sqlStmt) NumInput() int { return s.stmt.NumInput() }
+
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		res driver.Result
+		err error
+	)
+	if e, ok := s.stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		res, err = s.stmt.Exec(values(args))
+	}
+	s.log.log(ctx, "exec", s.query, args, start, err)
+	return res, err
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if q, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		rows, err = s.stmt.Query(values(args))
+	}
+	s.log.log(ctx, "query", s.query, args, start, err)
+	return rows, err
+}
+
+func (s *sqlStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// ColumnConverter returns the converter of the wrapped statement for the
+// argument idx, or the default one database/sql would use without it.
+func (s *sqlStmt) ColumnConverter(idx int) driver.ValueConverter {
+	if cc, ok := s.stmt.(driver.ColumnConverter); ok {
+		return cc.ColumnConverter(idx)
+	}
+	return driver.DefaultParameterConverter
+}
+
+type sqlTx struct {
+	tx  driver.Tx
+	ctx context.Context // of BeginTx
+	log *sqlLogger
+}
+
+func (t *sqlTx) Commit() error {
+	start := time.Now()
+	err := t.tx.Commit()
+	t.log.log(t.ctx, "commit", "", nil, start, err)
+	return err
+}
+
+func (t *sqlTx) Rollback() error {
+	start := time.Now()
+	err := t.tx.Rollback()
+	t.log.log(t.ctx, "rollback", "", nil, start, err)
+	return err
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	out := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		out[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return out
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	out := make([]driver.Value, len(args))
+	for i, a := range args {
+		out[i] = a.Value
+	}
+	return out
+}
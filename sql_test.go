@@ -0,0 +1,228 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDriver is a minimal database/sql driver used to exercise the wrapper.
+type fakeDriver struct {
+	delay time.Duration
+	err   error
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConnector struct{ d *fakeDriver }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{d: c.d}, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return c.d }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{d: c.d}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	time.Sleep(s.d.delay)
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return nil }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func openTestDB(t *testing.T, d *fakeDriver, opts SQLOptions) (*sql.DB, func() map[string]any) {
+	t.Helper()
+	logger, buf := testLogger(LogLevelInfo)
+	db := sql.OpenDB(WrapConnector(logger, fakeConnector{d: d}, opts))
+	t.Cleanup(func() { db.Close() })
+	last := func() map[string]any {
+		var m map[string]any
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		if err := json.Unmarshal(lines[len(lines)-1], &m); err != nil {
+			t.Fatalf("Failed to parse log entry: %v", err)
+		}
+		return m
+	}
+	return db, last
+}
+
+func TestSQLQueryLogging(t *testing.T) {
+	db, last := openTestDB(t, &fakeDriver{}, SQLOptions{LogArgs: true})
+
+	if _, err := db.Exec("UPDATE users SET name = ? WHERE id = ?", "john", 7); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	entry := last()
+	if entry["level"] != "info" {
+		t.Errorf("Expected level 'info', got '%v'", entry["level"])
+	}
+	if entry["query"] != "UPDATE users SET name = ? WHERE id = ?" {
+		t.Errorf("Unexpected query field: %v", entry["query"])
+	}
+	if entry["sql_op"] != "exec" {
+		t.Errorf("Expected sql_op 'exec', got '%v'", entry["sql_op"])
+	}
+	args, _ := entry["args"].([]any)
+	if len(args) != 2 || args[0] != "john" {
+		t.Errorf("Unexpected args field: %v", entry["args"])
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("Expected duration field")
+	}
+}
+
+func TestSQLRedactArgs(t *testing.T) {
+	db, last := openTestDB(t, &fakeDriver{}, SQLOptions{LogArgs: true, RedactArgs: true})
+
+	if _, err := db.Exec("UPDATE users SET password = ?", "hunter2"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	args, _ := last()["args"].([]any)
+	if len(args) != 1 || args[0] != "[REDACTED]" {
+		t.Errorf("Expected redacted args, got %v", args)
+	}
+}
+
+func TestSQLSlowQuery(t *testing.T) {
+	db, last := openTestDB(t, &fakeDriver{delay: 5 * time.Millisecond}, SQLOptions{SlowThreshold: time.Millisecond})
+
+	if _, err := db.Exec("SELECT pg_sleep(1)"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if level := last()["level"]; level != "warn" {
+		t.Errorf("Expected level 'warn', got '%v'", level)
+	}
+}
+
+func TestSQLErrorLogging(t *testing.T) {
+	db, last := openTestDB(t, &fakeDriver{err: errors.New("syntax error")}, SQLOptions{})
+
+	if _, err := db.Exec("SELEC 1"); err == nil {
+		t.Fatal("Expected Exec to fail")
+	}
+	entry := last()
+	if entry["level"] != "error" {
+		t.Errorf("Expected level 'error', got '%v'", entry["level"])
+	}
+	if entry["error"] != "syntax error" {
+		t.Errorf("Expected error field, got '%v'", entry["error"])
+	}
+	if _, ok := entry["args"]; ok {
+		t.Error("Args should not be logged unless LogArgs is set")
+	}
+}
+
+// closingConnector is a fakeConnector counting Close calls.
+type closingConnector struct {
+	fakeConnector
+	closes *int
+}
+
+func (c closingConnector) Close() error {
+	*c.closes++
+	return nil
+}
+
+func TestSQLClosesConnector(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	var closes int
+	db := sql.OpenDB(WrapConnector(logger, closingConnector{fakeConnector{d: &fakeDriver{}}, &closes}, SQLOptions{}))
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if closes != 1 {
+		t.Errorf("Expected sql.DB.Close to close the wrapped connector, got %d closes", closes)
+	}
+}
+
+// convertingStmt converts its arguments with a ColumnConverter and records
+// them.
+type convertingStmt struct {
+	fakeStmt
+	got *[]driver.Value
+}
+
+func (s *convertingStmt) ColumnConverter(int) driver.ValueConverter { return upperConverter{} }
+
+func (s *convertingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	*s.got = args
+	return driver.RowsAffected(1), nil
+}
+
+type upperConverter struct{}
+
+func (upperConverter) ConvertValue(v any) (driver.Value, error) {
+	if s, ok := v.(string); ok {
+		return strings.ToUpper(s), nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+type convertingConn struct {
+	fakeConn
+	got *[]driver.Value
+}
+
+func (c *convertingConn) Prepare(string) (driver.Stmt, error) {
+	return &convertingStmt{fakeStmt: fakeStmt{d: c.d}, got: c.got}, nil
+}
+
+type convertingConnector struct {
+	fakeConnector
+	got *[]driver.Value
+}
+
+func (c convertingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &convertingConn{fakeConn: fakeConn{d: c.d}, got: c.got}, nil
+}
+
+func TestSQLColumnConverter(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	var got []driver.Value
+	db := sql.OpenDB(WrapConnector(logger, convertingConnector{fakeConnector{d: &fakeDriver{}}, &got}, SQLOptions{}))
+	defer db.Close()
+	stmt, err := db.Prepare("INSERT INTO users (name) VALUES (?)")
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	defer stmt.Close()
+	if _, err := stmt.Exec("john"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if len(got) != 1 || got[0] != "JOHN" {
+		t.Errorf("Expected the statement's ColumnConverter applied, got %v", got)
+	}
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DeliveryStats reports the outcome of deliveries through a sink wrapper
+// such as RetrySink, SpoolWriter or DeadLetterSink. Counters a wrapper does
+// not track stay zero.
+type DeliveryStats struct {
+	// Delivered is the number of entries delivered, possibly after retries.
+	Delivered uint64
+	// Retries is the number of attempts after the first.
+	Retries uint64
+	// Failed is the number of entries given up on.
+	Failed uint64
+	// Dropped is the number of entries discarded without an attempt, by a
+	// spool over its size cap or an open circuit breaker.
+	Dropped uint64
+	// DeadLettered is the number of entries written to a dead-letter file.
+	DeadLettered uint64
+	// Bytes is the size of the delivered entries.
+	Bytes uint64
+}
+
+// deliveryCounters backs the Stats methods of the sink wrappers.
+type deliveryCounters struct {
+	delivered    atomic.Uint64
+	retries      atomic.Uint64
+	failed       atomic.Uint64
+	dropped      atomic.Uint64
+	deadLettered atomic.Uint64
+	bytes        atomic.Uint64
+}
+
+// deliver counts n entries of size bytes as delivered.
+func (c *deliveryCounters) deliver(n, size int) {
+	c.delivered.Add(uint64(n))
+	c.bytes.Add(uint64(size))
+}
+
+func (c *deliveryCounters) snapshot() DeliveryStats {
+	return DeliveryStats{
+		Delivered:    c.delivered.Load(),
+		Retries:      c.retries.Load(),
+		Failed:       c.failed.Load(),
+		Dropped:      c.dropped.Load(),
+		DeadLettered: c.deadLettered.Load(),
+		Bytes:        c.bytes.Load(),
+	}
+}
+
+// batchBytes returns the total size of entries.
+func batchBytes(entries [][]byte) int {
+	n := 0
+	for _, e := range entries {
+		n += len(e)
+	}
+	return n
+}
+
+// SinkStats is the DeliveryStats of one sink wrapper in a writer chain.
+type SinkStats struct {
+	// Sink names the wrapper's type, such as "RetrySink".
+	Sink string
+	DeliveryStats
+}
+
+// Stats returns the DeliveryStats of every sink wrapper in the writer
+// chain of l, outermost first and branch by branch where the chain fans
+// out to several sinks, so operators can alert on log loss:
+//
+//	for _, s := range logger.Stats() {
+//		if s.Dropped+s.Failed > 0 { ... }
+//	}
+func (l *Logger) Stats() []SinkStats {
+	return sinkStats(l.logger.Writer)
+}
+
+// sinkStats collects the DeliveryStats of the writers reached from w.
+func sinkStats(w any) []SinkStats {
+	var stats []SinkStats
+	walkWriters(w, func(w any) {
+		if s, ok := w.(interface{ Stats() DeliveryStats }); ok {
+			stats = append(stats, SinkStats{Sink: sinkName(w), DeliveryStats: s.Stats()})
+		}
+	})
+	return stats
+}
+
+// sinkName returns the type name of the writer w, such as "RetrySink".
+func sinkName(w any) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", w), "*logging.")
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestLoggerStatsPerSink(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	var dead bytes.Buffer
+	calls := 0
+	collector := BatchFunc(func(entries [][]byte) error {
+		calls++
+		switch calls {
+		case 1:
+			return errors.New("reset")
+		case 2:
+			return &RejectedError{Rejected: []Rejection{{Index: 0, Reason: "schema"}}}
+		}
+		return nil
+	})
+	retry := NewRetrySink(collector, RetryPolicy{InitialBackoff: time.Millisecond})
+	logger.SetWriter(NewBatchWriter(&DeadLetterSink{Sink: retry, Writer: &dead}, 1, time.Second))
+	logger.Info("rejected")
+	logger.Info("accepted")
+
+	stats := logger.Stats()
+	if len(stats) != 2 || stats[0].Sink != "DeadLetterSink" || stats[1].Sink != "RetrySink" {
+		t.Fatalf("Expected stats for both wrappers, outermost first, got %+v", stats)
+	}
+	if dl := stats[0]; dl.DeadLettered != 1 || dl.Delivered != 1 || dl.Bytes == 0 {
+		t.Errorf("Unexpected dead-letter stats: %+v", dl)
+	}
+	if rs := stats[1]; rs.Retries != 1 || rs.Failed != 1 || rs.Delivered != 1 {
+		t.Errorf("Unexpected retry stats: %+v", rs)
+	}
+}
+
+func TestVarsReportsSinks(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewBatchWriter(NewRetrySink(&collectSink{}, RetryPolicy{}), 1, time.Second))
+	logger.Info("shipped")
+
+	var got struct {
+		Sinks []map[string]any `json:"sinks"`
+	}
+	if err := json.Unmarshal([]byte(logger.Vars().String()), &got); err != nil {
+		t.Fatalf("Vars should render JSON: %v", err)
+	}
+	if len(got.Sinks) != 1 || got.Sinks[0]["sink"] != "RetrySink" || got.Sinks[0]["delivered"] != float64(1) {
+		t.Errorf("Unexpected sinks: %v", got.Sinks)
+	}
+}
+
+func TestLoggerStatsMultiSink(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	first, second := &collectSink{}, &collectSink{}
+	logger.SetWriter(&log.MultiEntryWriter{
+		NewBatchWriter(NewRetrySink(first, RetryPolicy{}), 1, time.Second),
+		&log.IOWriter{Writer: io.Discard},
+		NewBatchWriter(&DeadLetterSink{Sink: second, Writer: io.Discard}, 1, time.Second),
+	})
+	logger.Info("shipped")
+
+	stats := logger.Stats()
+	if len(stats) != 2 || stats[0].Sink != "RetrySink" || stats[1].Sink != "DeadLetterSink" {
+		t.Fatalf("Expected stats for the sinks of every branch, got %+v", stats)
+	}
+	if stats[0].Delivered != 1 || stats[1].Delivered != 1 {
+		t.Errorf("Expected each sink to count its delivery, got %+v", stats)
+	}
+}
@@ -0,0 +1,359 @@
+package logging
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	// logTags caches whether values of a type contain members to omit.
+	logTags sync.Map // reflect.Type -> bool
+
+	// structFields caches the members to encode of struct types with log
+	// tags.
+	structFields sync.Map // reflect.Type -> []taggedField
+
+	// interfaceTypes caches whether values of a type can hold interface
+	// values, whose dynamic types may have log tags.
+	interfaceTypes sync.Map // reflect.Type -> bool
+)
+
+// visit identifies a map, pointer or slice while values are searched or
+// converted, so cyclic values are not followed forever.
+type visit struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// visitOf returns the visit of rv, if it is a non-nil map, pointer or
+// slice.
+func visitOf(rv reflect.Value) (visit, bool) {
+	switch rv.Kind() {
+	case reflect.Map, reflect.Pointer:
+		return visit{rv.Pointer(), 0, rv.Type()}, !rv.IsNil()
+	case reflect.Slice:
+		return visit{rv.Pointer(), rv.Len(), rv.Type()}, !rv.IsNil()
+	}
+	return visit{}, false
+}
+
+// taggedField describes an encoded member of a struct with log tags.
+type taggedField struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	embedded  bool // an untagged embedded struct, flattened as by encoding/json
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// omitTagged returns v with the struct members tagged `log:"-"` or
+// `log:"omit"` removed: a struct, or a pointer to one, with such members
+// anywhere inside, including in map values and interface values, is
+// converted to Fields keyed like encoding/json would, and slices and maps
+// holding them are converted element by element. Any other value is
+// returned as is, so it encodes exactly as before.
+func omitTagged(v any) any {
+	return filterTagged(v, nil)
+}
+
+// filterTagged is omitTagged for a value inside the values being converted,
+// path. A cycle back to one of them is left out, as encoding/json would
+// fail on it.
+func filterTagged(v any, path map[visit]bool) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if t := rv.Type(); !hasLogTags(t) && !(holdsInterfaces(t) && dynamicTags(rv, make(map[visit]bool))) {
+		return v
+	}
+	if path == nil {
+		path = make(map[visit]bool)
+	}
+	return filterValue(rv, path)
+}
+
+// hasLogTags reports whether values of t contain struct members to omit.
+func hasLogTags(t reflect.Type) bool {
+	if tagged, ok := logTags.Load(t); ok {
+		return tagged.(bool)
+	}
+	tagged := logTagged(t, make(map[reflect.Type]bool))
+	logTags.Store(t, tagged)
+	return tagged
+}
+
+func logTagged(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return logTagged(t.Elem(), visiting)
+	case reflect.Struct:
+	default:
+		return false
+	}
+	if marshals(t) {
+		return false
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		if omitTag(f) || logTagged(f.Type, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshals reports whether the struct type t encodes itself.
+func marshals(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType)
+}
+
+// holdsInterfaces reports whether values of t can hold interface values,
+// such as the values of a map[string]any.
+func holdsInterfaces(t reflect.Type) bool {
+	if holds, ok := interfaceTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	holds := interfacesIn(t, make(map[reflect.Type]bool))
+	interfaceTypes.Store(t, holds)
+	return holds
+}
+
+func interfacesIn(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return interfacesIn(t.Elem(), visiting)
+	case reflect.Struct:
+	default:
+		return false
+	}
+	if marshals(t) || visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if (f.IsExported() || f.Anonymous) && !omitTag(f) && interfacesIn(f.Type, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// dynamicTags reports whether rv holds, in an interface value, a value
+// whose type has log tags. Those are only known at run time. seen holds the
+// maps, pointers and slices already searched.
+func dynamicTags(rv reflect.Value, seen map[visit]bool) bool {
+	if !holdsInterfaces(rv.Type()) {
+		return false
+	}
+	if v, ok := visitOf(rv); ok {
+		if seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if rv.IsNil() {
+			return false
+		}
+		elem := rv.Elem()
+		return hasLogTags(elem.Type()) || dynamicTags(elem, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if dynamicTags(rv.Index(i), seen) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for iter := rv.MapRange(); iter.Next(); {
+			if dynamicTags(iter.Value(), seen) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for _, f := range fieldsOf(rv.Type()) {
+			fv, err := rv.FieldByIndexErr(f.index)
+			if err == nil && dynamicTags(fv, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// omitTag reports whether f is tagged to be left out of log entries.
+func omitTag(f reflect.StructField) bool {
+	tag := f.Tag.Get("log")
+	return tag == "-" || tag == "omit"
+}
+
+// filterValue converts rv, which holds members to omit, for encoding.
+func filterValue(rv reflect.Value, path map[visit]bool) any {
+	if v, ok := visitOf(rv); ok {
+		if path[v] {
+			return nil
+		}
+		path[v] = true
+		defer delete(path, v)
+	}
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return filterValue(rv.Elem(), path)
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return filterElem(rv.Elem(), path)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = filterElem(rv.Index(i), path)
+		}
+		return out
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		out := make(Fields, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			out[mapKey(iter.Key())] = filterElem(iter.Value(), path)
+		}
+		return out
+	case reflect.Struct:
+		out := make(Fields)
+		filterStruct(rv, out, path)
+		return out
+	}
+	if rv.CanInterface() {
+		return rv.Interface()
+	}
+	return nil
+}
+
+// filterElem converts an element of a slice, map or interface value, which
+// may or may not hold members to omit.
+func filterElem(rv reflect.Value, path map[visit]bool) any {
+	if !rv.CanInterface() {
+		return nil
+	}
+	return filterTagged(rv.Interface(), path)
+}
+
+// mapKey returns the key of a map entry as encoding/json writes it.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if b, err := tm.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+// filterStruct adds the encoded members of the struct rv to out.
+func filterStruct(rv reflect.Value, out Fields, path map[visit]bool) {
+	for _, f := range fieldsOf(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		if !f.embedded && !fv.CanInterface() {
+			continue // promoted through an unexported embedded struct
+		}
+		if f.embedded {
+			if fv.Kind() == reflect.Pointer {
+				v, ok := visitOf(fv)
+				if !ok || path[v] {
+					continue
+				}
+				path[v] = true
+				filterStruct(fv.Elem(), out, path)
+				delete(path, v)
+				continue
+			}
+			filterStruct(fv, out, path)
+			continue
+		}
+		if f.omitEmpty && emptyValue(fv) {
+			continue
+		}
+		out[f.name] = filterTagged(fv.Interface(), path)
+	}
+}
+
+// emptyValue reports whether omitempty leaves out v, as for encoding/json:
+// false, 0, nil pointers and interfaces, and empty arrays, maps, slices
+// and strings, but never structs.
+func emptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// fieldsOf returns the members of the struct type t to encode.
+func fieldsOf(t reflect.Type) []taggedField {
+	if cached, ok := structFields.Load(t); ok {
+		return cached.([]taggedField)
+	}
+	var fields []taggedField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if omitTag(f) {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, taggedField{index: f.Index, embedded: true})
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, taggedField{index: f.Index, name: name, omitEmpty: strings.Contains(opts, "omitempty")})
+	}
+	structFields.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" log:"-"`
+	Token    string `log:"omit"`
+}
+
+type auditMeta struct {
+	Region string `json:"region,omitempty"`
+}
+
+type account struct {
+	auditMeta
+	ID      int           `json:"id"`
+	Creds   credentials   `json:"creds"`
+	Backups []credentials `json:"backups"`
+	Created time.Time     `json:"created"`
+	secret  string
+}
+
+func TestStructTagsOmitFields(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	acct := &account{
+		auditMeta: auditMeta{Region: "eu"},
+		ID:        7,
+		Creds:     credentials{User: "ada", Password: "hunter2", Token: "t0k3n"},
+		Backups:   []credentials{{User: "bob", Password: "pw"}},
+		secret:    "s",
+	}
+	logger.WithFields(Fields{"account": acct, "creds": acct.Creds, "plain": auditMeta{Region: "us"}}).Info("loaded")
+
+	e := decodeEntries(t, buf.String())[0]
+	a := e["account"].(map[string]any)
+	creds := a["creds"].(map[string]any)
+	backup := a["backups"].([]any)[0].(map[string]any)
+	if a["id"] != float64(7) || a["region"] != "eu" || creds["user"] != "ada" || backup["user"] != "bob" {
+		t.Errorf("Expected untagged members encoded like encoding/json, got %v", a)
+	}
+	for name, m := range map[string]map[string]any{"nested": creds, "slice": backup, "top-level": e["creds"].(map[string]any)} {
+		if _, ok := m["password"]; ok {
+			t.Errorf("%s: expected log:\"-\" members omitted, got %v", name, m)
+		}
+		if _, ok := m["Token"]; ok {
+			t.Errorf("%s: expected log:\"omit\" members omitted, got %v", name, m)
+		}
+	}
+	if _, ok := a["created"].(string); !ok || a["secret"] != nil {
+		t.Errorf("Expected marshalers kept and unexported members skipped, got %v", a)
+	}
+	if e["plain"].(map[string]any)["region"] != "us" {
+		t.Errorf("Expected structs without log tags encoded as before, got %v", e["plain"])
+	}
+	if acct.Creds.Password != "hunter2" {
+		t.Error("Filtering should not modify the logged value")
+	}
+}
+
+type session struct {
+	ID    string                 `json:"id"`
+	ByKey map[string]credentials `json:"by_key"`
+	Meta  map[string]any         `json:"meta"`
+}
+
+func TestStructTagsOmitFieldsInMaps(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	creds := credentials{User: "ada", Password: "hunter2", Token: "t0k3n"}
+	s := session{
+		ID:    "s1",
+		ByKey: map[string]credentials{"primary": creds},
+		Meta:  map[string]any{"creds": creds, "list": []any{&creds}, "n": 1},
+	}
+	logger.WithFields(Fields{"session": s, "meta": map[string]any{"creds": creds}, "ids": map[int]credentials{7: creds}}).Info("loaded")
+
+	e := decodeEntries(t, buf.String())[0]
+	got := e["session"].(map[string]any)
+	meta := got["meta"].(map[string]any)
+	checks := map[string]any{
+		"typed map":     got["by_key"].(map[string]any)["primary"],
+		"any map":       meta["creds"],
+		"slice in map":  meta["list"].([]any)[0],
+		"top-level map": e["meta"].(map[string]any)["creds"],
+		"int keys":      e["ids"].(map[string]any)["7"],
+	}
+	for name, v := range checks {
+		m, _ := v.(map[string]any)
+		if m["user"] != "ada" {
+			t.Errorf("%s: expected untagged members kept, got %v", name, v)
+		}
+		if _, ok := m["password"]; ok {
+			t.Errorf("%s: expected log:\"-\" members omitted, got %v", name, m)
+		}
+	}
+	if got["id"] != "s1" || meta["n"] != float64(1) {
+		t.Errorf("Expected other values kept, got %v", got)
+	}
+	if s.Meta["creds"].(credentials).Password != "hunter2" {
+		t.Error("Filtering should not modify the logged value")
+	}
+}
+
+type emptyMembers struct {
+	List  []string     `json:"list,omitempty"`
+	Set   map[int]bool `json:"set,omitempty"`
+	Meta  auditMeta    `json:"meta,omitempty"`
+	Count int          `json:"count,omitempty"`
+	Creds credentials  `json:"creds"`
+}
+
+func TestStructTagsOmitEmptyLikeJSON(t *testing.T) {
+	v := emptyMembers{List: []string{}, Set: map[int]bool{}}
+	got := omitTagged(v).(Fields)
+	for _, key := range []string{"list", "set", "count"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("Expected empty %s omitted like encoding/json, got %v", key, got)
+		}
+	}
+	if _, ok := got["meta"]; !ok {
+		t.Errorf("Expected zero structs kept like encoding/json, got %v", got)
+	}
+}
+
+// node is a cyclic value with tagged members.
+type node struct {
+	Name string `json:"name"`
+	Next *node  `json:"next"`
+	Key  string `log:"-"`
+}
+
+func TestStructTagsCyclicValues(t *testing.T) {
+	n := &node{Name: "a", Key: "k"}
+	n.Next = n
+	if got, ok := omitTagged(n).(Fields); !ok || got["name"] != "a" || got["next"] != nil {
+		t.Errorf("Expected the cycle cut off, got %v", got)
+	}
+	m := map[string]any{"node": n}
+	m["a"], m["b"] = m, m
+	got, ok := omitTagged(m).(Fields)
+	if !ok || got["node"].(Fields)["name"] != "a" || got["a"] != nil || got["b"] != nil {
+		t.Errorf("Expected cyclic maps cut off, got %v", got)
+	}
+}
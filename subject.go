@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/phuslu/log"
+)
+
+// SubjectField is the field holding the hashed data-subject identifier
+// attached by Logger.WithSubject.
+const SubjectField = "subject"
+
+// SubjectLocator is implemented by sinks that can tell where they stored
+// the entries of a data subject, such as a remote sink querying its
+// backend. Logger.LocateSubject asks every locator in the writer chain.
+type SubjectLocator interface {
+	// LocateSubject returns the locations, such as file paths or index
+	// names, holding entries whose SubjectField is hash.
+	LocateSubject(hash string) ([]string, error)
+}
+
+// WithSubjectKey sets the key hashing subject identifiers, as by
+// Logger.SetSubjectKey.
+func WithSubjectKey(key []byte) Option {
+	return optionFunc(func(o *options) { o.subjectKey = key })
+}
+
+// SetSubjectKey sets the HMAC key hashing the subject identifiers passed to
+// WithSubject, for l and every logger derived or named from the same root.
+// Without a key, identifiers are hashed with plain SHA-256, which anyone
+// can reverse for guessable identifiers such as email addresses. Changing
+// the key makes earlier entries unfindable by LocateSubject.
+func (l *Logger) SetSubjectKey(key []byte) {
+	l.state.tree.subject.Store(&key)
+}
+
+// SubjectHash returns the value of SubjectField for the data subject id.
+func (l *Logger) SubjectHash(id string) string {
+	var key []byte
+	if k := l.state.tree.subject.Load(); k != nil {
+		key = *k
+	}
+	return hashValue(key, id)
+}
+
+// WithSubject returns a derived Logger tagging its entries with the hashed
+// identifier of the data subject they concern, such as a user ID, so a
+// right-to-erasure request can find them with LocateSubject without the
+// identifier itself appearing in the logs. With a field allowlist,
+// SubjectField must be allowed.
+func (l *Logger) WithSubject(id string) *Logger {
+	return l.WithFields(Fields{SubjectField: l.SubjectHash(id)})
+}
+
+// LocateSubject enumerates the sinks and files holding entries tagged with
+// the data subject id by WithSubject: it searches the files written by the
+// writer chain, including rotated backups, and asks every SubjectLocator.
+// Encrypted files cannot be searched and are skipped. The locations are
+// sorted; errors from individual files or locators are joined.
+func (l *Logger) LocateSubject(id string) ([]string, error) {
+	hash := l.SubjectHash(id)
+	seen := make(map[string]bool)
+	var errs []error
+	locateSubject(l.logger.Writer, hash, seen, &errs)
+	locations := make([]string, 0, len(seen))
+	for loc := range seen {
+		locations = append(locations, loc)
+	}
+	sort.Strings(locations)
+	return locations, errors.Join(errs...)
+}
+
+// locateSubject adds the locations of hash in the writer chain starting at
+// w to seen. The writers behind a SubjectLocator or an EncryptingWriter are
+// not searched.
+func locateSubject(w any, hash string, seen map[string]bool, errs *[]error) {
+	add := func(locs []string, err error) {
+		for _, loc := range locs {
+			seen[loc] = true
+		}
+		if err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+	pruneWriters(w, func(w any) bool {
+		switch w := w.(type) {
+		case *EncryptingWriter:
+			return false
+		case SubjectLocator:
+			add(w.LocateSubject(hash))
+			return false
+		case *log.FileWriter:
+			add(searchFiles(w.Filename, hash))
+		case *os.File:
+			if !isStdStream(w) {
+				add(searchFiles(w.Name(), hash))
+			}
+		}
+		return true
+	})
+}
+
+// searchFiles returns the files among name and its rotated backups that
+// contain hash. Backups are named like name with a dot and a timestamp
+// before the extension, as log.FileWriter names them.
+func searchFiles(name, hash string) ([]string, error) {
+	ext := filepath.Ext(name)
+	backups, err := filepath.Glob(globEscape(strings.TrimSuffix(name, ext)) + ".[0-9]*" + globEscape(ext))
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	var errs []error
+	for _, m := range append([]string{name}, backups...) {
+		if fi, err := os.Lstat(m); err != nil || !fi.Mode().IsRegular() {
+			continue // the current file is a symlink to a timestamped one
+		}
+		ok, err := fileContains(m, hash)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ok {
+			found = append(found, m)
+		}
+	}
+	return found, errors.Join(errs...)
+}
+
+// globEscape escapes the metacharacters of filepath.Match in s.
+func globEscape(s string) string {
+	return globEscaper.Replace(s)
+}
+
+var globEscaper = strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`)
+
+func fileContains(name, s string) (bool, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		if bytes.Contains(sc.Bytes(), []byte(s)) {
+			return true, nil
+		}
+	}
+	return false, sc.Err()
+}
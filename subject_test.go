@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phuslu/log"
+)
+
+type indexLocator struct {
+	log.Writer
+	index string
+}
+
+func (l indexLocator) LocateSubject(hash string) ([]string, error) {
+	return []string{l.index + "?subject=" + hash}, nil
+}
+
+func TestLocateSubject(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "app.log")
+	rotated := filepath.Join(dir, "app.2024-05-01T00-00-00.log")
+	other := filepath.Join(dir, "app.2024-04-01T00-00-00.log")
+	os.WriteFile(other, []byte(`{"message":"unrelated"}`+"\n"), 0o644)
+	f, err := os.Create(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	writers := log.MultiEntryWriter{&log.IOWriter{Writer: f}, indexLocator{Writer: &log.IOWriter{Writer: &syncBuffer{}}, index: "es://logs"}}
+	logger := NewLogger(WithWriter(&writers), WithSubjectKey([]byte("erasure-key")))
+	hash := logger.SubjectHash("user-42")
+	os.WriteFile(rotated, []byte(`{"subject":"`+hash+`","message":"old"}`+"\n"), 0o644)
+
+	logger.WithSubject("user-42").Info("profile updated")
+	logger.WithSubject("user-7").Info("profile viewed")
+	data, _ := os.ReadFile(current)
+	if strings.Contains(string(data), "user-42") || !strings.Contains(string(data), `"subject":"`+hash+`"`) {
+		t.Fatalf("Expected only the hashed subject in entries, got %q", data)
+	}
+
+	locations, err := logger.LocateSubject("user-42")
+	if err != nil {
+		t.Fatalf("LocateSubject failed: %v", err)
+	}
+	want := []string{rotated, current, "es://logs?subject=" + hash}
+	if strings.Join(locations, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected locations:\n got %v\nwant %v", locations, want)
+	}
+	if locations, _ := logger.LocateSubject("user-1"); len(locations) != 1 {
+		t.Errorf("Expected only the locator for an unknown subject, got %v", locations)
+	}
+	if unkeyed := NewLogger(WithWriter(&writers)).SubjectHash("user-42"); unkeyed == hash {
+		t.Error("Expected the subject key to change the hash")
+	}
+}
+
+func TestLocateSubjectSplitSinks(t *testing.T) {
+	dir := t.TempDir()
+	out := &log.FileWriter{Filename: filepath.Join(dir, "app.log")}
+	errOut := &log.FileWriter{Filename: filepath.Join(dir, "app-error.log")}
+	defer out.Close()
+	defer errOut.Close()
+	logger := NewLogger(WithOutput(out), WithErrorOutput(errOut), WithSubjectKey([]byte("erasure-key")))
+	hash := logger.SubjectHash("user-42")
+	// Another sink's file sharing the base name must not be searched.
+	audit := filepath.Join(dir, "app-audit.log")
+	os.WriteFile(audit, []byte(`{"subject":"`+hash+`"}`+"\n"), 0o644)
+
+	logger.WithSubject("user-42").Error("payment failed")
+	locations, err := logger.LocateSubject("user-42")
+	if err != nil {
+		t.Fatalf("LocateSubject failed: %v", err)
+	}
+	if len(locations) != 1 || filepath.Base(locations[0]) == "app-audit.log" || !strings.HasPrefix(filepath.Base(locations[0]), "app-error.") {
+		t.Errorf("Expected only the error sink's file, got %v", locations)
+	}
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// subscribers holds the Subscribe channels of a logger tree.
+type subscribers struct {
+	active atomic.Int32 // len(subs), read on every entry
+	mu     sync.Mutex
+	subs   map[*subscription]struct{}
+}
+
+type subscription struct {
+	filter func(Entry) bool
+	ch     chan Entry
+	record func(Entry) // called synchronously instead of sending on ch
+}
+
+// Subscribe returns a channel receiving the entries of l and of every
+// logger derived or named from the same root, from now until cancel is
+// called or the logger is closed, at which point the channel is closed.
+// Only entries for which filter returns true are sent; a nil filter
+// selects all. Entries are those that pass the level, sampling and rate
+// limiting checks.
+//
+// Entries are delivered on a buffered channel; when the consumer falls
+// behind, entries are dropped rather than slowing down logging.
+// Subscribing costs nothing until the first subscriber, then one message
+// formatting per entry.
+func (l *Logger) Subscribe(filter func(Entry) bool) (entries <-chan Entry, cancel func()) {
+	s := &l.state.tree.subs
+	sub := &subscription{filter: filter, ch: make(chan Entry, 256)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l.state.tree.closed.Load() {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	if s.subs == nil {
+		s.subs = make(map[*subscription]struct{})
+	}
+	s.subs[sub] = struct{}{}
+	s.active.Store(int32(len(s.subs)))
+	return sub.ch, func() { s.remove(sub) }
+}
+
+func (s *subscribers) remove(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		sub.close()
+		s.active.Store(int32(len(s.subs)))
+	}
+}
+
+// closeAll ends every subscription.
+func (s *subscribers) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		sub.close()
+	}
+	s.subs = nil
+	s.active.Store(0)
+}
+
+func (sub *subscription) close() {
+	if sub.ch != nil {
+		close(sub.ch)
+	}
+}
+
+// record calls fn synchronously with every entry of the tree of l until
+// cancel is called or the logger is closed. Unlike Subscribe, no entry is
+// ever dropped.
+func (l *Logger) record(fn func(Entry)) (cancel func()) {
+	s := &l.state.tree.subs
+	sub := &subscription{record: fn}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l.state.tree.closed.Load() {
+		return func() {}
+	}
+	if s.subs == nil {
+		s.subs = make(map[*subscription]struct{})
+	}
+	s.subs[sub] = struct{}{}
+	s.active.Store(int32(len(s.subs)))
+	return func() { s.remove(sub) }
+}
+
+// publish sends an entry with the formatted message text to the
+// subscribers of the tree of l, sanitized and with registered secrets
+// masked as in the sink. A zero time stands for now.
+func (l *Logger) publish(at time.Time, level LogLevel, text string) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	secrets := l.secrets()
+	e := Entry{Time: at, Level: level, Message: secrets.mask(sanitizeText(text)), Logger: l.name}
+	if len(l.fields) > 0 {
+		e.Fields = make(Fields, len(l.fields))
+		for k, v := range l.fields {
+			if str, ok := v.(string); ok {
+				v = secrets.mask(str)
+			}
+			e.Fields[k] = v
+		}
+	}
+	s := &l.state.tree.subs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+		if sub.record != nil {
+			sub.record(e)
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
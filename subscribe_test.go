@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	db := logger.Named("db")
+	entries, cancel := logger.Subscribe(func(e Entry) bool { return e.Level >= LogLevelWarning })
+	defer cancel()
+
+	logger.Info("ignored")
+	db.WithFields(Fields{"table": "users"}).Warning("slow query %dms", 250)
+
+	e := <-entries
+	if e.Message != "slow query 250ms" || e.Level != LogLevelWarning || e.Logger != "db" {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+	if e.Fields["table"] != "users" || e.Time.IsZero() {
+		t.Errorf("Expected fields and time, got %+v", e)
+	}
+	select {
+	case e := <-entries:
+		t.Errorf("Expected no more entries, got %+v", e)
+	default:
+	}
+}
+
+func TestSubscribeCancelAndClose(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	first, cancel := logger.Subscribe(nil)
+	cancel()
+	cancel()
+	if _, ok := <-first; ok {
+		t.Error("cancel should close the channel")
+	}
+	logger.Info("no subscribers")
+
+	second, _ := logger.Subscribe(nil)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, ok := <-second; ok {
+		t.Error("Close should close the channel")
+	}
+}
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	logger, _ := testLogger(LogLevelInfo)
+	entries, cancel := logger.Subscribe(nil)
+	defer cancel()
+	for i := 0; i < 1000; i++ {
+		logger.Info("entry %d", i)
+	}
+	if n := len(entries); n != cap(entries) {
+		t.Errorf("Expected a full buffer, got %d", n)
+	}
+}
+
+// countingStringer counts its String calls.
+type countingStringer struct{ n *int }
+
+func (s countingStringer) String() string {
+	*s.n++
+	return "token sk-live-1234\x00"
+}
+
+func TestSubscribeMatchesSink(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.RegisterSecrets("sk-live-1234")
+	entries, cancel := logger.Subscribe(nil)
+	defer cancel()
+
+	var calls int
+	logger.WithFields(Fields{"auth": "Bearer sk-live-1234"}).Info("%v", countingStringer{&calls})
+
+	e := <-entries
+	if calls != 1 {
+		t.Errorf("Expected the argument formatted once, got %d calls", calls)
+	}
+	if want := "token " + Redacted + "�"; e.Message != want {
+		t.Errorf("Expected the published message %q, got %q", want, e.Message)
+	}
+	if e.Fields["auth"] != "Bearer "+Redacted {
+		t.Errorf("Expected the secret masked in fields, got %v", e.Fields["auth"])
+	}
+	var sink map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &sink); err != nil || sink["message"] != e.Message {
+		t.Errorf("Expected the sink message %q, got %v (%v)", e.Message, sink["message"], err)
+	}
+}
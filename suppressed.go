@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SuppressionStats counts the entries that were logged but never reached a
+// sink.
+type SuppressionStats struct {
+	// Sampled and RateLimited are the entries dropped by the sampler and
+	// the rate limiter, by level.
+	Sampled     map[LogLevel]uint64
+	RateLimited map[LogLevel]uint64
+
+	// Dropped is the number of entries discarded by full AsyncWriter
+	// queues, SpoolWriter directories and open circuit breakers.
+	Dropped uint64
+}
+
+// Suppressed returns the entries suppressed so far by l and every logger
+// derived or named from the same root.
+func (l *Logger) Suppressed() SuppressionStats {
+	s := SuppressionStats{
+		Sampled:     make(map[LogLevel]uint64, len(levelNames)),
+		RateLimited: make(map[LogLevel]uint64, len(levelNames)),
+		Dropped:     droppedEntries(l.logger.Writer),
+	}
+	for i := range levelNames {
+		level := LogLevel(i - 1)
+		s.Sampled[level] = l.state.tree.sampled[i].Load()
+		s.RateLimited[level] = l.state.tree.limited[i].Load()
+	}
+	return s
+}
+
+// ReportSuppressed logs a summary of the entries suppressed in each
+// interval (default 1m) until ctx is done or l is closed, so sampling, rate
+// limiting and full queues never hide entries silently:
+//
+//	logging: suppressed 1423 debug entries in last 60s
+//	logging: dropped 12 entries in last 60s
+//
+// The summaries are Warning entries with the sampled and rate_limited
+// counts and the suppressed_level, or the dropped count; they bypass the
+// level, sampler and rate limiter of l. Quiet intervals are not reported.
+func (l *Logger) ReportSuppressed(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	last := l.Suppressed()
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if l.state.tree.closed.Load() {
+					return
+				}
+				last = l.reportSuppressed(last, interval)
+			}
+		}
+	}()
+}
+
+// reportSuppressed logs what was suppressed since last and returns the
+// current counts.
+func (l *Logger) reportSuppressed(last SuppressionStats, interval time.Duration) SuppressionStats {
+	now := l.Suppressed()
+	window := fmt.Sprintf("%gs", interval.Seconds())
+	for i, name := range levelNames {
+		level := LogLevel(i - 1)
+		sampled := now.Sampled[level] - last.Sampled[level]
+		limited := now.RateLimited[level] - last.RateLimited[level]
+		if sampled+limited == 0 {
+			continue
+		}
+		l.logger.Warn().
+			Str("suppressed_level", name).
+			Uint64("sampled", sampled).
+			Uint64("rate_limited", limited).
+			Msgf("logging: suppressed %d %s entries in last %s", sampled+limited, name, window)
+	}
+	if dropped := now.Dropped - last.Dropped; dropped > 0 {
+		l.logger.Warn().
+			Uint64("dropped", dropped).
+			Msgf("logging: dropped %d entries in last %s", dropped, window)
+	}
+	return now
+}
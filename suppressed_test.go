@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// droppingWriter reports a fixed number of dropped entries.
+type droppingWriter struct {
+	log.Writer
+	dropped uint64
+}
+
+func (w *droppingWriter) Dropped() uint64 { return w.dropped }
+
+func TestSuppressedCounts(t *testing.T) {
+	logger, _ := testLogger(LogLevelDebug)
+	logger.SetSampler(SamplerFunc(func(level LogLevel) bool { return level != LogLevelDebug }))
+	logger.SetRateLimiter(NewRateLimiter(2, time.Hour))
+	for range 5 {
+		logger.Debug("sampled out")
+		logger.Warning("disk full")
+	}
+	logger.Error("once")
+
+	s := logger.Suppressed()
+	if s.Sampled[LogLevelDebug] != 5 || s.RateLimited[LogLevelWarning] != 3 {
+		t.Errorf("Expected 5 debug entries sampled and 3 warnings rate limited, got %+v", s)
+	}
+	if s.Sampled[LogLevelError] != 0 || s.RateLimited[LogLevelError] != 0 || s.Dropped != 0 {
+		t.Errorf("Expected nothing else suppressed, got %+v", s)
+	}
+}
+
+func TestReportSuppressed(t *testing.T) {
+	logger, buf := testLogger(LogLevelError)
+	logger.SetSampler(SamplerFunc(func(level LogLevel) bool { return level != LogLevelDebug }))
+	dw := &droppingWriter{Writer: logger.logger.Writer}
+	logger.logger.Writer = dw
+
+	last := logger.Suppressed()
+	last = logger.reportSuppressed(last, time.Minute)
+	if buf.Len() != 0 {
+		t.Fatalf("Expected a quiet interval not reported, got %q", buf.String())
+	}
+
+	logger.SetLogLevel(LogLevelDebug)
+	for range 1423 {
+		logger.Debug("noisy")
+	}
+	logger.SetLogLevel(LogLevelError)
+	dw.dropped = 12
+	last = logger.reportSuppressed(last, time.Minute)
+
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %q", buf.String())
+	}
+	if entries[0]["message"] != "logging: suppressed 1423 debug entries in last 60s" ||
+		entries[0]["level"] != "warn" || entries[0]["suppressed_level"] != "debug" ||
+		entries[0]["sampled"] != float64(1423) || entries[0]["rate_limited"] != float64(0) {
+		t.Errorf("Unexpected suppression summary %v", entries[0])
+	}
+	if entries[1]["message"] != "logging: dropped 12 entries in last 60s" || entries[1]["dropped"] != float64(12) {
+		t.Errorf("Unexpected drop summary %v", entries[1])
+	}
+
+	buf.Reset()
+	logger.reportSuppressed(last, time.Minute)
+	if buf.Len() != 0 {
+		t.Errorf("Expected only new suppression reported, got %q", buf.String())
+	}
+}
+
+func TestReportSuppressedPeriodically(t *testing.T) {
+	sink := new(syncBuffer)
+	logger := NewLogger(WithOutput(sink), WithFormat(FormatJSON), LogLevelDebug,
+		WithSampler(SamplerFunc(func(level LogLevel) bool { return false })))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger.ReportSuppressed(ctx, 10*time.Millisecond)
+
+	logger.Info("sampled out")
+	waitFor(t, "a summary", func() bool {
+		return strings.Contains(string(sink.Bytes()), "suppressed 1 info entries in last 0.01s")
+	})
+}
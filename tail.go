@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// TailHandler returns an http.Handler streaming the entries of w as
+// Server-Sent Events, so a service can be tailed from a browser with
+// EventSource: first the kept entries, then live ones until the client
+// disconnects. Each event's data is one JSON entry.
+//
+// The query selects entries: "level" sets the minimum level and every
+// other parameter requires a field with that value, for example
+// ?level=warning&component=db. A client too slow to keep up misses entries
+// rather than slowing down logging.
+func (w *RingWriter) TailHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		filter, err := parseTailFilter(r.URL.Query())
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			http.Error(rw, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		recent, entries, cancel := w.subscribe(256)
+		defer cancel()
+
+		h := rw.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no")
+		rw.WriteHeader(http.StatusOK)
+		for _, e := range recent {
+			if filter.match(e) {
+				writeEvent(rw, e)
+			}
+		}
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-entries:
+				if !ok {
+					return
+				}
+				if filter.match(e) {
+					writeEvent(rw, e)
+					flusher.Flush()
+				}
+			}
+		}
+	})
+}
+
+// tailFilter selects the entries streamed by TailHandler.
+type tailFilter struct {
+	level  LogLevel
+	fields map[string]string
+}
+
+func parseTailFilter(q url.Values) (tailFilter, error) {
+	f := tailFilter{level: LogLevelDebug, fields: make(map[string]string)}
+	for key, values := range q {
+		if key == "level" {
+			level, err := ParseLevel(values[0])
+			if err != nil {
+				return f, err
+			}
+			f.level = level
+			continue
+		}
+		f.fields[key] = values[0]
+	}
+	return f, nil
+}
+
+func (f tailFilter) match(entry []byte) bool {
+	if f.level == LogLevelDebug && len(f.fields) == 0 {
+		return true
+	}
+	var fields map[string]any
+	if json.Unmarshal(entry, &fields) != nil {
+		return false
+	}
+	if s, _ := fields["level"].(string); s != "" {
+		if level, err := parseLevel(s); err == nil && level < f.level {
+			return false
+		}
+	}
+	for key, want := range f.fields {
+		v, ok := fields[key]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+func writeEvent(rw http.ResponseWriter, entry []byte) {
+	if n := len(entry); n > 0 && entry[n-1] == '\n' {
+		entry = entry[:n-1]
+	}
+	_, _ = fmt.Fprintf(rw, "data: %s\n\n", entry)
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTailHandlerStreams(t *testing.T) {
+	logger := NewLogger(WithOutput(new(syncBuffer)), WithFormat(FormatJSON))
+	ring := logger.SetRingBuffer(10)
+	logger.Info("old info")
+	logger.WithFields(Fields{"component": "db"}).Warning("old warning")
+
+	srv := httptest.NewServer(ring.TailHandler())
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?level=warning&component=db", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+
+	events := bufio.NewScanner(resp.Body)
+	next := func() string {
+		for events.Scan() {
+			if line := events.Text(); strings.HasPrefix(line, "data: ") {
+				return line
+			}
+		}
+		t.Fatalf("Stream ended: %v", events.Err())
+		return ""
+	}
+	if e := next(); !strings.Contains(e, "old warning") {
+		t.Errorf("Expected the kept warning first, got %q", e)
+	}
+	logger.WithFields(Fields{"component": "http"}).Error("other component")
+	logger.WithFields(Fields{"component": "db"}).Info("too low")
+	logger.WithFields(Fields{"component": "db"}).Error("live error")
+	if e := next(); !strings.Contains(e, "live error") {
+		t.Errorf("Expected only the matching live entry, got %q", e)
+	}
+}
+
+func TestTailHandlerRejectsBadLevel(t *testing.T) {
+	ring := NewRingWriter(nil, 10)
+	rec := httptest.NewRecorder()
+	ring.TailHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?level=loud", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
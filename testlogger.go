@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// TestNameField is the field NewTestLogger adds to every entry, holding the
+// name of the test.
+const TestNameField = "test"
+
+// TestBuffer collects the output of a test logger. It is safe for
+// concurrent use.
+type TestBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (b *TestBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the output collected so far.
+func (b *TestBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Entries decodes the JSON entries collected so far. Lines that are not
+// JSON, such as console output, are skipped.
+func (b *TestBuffer) Entries() Entries {
+	var entries Entries
+	for _, line := range bytes.Split([]byte(b.String()), []byte("\n")) {
+		if e, err := decodeEntry(line); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// NewTestLogger returns a logger for the test t writing JSON to a buffer of
+// its own, tagging every entry with TestNameField, and closed when the test
+// ends. Each call builds an independent logger, with its own level and
+// settings, so parallel tests and subtests can each have one without
+// sharing or racing on a buffer. It logs at LogLevelDebug unless opts say
+// otherwise; an output set by opts is ignored.
+func NewTestLogger(t testing.TB, opts ...Option) (*Logger, *TestBuffer) {
+	t.Helper()
+	buf := new(TestBuffer)
+	logger := newTestLogger(t, buf, append([]Option{LogLevelDebug, WithFormat(FormatJSON)}, opts...))
+	return logger.WithFields(Fields{TestNameField: t.Name()}), buf
+}
+
+// NewQuietTestLogger returns a logger for the test t that buffers its
+// entries and hands them to t.Log only if the test fails, so passing tests
+// stay quiet while failing ones show everything that was logged. It logs at
+// LogLevelDebug in uncolored console format unless opts say otherwise, and
+// is closed when the test ends.
+func NewQuietTestLogger(t testing.TB, opts ...Option) *Logger {
+	t.Helper()
+	buf := new(TestBuffer)
+	logger := newTestLogger(t, buf, append([]Option{LogLevelDebug, WithColor(false)}, opts...))
+	t.Cleanup(func() {
+		if out := buf.String(); t.Failed() && out != "" {
+			t.Logf("logs:\n%s", out)
+		}
+	})
+	return logger
+}
+
+// newTestLogger returns a logger writing to buf, closed when t ends.
+func newTestLogger(t testing.TB, buf *TestBuffer, opts []Option) *Logger {
+	logger := NewLogger(append(opts, WithOutput(buf))...)
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// cleanupTB runs cleanups on demand and records what is logged.
+type cleanupTB struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (c *cleanupTB) Name() string     { return "TestFake" }
+func (c *cleanupTB) Helper()          {}
+func (c *cleanupTB) Failed() bool     { return c.failed }
+func (c *cleanupTB) Cleanup(f func()) { c.cleanups = append(c.cleanups, f) }
+func (c *cleanupTB) Logf(format string, args ...any) {
+	c.logs = append(c.logs, fmt.Sprintf(format, args...))
+}
+
+// finish runs the cleanups last added first, like testing does.
+func (c *cleanupTB) finish() {
+	for i := len(c.cleanups) - 1; i >= 0; i-- {
+		c.cleanups[i]()
+	}
+}
+
+func TestQuietTestLoggerDumpsOnlyOnFailure(t *testing.T) {
+	passing := &cleanupTB{}
+	NewQuietTestLogger(passing).Debug("connecting")
+	passing.finish()
+	if len(passing.logs) != 0 {
+		t.Errorf("Expected a passing test to stay quiet, got %v", passing.logs)
+	}
+
+	failing := &cleanupTB{failed: true}
+	logger := NewQuietTestLogger(failing, WithFormat(FormatJSON))
+	logger.Debug("connecting")
+	logger.WithFields(Fields{"attempt": 3}).Error("gave up")
+	failing.finish()
+	if len(failing.logs) != 1 {
+		t.Fatalf("Expected the logs dumped once, got %v", failing.logs)
+	}
+	out := strings.TrimPrefix(failing.logs[0], "logs:\n")
+	entries := decodeEntries(t, out)
+	if len(entries) != 2 || entries[0]["message"] != "connecting" || entries[1]["attempt"] != float64(3) {
+		t.Errorf("Expected every entry in the dump, got %q", out)
+	}
+}
+
+func TestQuietTestLoggerInRealTest(t *testing.T) {
+	logger := NewQuietTestLogger(t)
+	logger.Info("only shown if this test fails")
+}
+
+func TestTestLoggerIsolatedUnderParallel(t *testing.T) {
+	for i := range 8 {
+		t.Run(fmt.Sprintf("sub%d", i), func(t *testing.T) {
+			t.Parallel()
+			logger, buf := NewTestLogger(t)
+			if i%2 == 0 {
+				logger.SetLogLevel(LogLevelError)
+			}
+			for j := range 50 {
+				logger.WithFields(Fields{"j": j}).Error("entry")
+				logger.Info("info")
+			}
+			entries := buf.Entries()
+			want := 100
+			if i%2 == 0 {
+				want = 50
+			}
+			if len(entries) != want {
+				t.Fatalf("Expected %d entries in this test's buffer, got %d", want, len(entries))
+			}
+			for _, e := range entries {
+				if e.Fields[TestNameField] != t.Name() {
+					t.Fatalf("Expected %s=%q, got %v", TestNameField, t.Name(), e.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestTestLoggerClosedAtCleanup(t *testing.T) {
+	tb := &cleanupTB{}
+	logger, buf := NewTestLogger(tb, WithLevel(LogLevelWarning))
+	logger.Info("filtered")
+	logger.Warning("kept")
+	tb.finish()
+	if !logger.state.tree.closed.Load() {
+		t.Error("Expected the logger closed at the end of the test")
+	}
+	entries := buf.Entries()
+	if len(entries) != 1 || entries[0].Message != "kept" || entries[0].Fields[TestNameField] != "TestFake" {
+		t.Errorf("Expected only the warning, tagged with the test, got %+v", entries)
+	}
+}
@@ -0,0 +1,178 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// ErrWriteTimeout is returned when a sink does not complete a write within
+// its timeout, or is still stuck in an earlier one.
+var ErrWriteTimeout = errors.New("logging: write timed out")
+
+// writeGuard runs writes with a deadline. A write that times out keeps
+// running in the background, since writes cannot be interrupted; until it
+// returns, further writes fail at once instead of piling up behind it.
+type writeGuard struct {
+	mu       sync.Mutex
+	hung     bool
+	timeouts atomic.Uint64
+}
+
+// run calls write, waiting at most timeout for it.
+func (g *writeGuard) run(timeout time.Duration, write func() error) error {
+	g.mu.Lock()
+	if g.hung {
+		g.mu.Unlock()
+		g.timeouts.Add(1)
+		return ErrWriteTimeout
+	}
+	g.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() { done <- write() }()
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+	}
+	g.mu.Lock()
+	g.hung = true
+	g.mu.Unlock()
+	g.timeouts.Add(1)
+	go func() {
+		<-done
+		g.mu.Lock()
+		g.hung = false
+		g.mu.Unlock()
+	}()
+	return ErrWriteTimeout
+}
+
+func (g *writeGuard) healthy() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.hung {
+		return ErrWriteTimeout
+	}
+	return nil
+}
+
+// TimeoutWriter is a log.Writer that bounds each write to Writer by
+// Timeout, so a hung NFS mount or a TCP black hole cannot stall the
+// goroutine flushing entries forever. A write that times out is abandoned
+// to finish in the background, and writes fail with ErrWriteTimeout until
+// it does.
+type TimeoutWriter struct {
+	// Writer is the destination of entries.
+	Writer log.Writer
+
+	// Timeout bounds each write. Defaults to 5s.
+	Timeout time.Duration
+
+	guard writeGuard
+}
+
+// NewTimeoutWriter returns a TimeoutWriter bounding writes to w by timeout.
+func NewTimeoutWriter(w log.Writer, timeout time.Duration) *TimeoutWriter {
+	return &TimeoutWriter{Writer: w, Timeout: timeout}
+}
+
+// WriteEntry implements log.Writer. The entry is copied, since the write
+// may outlive the call.
+func (w *TimeoutWriter) WriteEntry(e *log.Entry) (int, error) {
+	b := getEntryBuffer()
+	_, _ = log.IOWriter{Writer: b}.WriteEntry(e)
+	level := e.Level
+	var n int
+	err := w.guard.run(writeTimeout(w.Timeout), func() error {
+		defer putEntryBuffer(b)
+		c := log.NewContext(*b)
+		c.Level = level
+		var err error
+		n, err = w.Writer.WriteEntry(c)
+		return err
+	})
+	if err == ErrWriteTimeout {
+		return 0, err
+	}
+	return n, err
+}
+
+// Timeouts returns the number of writes that failed with ErrWriteTimeout.
+func (w *TimeoutWriter) Timeouts() uint64 {
+	return w.guard.timeouts.Load()
+}
+
+// Healthy implements HealthChecker. The writer is unhealthy while a write
+// is stuck.
+func (w *TimeoutWriter) Healthy() error {
+	return w.guard.healthy()
+}
+
+// Flush flushes Writer if it buffers entries, waiting at most Timeout.
+func (w *TimeoutWriter) Flush() error {
+	return w.guard.run(writeTimeout(w.Timeout), func() error { return flushWriter(w.Writer) })
+}
+
+// Close closes Writer if it is closable.
+func (w *TimeoutWriter) Close() error {
+	return closeWriter(w.Writer)
+}
+
+// TimeoutSink is a BatchSink that bounds each batch written to Sink by
+// Timeout, like TimeoutWriter.
+type TimeoutSink struct {
+	// Sink receives the batches.
+	Sink BatchSink
+
+	// Timeout bounds each batch. Defaults to 5s.
+	Timeout time.Duration
+
+	guard writeGuard
+}
+
+// NewTimeoutSink returns a TimeoutSink bounding batches to sink by timeout.
+func NewTimeoutSink(sink BatchSink, timeout time.Duration) *TimeoutSink {
+	return &TimeoutSink{Sink: sink, Timeout: timeout}
+}
+
+// WriteBatch implements BatchSink. The entries are copied, since the write
+// may outlive the call.
+func (s *TimeoutSink) WriteBatch(entries [][]byte) error {
+	batch := make([][]byte, len(entries))
+	buf := make([]byte, 0, batchBytes(entries))
+	for i, e := range entries {
+		buf = append(buf, e...)
+		batch[i] = buf[len(buf)-len(e):]
+	}
+	return s.guard.run(writeTimeout(s.Timeout), func() error { return s.Sink.WriteBatch(batch) })
+}
+
+// Timeouts returns the number of batches that failed with ErrWriteTimeout.
+func (s *TimeoutSink) Timeouts() uint64 {
+	return s.guard.timeouts.Load()
+}
+
+// Healthy implements HealthChecker. The sink is unhealthy while a write is
+// stuck.
+func (s *TimeoutSink) Healthy() error {
+	return s.guard.healthy()
+}
+
+// Close closes Sink if it is closable.
+func (s *TimeoutSink) Close() error {
+	return closeWriter(s.Sink)
+}
+
+func writeTimeout(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 5 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutWriterAbandonsHungWrites(t *testing.T) {
+	sink := blockingWriter{release: make(chan struct{})}
+	logger, _ := testLogger(LogLevelInfo)
+	tw := NewTimeoutWriter(sink, 10*time.Millisecond)
+	logger.SetWriter(tw)
+
+	start := time.Now()
+	logger.Info("stuck")
+	logger.Info("fails fast")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Expected hung writes not to stall the logger, took %v", elapsed)
+	}
+	if tw.Timeouts() != 2 {
+		t.Errorf("Expected 2 timeouts, got %d", tw.Timeouts())
+	}
+	if err := logger.Health(); !errors.Is(err, ErrWriteTimeout) {
+		t.Errorf("Expected the stuck writer to be unhealthy, got %v", err)
+	}
+
+	close(sink.release)
+	waitFor(t, "the hung write to return", func() bool { return tw.Healthy() == nil })
+	logger.Info("recovered")
+	if tw.Timeouts() != 2 {
+		t.Errorf("Expected writes to resume once the hung write returned, got %d timeouts", tw.Timeouts())
+	}
+}
+
+func TestTimeoutWriterPassesEntries(t *testing.T) {
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetWriter(NewTimeoutWriter(logger.logger.Writer, time.Second))
+	logger.Warning("delivered")
+	if !strings.Contains(buf.String(), `"level":"warn"`) || !strings.Contains(buf.String(), "delivered") {
+		t.Errorf("Expected the entry to reach the sink unchanged, got %q", buf.String())
+	}
+}
+
+func TestTimeoutSinkCopiesBatch(t *testing.T) {
+	release := make(chan struct{})
+	got := make(chan string, 1)
+	sink := NewTimeoutSink(BatchFunc(func(entries [][]byte) error {
+		<-release
+		got <- string(entries[0])
+		return nil
+	}), 10*time.Millisecond)
+
+	entry := []byte("original")
+	if err := sink.WriteBatch([][]byte{entry}); !errors.Is(err, ErrWriteTimeout) {
+		t.Fatalf("Expected ErrWriteTimeout, got %v", err)
+	}
+	copy(entry, "reused!!")
+	close(release)
+	if s := <-got; s != "original" {
+		t.Errorf("Expected the abandoned write to keep its own copy, got %q", s)
+	}
+}
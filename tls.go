@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// TLSOptions describes the TLS settings of a connection to a collector, so
+// network sinks and level sources need not assume plaintext or build a
+// tls.Config by hand. The zero value verifies the server against the
+// system roots with TLS 1.2 or later. Its fields carry json and
+// mapstructure tags for embedding in an application's config.
+type TLSOptions struct {
+	// CAFile is a PEM bundle of the CAs trusted to sign the server's
+	// certificate, replacing the system roots.
+	CAFile string `json:"ca_file" mapstructure:"ca_file"`
+
+	// CAPEM holds the same bundle inline, added to CAFile if both are set.
+	CAPEM []byte `json:"ca_pem" mapstructure:"ca_pem"`
+
+	// CertFile and KeyFile are the PEM client certificate and key
+	// presented for mutual TLS.
+	CertFile string `json:"cert_file" mapstructure:"cert_file"`
+	KeyFile  string `json:"key_file" mapstructure:"key_file"`
+
+	// ServerName overrides the name verified in the server's certificate
+	// and sent for SNI. Defaults to the host dialed.
+	ServerName string `json:"server_name" mapstructure:"server_name"`
+
+	// MinVersion is the lowest protocol version accepted: "1.2" or "1.3".
+	// Defaults to "1.2".
+	MinVersion string `json:"min_version" mapstructure:"min_version"`
+
+	// InsecureSkipVerify disables verification of the server's
+	// certificate. Use it only in tests.
+	InsecureSkipVerify bool `json:"insecure_skip_verify" mapstructure:"insecure_skip_verify"`
+}
+
+// Config builds the tls.Config described by o, loading the files it names.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+	switch o.MinVersion {
+	case "", "1.2":
+		cfg.MinVersion = tls.VersionTLS12
+	case "1.3":
+		cfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("logging: tls: unsupported min_version %q (valid: 1.2, 1.3)", o.MinVersion)
+	}
+
+	if o.CAFile != "" || len(o.CAPEM) > 0 {
+		pool := x509.NewCertPool()
+		if o.CAFile != "" {
+			pem, err := os.ReadFile(o.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("logging: tls: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("logging: tls: no certificates in %s", o.CAFile)
+			}
+		}
+		if len(o.CAPEM) > 0 && !pool.AppendCertsFromPEM(o.CAPEM) {
+			return nil, fmt.Errorf("logging: tls: no certificates in ca_pem")
+		}
+		cfg.RootCAs = pool
+	}
+
+	switch {
+	case o.CertFile != "" && o.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("logging: tls: client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case o.CertFile != "" || o.KeyFile != "":
+		return nil, fmt.Errorf("logging: tls: cert_file and key_file must be set together")
+	}
+	return cfg, nil
+}
+
+// Client returns an HTTP client using the TLS settings of o, for
+// HTTPLevelSource, Endpoint or an HTTP sink.
+func (o TLSOptions) Client() (*http.Client, error) {
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = cfg
+	return &http.Client{Transport: t, Timeout: 30 * time.Second}, nil
+}
+
+// Dial connects to addr over TLS with the settings of o, for TCP sinks.
+func (o TLSOptions) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	d := tls.Dialer{Config: cfg}
+	return d.DialContext(ctx, network, addr)
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes a self-signed client certificate and its key to
+// dir, returning their paths and the parsed certificate.
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "log-shipper"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	cert, _ = x509.ParseCertificate(der)
+	return certFile, keyFile, cert
+}
+
+func TestTLSOptionsMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCert(t, dir)
+	clients := x509.NewCertPool()
+	clients.AddCert(clientCert)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.TLS = &tls.Config{ClientCAs: clients, ClientAuth: tls.RequireAndVerifyClientCert, MaxVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	opts := TLSOptions{CAPEM: caPEM, CertFile: certFile, KeyFile: keyFile, ServerName: "example.com"}
+	client, err := opts.Client()
+	if err != nil {
+		t.Fatalf("Client failed: %v", err)
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("Expected the mTLS request to succeed, got %v", err)
+	}
+	resp.Body.Close()
+
+	conn, err := opts.Dial(context.Background(), "tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	conn.Close()
+
+	for name, bad := range map[string]TLSOptions{
+		"no client cert": {CAPEM: caPEM, ServerName: "example.com"},
+		"wrong name":     {CAPEM: caPEM, CertFile: certFile, KeyFile: keyFile, ServerName: "logs.example.org"},
+		"min version":    {CAPEM: caPEM, CertFile: certFile, KeyFile: keyFile, ServerName: "example.com", MinVersion: "1.3"},
+	} {
+		if _, err := bad.Dial(context.Background(), "tcp", ts.Listener.Addr().String()); err == nil {
+			t.Errorf("%s: expected the handshake to fail", name)
+		} else if c, err := bad.Client(); err == nil {
+			if resp, err := c.Get(ts.URL); err == nil {
+				resp.Body.Close()
+				t.Errorf("%s: expected the request to fail", name)
+			}
+		}
+	}
+}
+
+func TestTLSOptionsErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	os.WriteFile(empty, nil, 0o600)
+	for name, opts := range map[string]TLSOptions{
+		"min version": {MinVersion: "1.0"},
+		"missing ca":  {CAFile: filepath.Join(dir, "missing.pem")},
+		"empty ca":    {CAFile: empty},
+		"cert only":   {CertFile: empty},
+	} {
+		if _, err := opts.Config(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// correlationHeaders are the request headers HTTPMiddleware keeps in the
+// request context and Transport forwards to outbound requests.
+var correlationHeaders = []string{"X-Request-ID", "Traceparent", "Tracestate"}
+
+type correlationKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID id, which
+// Transport sends as the X-Request-ID header of outbound requests.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return withCorrelation(ctx, http.Header{"X-Request-Id": {id}})
+}
+
+// RequestID returns the request ID carried by ctx, or "".
+func RequestID(ctx context.Context) string {
+	h, _ := ctx.Value(correlationKey{}).(http.Header)
+	return h.Get("X-Request-ID")
+}
+
+// withCorrelation returns a copy of ctx carrying the correlation headers
+// found in h, merged with those ctx already carries.
+func withCorrelation(ctx context.Context, h http.Header) context.Context {
+	prev, _ := ctx.Value(correlationKey{}).(http.Header)
+	var c http.Header
+	for _, name := range correlationHeaders {
+		v := h.Get(name)
+		if v == "" {
+			v = prev.Get(name)
+		}
+		if v == "" {
+			continue
+		}
+		if c == nil {
+			c = make(http.Header, len(correlationHeaders))
+		}
+		c.Set(name, v)
+	}
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// Transport is an http.RoundTripper that logs outbound requests and
+// propagates correlation headers: the request ID and W3C trace context
+// (traceparent, tracestate) carried by the request's context, as stored by
+// HTTPMiddleware or WithRequestID, are added to requests that do not set
+// them, so the logs of every service a request passes through correlate.
+//
+// Requests are logged at Debug level, 5xx responses at Warning and
+// transport errors at Error, with the method, URL without its query,
+// status and duration. Entries use the logger carried by the request's
+// context if it was derived from Logger.
+type Transport struct {
+	// Base performs the requests. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// Logger receives the entries.
+	Logger *Logger
+}
+
+// NewTransport returns a Transport logging to l and sending requests with
+// base.
+func NewTransport(l *Logger, base http.RoundTripper) *Transport {
+	return &Transport{Base: base, Logger: l}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c, ok := req.Context().Value(correlationKey{}).(http.Header); ok {
+		cloned := false
+		for name, values := range c {
+			if req.Header.Get(name) != "" {
+				continue
+			}
+			if !cloned {
+				// A RoundTripper must not modify the caller's request.
+				req = req.Clone(req.Context())
+				cloned = true
+			}
+			req.Header[name] = values
+		}
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	u := *req.URL
+	u.RawQuery, u.User = "", nil
+	fields := Fields{"method": req.Method, "url": u.String(), "duration": time.Since(start)}
+	l := t.Logger.scoped(req.Context())
+	switch {
+	case err != nil:
+		fields["error"] = err
+		l.WithFields(fields).Error("http client request failed")
+	case resp.StatusCode >= 500:
+		fields["status"] = resp.StatusCode
+		l.WithFields(fields).Warning("http client request")
+	case l.enabled(LogLevelDebug):
+		fields["status"] = resp.StatusCode
+		l.WithFields(fields).Debug("http client request")
+	}
+	return resp, err
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransportPropagatesCorrelation(t *testing.T) {
+	var got http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	logger, buf := testLogger(LogLevelInfo)
+	client := &http.Client{Transport: NewTransport(logger, nil)}
+	h := HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, upstream.URL+"/items?token=secret", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("Outbound request failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if req.Header.Get("X-Request-ID") != "" {
+			t.Error("Transport must not modify the caller's request")
+		}
+	}))
+	in := httptest.NewRequest(http.MethodGet, "/", nil)
+	in.Header.Set("X-Request-ID", "req-1")
+	in.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	h.ServeHTTP(httptest.NewRecorder(), in)
+
+	if got.Get("X-Request-ID") != "req-1" || !strings.HasPrefix(got.Get("Traceparent"), "00-0af7") {
+		t.Errorf("Expected correlation headers upstream, got %v", got)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"message":"http client request"`) || !strings.Contains(out, `"status":502`) {
+		t.Errorf("Expected the 5xx response logged, got %q", out)
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("The query should not be logged, got %q", out)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "abc")
+	if RequestID(ctx) != "abc" || RequestID(context.Background()) != "" {
+		t.Errorf("Unexpected request IDs: %q", RequestID(ctx))
+	}
+}
@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError describes a problem with one field of a Config.
+type FieldError struct {
+	// Path locates the field, for example "sinks[1].rotation.max_size".
+	Path string
+	Err  error
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError lists every problem found in a Config.
+type ConfigError struct {
+	Errors []FieldError
+}
+
+func (e *ConfigError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "logging: invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks cfg and returns a *ConfigError listing every problem
+// found, or nil. LoadConfig and NewLoggerFromConfig call it, so a bad
+// config fails when it is loaded rather than when entries are written.
+func (cfg *Config) Validate() error {
+	var errs []FieldError
+	add := func(path string, err error) {
+		errs = append(errs, FieldError{Path: path, Err: err})
+	}
+
+	if cfg.Level != "" {
+		if _, _, _, err := ParseLevelSpec(cfg.Level); err != nil {
+			add("level", errors.Unwrap(err))
+		}
+	}
+	if cfg.Profile != "" {
+		if _, err := parseProfile(cfg.Profile); err != nil {
+			add("profile", err)
+		}
+	}
+	if _, err := parseFormat(cfg.Format, FormatConsole); err != nil {
+		add("format", err)
+	}
+
+	paths := make(map[string]int)
+	for i, sc := range cfg.Sinks {
+		p := fmt.Sprintf("sinks[%d]", i)
+		switch sc.Type {
+		case "stdout", "stderr", "":
+			if sc.Path != "" {
+				add(p+".path", fmt.Errorf("not used by %s sinks", sc.typeName()))
+			}
+			if sc.Rotation != (RotationConfig{}) {
+				add(p+".rotation", fmt.Errorf("not used by %s sinks", sc.typeName()))
+			}
+			if sc.Encryption != (EncryptionConfig{}) {
+				add(p+".encryption", fmt.Errorf("not used by %s sinks", sc.typeName()))
+			}
+		case "file":
+			if sc.Path == "" {
+				add(p+".path", errors.New("required by file sinks"))
+			} else if j, dup := paths[sc.Path]; dup {
+				add(p+".path", fmt.Errorf("%q is also written by sinks[%d]", sc.Path, j))
+			} else {
+				paths[sc.Path] = i
+			}
+		default:
+			add(p+".type", fmt.Errorf("unknown sink type %q (valid: stdout, stderr, file)", sc.Type))
+		}
+		if _, err := parseFormat(sc.Format, FormatConsole); err != nil {
+			add(p+".format", err)
+		}
+		r := sc.Rotation
+		if r.MaxSize < 0 {
+			add(p+".rotation.max_size", errors.New("must not be negative"))
+		}
+		if r.MaxBackups < 0 {
+			add(p+".rotation.max_backups", errors.New("must not be negative"))
+		}
+		if r.MaxBackups > 0 && r.MaxSize == 0 {
+			add(p+".rotation.max_backups", errors.New("has no effect without max_size"))
+		}
+		if e := sc.Encryption; e.KeyID != "" && e.KeyEnv == "" {
+			add(p+".encryption.key_id", errors.New("has no effect without key_env"))
+		} else if len(e.KeyID) > 255 {
+			add(p+".encryption.key_id", errors.New("must be at most 255 bytes"))
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Sampling))
+	for name := range cfg.Sampling {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := "sampling." + name
+		if _, err := parseLevel(name); err != nil {
+			add(p, err)
+		}
+		n := cfg.Sampling[name]
+		if n == 0 {
+			add(p, errors.New("must be at least 1"))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ConfigError{Errors: errs}
+}
+
+func (sc SinkConfig) typeName() string {
+	if sc.Type == "" {
+		return "stdout"
+	}
+	return sc.Type
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := &Config{
+		Level:  "loud",
+		Format: "xml",
+		Sinks: []SinkConfig{
+			{Type: "syslog"},
+			{Type: "file"},
+			{Type: "stdout", Rotation: RotationConfig{MaxSize: 1 << 20}},
+			{Type: "file", Path: "app.log", Rotation: RotationConfig{MaxBackups: 3}},
+			{Type: "file", Path: "app.log", Rotation: RotationConfig{MaxSize: -1}},
+		},
+		Sampling: map[string]uint64{"trace": 2, "debug": 0},
+	}
+
+	err := cfg.Validate()
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Expected *ConfigError, got %v", err)
+	}
+	want := []string{
+		"level",
+		"format",
+		"sinks[0].type",
+		"sinks[1].path",
+		"sinks[2].rotation",
+		"sinks[3].rotation.max_backups",
+		"sinks[4].path",
+		"sinks[4].rotation.max_size",
+		"sampling.debug",
+		"sampling.trace",
+	}
+	var got []string
+	for _, fe := range cerr.Errors {
+		got = append(got, fe.Path)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected problems:\n got %v\nwant %v", got, want)
+	}
+	if !strings.Contains(err.Error(), `sinks[0].type: unknown sink type "syslog"`) {
+		t.Errorf("Expected field paths in the message, got %q", err)
+	}
+}
+
+func TestValidateAcceptsValidConfig(t *testing.T) {
+	cfg := &Config{
+		Level: "info,db=debug",
+		Sinks: []SinkConfig{
+			{Type: "stderr", Format: "json"},
+			{Type: "file", Path: "app.log", Rotation: RotationConfig{MaxSize: 1 << 20, MaxBackups: 3}},
+		},
+		Sampling: map[string]uint64{"debug": 10},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected a valid config, got %v", err)
+	}
+}
+
+func TestLoadConfigValidates(t *testing.T) {
+	path := writeConfig(t, "logging.json", `{"sinks": [{"type": "file"}]}`)
+	_, err := LoadConfig(path)
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Errors[0].Path != "sinks[0].path" {
+		t.Errorf("Expected LoadConfig to validate, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Verifier is implemented by sinks that can check their connectivity
+// before the first entry is sent.
+type Verifier interface {
+	Verify(ctx context.Context) error
+}
+
+// VerifierFunc adapts an ordinary function to a Verifier.
+type VerifierFunc func(ctx context.Context) error
+
+// Verify calls f(ctx).
+func (f VerifierFunc) Verify(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Endpoint verifies a remote collector step by step: DNS resolution, TCP
+// connection, TLS handshake for https URLs and, by sending a HEAD request
+// with Header, authentication.
+type Endpoint struct {
+	// URL is the collector's address, such as https://logs.example.com/ingest.
+	URL string
+
+	// TLSConfig configures the handshake, for example as built by
+	// TLSOptions.Config. Defaults to the system roots.
+	TLSConfig *tls.Config
+
+	// Header is sent with the HEAD request, typically the credentials.
+	Header http.Header
+
+	// Client sends the HEAD request. Defaults to a client using TLSConfig,
+	// or http.DefaultClient without it.
+	Client *http.Client
+}
+
+// Verify implements Verifier. A 401 or 403 response fails authentication;
+// other statuses pass, since collectors rarely implement HEAD.
+func (e Endpoint) Verify(ctx context.Context) error {
+	u, err := url.Parse(e.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("logging: verify %s: invalid URL", e.URL)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	if _, err := net.DefaultResolver.LookupHost(ctx, u.Hostname()); err != nil {
+		return fmt.Errorf("logging: verify %s: dns: %w", u.Host, err)
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("logging: verify %s: tcp: %w", addr, err)
+	}
+	defer conn.Close()
+	if u.Scheme == "https" {
+		cfg := e.TLSConfig.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		if err := tls.Client(conn, cfg).HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("logging: verify %s: tls: %w", addr, err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, e.URL, nil)
+	if err != nil {
+		return fmt.Errorf("logging: verify %s: %w", e.URL, err)
+	}
+	for k, v := range e.Header {
+		req.Header[k] = v
+	}
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+		if e.TLSConfig != nil {
+			t := http.DefaultTransport.(*http.Transport).Clone()
+			t.TLSClientConfig = e.TLSConfig
+			client = &http.Client{Transport: t}
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("logging: verify %s: %w", e.URL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("logging: verify %s: auth: %s", e.URL, resp.Status)
+	}
+	return nil
+}
+
+// VerifiedSink attaches v to sink, so VerifySinks checks the collector sink
+// sends to.
+func VerifiedSink(sink BatchSink, v Verifier) BatchSink {
+	return &verifiedSink{BatchSink: sink, verifier: v}
+}
+
+type verifiedSink struct {
+	BatchSink
+	verifier Verifier
+}
+
+func (s *verifiedSink) Verify(ctx context.Context) error { return s.verifier.Verify(ctx) }
+func (s *verifiedSink) Close() error                     { return closeWriter(s.BatchSink) }
+
+// VerifyMode selects what VerifySinks does with an unreachable sink.
+type VerifyMode int
+
+// Verify modes.
+const (
+	// VerifyFailFast returns the errors, so startup can be aborted.
+	VerifyFailFast VerifyMode = iota
+	// VerifyWarn logs each error at Warning level and returns nil.
+	VerifyWarn
+)
+
+// VerifySinks checks every Verifier in the writer chain of l, including
+// those behind fan-out writers, so a bad endpoint is found at startup
+// rather than hours later:
+//
+//	sink := logging.VerifiedSink(collector, logging.Endpoint{URL: url, Header: auth})
+//	logger.SetWriter(logging.NewBatchWriter(sink, 500, time.Second))
+//	if err := logger.VerifySinks(ctx, logging.VerifyFailFast); err != nil {
+//		return err
+//	}
+func (l *Logger) VerifySinks(ctx context.Context, mode VerifyMode) error {
+	var errs []error
+	walkWriters(l.logger.Writer, func(w any) {
+		if v, ok := w.(Verifier); ok {
+			if err := v.Verify(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	if mode == VerifyWarn {
+		for _, err := range errs {
+			l.WithFields(Fields{"error": err.Error()}).Warning("logging: sink unreachable")
+		}
+		return nil
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+func TestEndpointVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	ctx := context.Background()
+
+	ok := Endpoint{URL: ts.URL, TLSConfig: tlsConfig, Client: ts.Client(), Header: http.Header{"Authorization": {"Bearer good"}}}
+	if err := ok.Verify(ctx); err != nil {
+		t.Errorf("Expected a reachable, authorized endpoint to verify, got %v", err)
+	}
+	bad := ok
+	bad.Header = http.Header{"Authorization": {"Bearer bad"}}
+	if err := bad.Verify(ctx); err == nil || !strings.Contains(err.Error(), "auth: 401") {
+		t.Errorf("Expected an auth failure, got %v", err)
+	}
+	untrusted := ok
+	untrusted.TLSConfig = nil
+	if err := untrusted.Verify(ctx); err == nil || !strings.Contains(err.Error(), "tls:") {
+		t.Errorf("Expected a TLS failure for an untrusted certificate, got %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	if err := (Endpoint{URL: "http://" + addr}).Verify(ctx); err == nil || !strings.Contains(err.Error(), "tcp:") {
+		t.Errorf("Expected a TCP failure for a closed port, got %v", err)
+	}
+}
+
+func TestVerifySinks(t *testing.T) {
+	broken := errors.New("logging: verify collector: dns: no such host")
+	sink := VerifiedSink(&collectSink{}, VerifierFunc(func(context.Context) error { return broken }))
+
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(NewBatchWriter(sink, 1, time.Second))
+	if err := logger.VerifySinks(context.Background(), VerifyFailFast); !errors.Is(err, broken) {
+		t.Errorf("Expected the verification error, got %v", err)
+	}
+
+	logger, buf := testLogger(LogLevelInfo)
+	logger.SetWriter(unverifiedWriter{Writer: logger.logger.Writer, err: broken})
+	if err := logger.VerifySinks(context.Background(), VerifyWarn); err != nil {
+		t.Errorf("VerifyWarn should not fail, got %v", err)
+	}
+	entries := decodeEntries(t, buf.String())
+	if len(entries) != 1 || entries[0]["level"] != "warn" || entries[0]["error"] != broken.Error() {
+		t.Errorf("Expected a warning for the unreachable sink, got %v", entries)
+	}
+}
+
+func TestVerifySinksMultiSink(t *testing.T) {
+	first := errors.New("logging: verify first: refused")
+	second := errors.New("logging: verify second: refused")
+	logger, _ := testLogger(LogLevelInfo)
+	logger.SetWriter(&log.MultiEntryWriter{
+		NewBatchWriter(VerifiedSink(&collectSink{}, VerifierFunc(func(context.Context) error { return first })), 1, time.Second),
+		&log.IOWriter{Writer: io.Discard},
+		unverifiedWriter{Writer: &log.IOWriter{Writer: io.Discard}, err: second},
+	})
+	err := logger.VerifySinks(context.Background(), VerifyFailFast)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("Expected every sink behind the fan-out verified, got %v", err)
+	}
+}
+
+// unverifiedWriter is a log.Writer whose verification fails with err.
+type unverifiedWriter struct {
+	log.Writer
+	err error
+}
+
+func (w unverifiedWriter) Verify(context.Context) error { return w.err }